@@ -0,0 +1,814 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+/*
+#include "osgeo_utils.h"
+*/
+import "C"
+import (
+	"encoding/csv"
+	"fmt"
+	"math"
+	"os"
+	"sort"
+	"strconv"
+	"unsafe"
+)
+
+// ============================================================================
+// 面积平差 - 图斑椭球面积按控制面积进行平差
+// ============================================================================
+
+// AreaAdjustRule 面积平差差值分配规则
+type AreaAdjustRule int
+
+const (
+	// AreaAdjustProportional 按图斑椭球面积比例分配差值（默认）
+	AreaAdjustProportional AreaAdjustRule = iota
+	// AreaAdjustWeighted 按权重字段比例分配差值
+	AreaAdjustWeighted
+	// AreaAdjustEqual 在图斑间平均分配差值
+	AreaAdjustEqual
+)
+
+func (r AreaAdjustRule) String() string {
+	switch r {
+	case AreaAdjustProportional:
+		return "按面积比例分配"
+	case AreaAdjustWeighted:
+		return "按权重字段分配"
+	case AreaAdjustEqual:
+		return "平均分配"
+	default:
+		return "未知规则"
+	}
+}
+
+// AreaAdjustmentOptions 面积平差配置
+type AreaAdjustmentOptions struct {
+	ControlIDField     string         // 控制面标识字段（如行政区代码），为空时使用控制面FID
+	ControlAreaField   string         // 控制面积字段，为空时按控制面几何计算椭球面积
+	ParcelControlField string         // 图斑中记录所属控制面标识的字段，为空时按图斑内点的空间位置归属
+	Rule               AreaAdjustRule // 差值分配规则
+	WeightField        string         // 权重字段（Rule为AreaAdjustWeighted时使用）
+	Decimals           *int           // 面积保留的小数位数，nil或负数时默认2（即0.01平方米），可设为0按整平方米平差
+	DensifyDegrees     float64        // 计算椭球面积前的边加密间距（度），默认0.0001，小于0表示不加密
+
+	EllipsoidAreaField string // 输出的平差前椭球面积字段名，默认ELLIP_AREA
+	AdjustedAreaField  string // 输出的平差后面积字段名，默认ADJ_AREA
+	ControlIDOutField  string // 输出的所属控制面标识字段名，默认CTRL_ID
+}
+
+// AreaAdjustmentControlReport 单个控制面的平差对账信息
+type AreaAdjustmentControlReport struct {
+	ControlID     string  // 控制面标识
+	ControlArea   float64 // 控制面积（已按精度取整）
+	ParcelCount   int     // 参与平差的图斑数量
+	ParcelAreaSum float64 // 平差前图斑椭球面积合计
+	Difference    float64 // 控制面积与图斑面积合计之差
+	RelativeError float64 // 相对差值（Difference / ControlArea）
+	AdjustedSum   float64 // 平差后图斑面积合计
+	MaxCorrection float64 // 单个图斑的最大改正数（绝对值）
+	Balanced      bool    // 平差后合计是否与控制面积一致
+}
+
+// AreaAdjustmentReport 面积平差对账报告
+type AreaAdjustmentReport struct {
+	Rule            AreaAdjustRule
+	Decimals        int
+	Controls        []AreaAdjustmentControlReport
+	UnassignedCount int     // 未归属任何控制面的图斑数量
+	UnassignedFIDs  []int64 // 未归属任何控制面的图斑FID
+}
+
+// AreaAdjustmentResult 面积平差结果
+type AreaAdjustmentResult struct {
+	OutputLayer *GDALLayer
+	Report      *AreaAdjustmentReport
+}
+
+// areaControl 控制面信息
+type areaControl struct {
+	id       string
+	geometry C.OGRGeometryH // 已转换到图斑坐标系的控制面几何
+	envelope C.OGREnvelope
+	area     float64
+}
+
+// areaParcel 参与平差的图斑信息
+type areaParcel struct {
+	fid      int64
+	control  int // 所属控制面索引，-1表示未归属
+	area     float64
+	weight   float64
+	adjusted float64
+}
+
+// AreaAdjustLayer 对图斑图层按控制面图层进行面积平差
+// 图斑椭球面积之和与所属控制面的椭球面积之差，按配置的规则分配到各图斑，
+// 并以最大余数法取整，保证平差后面积合计与控制面积严格相等。
+// 返回的图层复制了图斑的全部字段，并追加平差前面积、平差后面积和控制面标识字段。
+func AreaAdjustLayer(parcelLayer, controlLayer *GDALLayer, options *AreaAdjustmentOptions) (*AreaAdjustmentResult, error) {
+	if parcelLayer == nil || parcelLayer.layer == nil {
+		return nil, fmt.Errorf("图斑图层为空")
+	}
+	if controlLayer == nil || controlLayer.layer == nil {
+		return nil, fmt.Errorf("控制面图层为空")
+	}
+
+	opts := normalizeAreaAdjustmentOptions(options)
+	if opts.Rule == AreaAdjustWeighted && opts.WeightField == "" {
+		return nil, fmt.Errorf("按权重分配时必须指定权重字段")
+	}
+
+	parcelSRS := parcelLayer.GetSpatialRef()
+	if parcelSRS == nil {
+		return nil, fmt.Errorf("图斑图层没有定义空间参考系统")
+	}
+
+	controls, err := loadAreaControls(controlLayer, parcelSRS, opts)
+	if err != nil {
+		return nil, err
+	}
+	defer func() {
+		for _, ctrl := range controls {
+			if ctrl.geometry != nil {
+				C.OGR_G_DestroyGeometry(ctrl.geometry)
+			}
+		}
+	}()
+
+	controlIndex := make(map[string]int, len(controls))
+	for i, ctrl := range controls {
+		controlIndex[ctrl.id] = i
+	}
+
+	parcels, err := collectAreaParcels(parcelLayer, controls, controlIndex, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	report := &AreaAdjustmentReport{
+		Rule:     opts.Rule,
+		Decimals: *opts.Decimals,
+	}
+
+	// 按控制面分组并分配差值
+	groups := make([][]int, len(controls))
+	for i, parcel := range parcels {
+		if parcel.control < 0 {
+			report.UnassignedCount++
+			report.UnassignedFIDs = append(report.UnassignedFIDs, parcel.fid)
+			parcels[i].adjusted = roundToDecimals(parcel.area, *opts.Decimals)
+			continue
+		}
+		groups[parcel.control] = append(groups[parcel.control], i)
+	}
+
+	for ci, ctrl := range controls {
+		controlReport := distributeAreaDifference(parcels, groups[ci], ctrl.area, opts)
+		controlReport.ControlID = ctrl.id
+		report.Controls = append(report.Controls, controlReport)
+	}
+
+	outputLayer, err := writeAreaAdjustmentLayer(parcelLayer, parcels, controls, opts)
+	if err != nil {
+		return nil, err
+	}
+
+	return &AreaAdjustmentResult{
+		OutputLayer: outputLayer,
+		Report:      report,
+	}, nil
+}
+
+// normalizeAreaAdjustmentOptions 填充面积平差配置的默认值
+func normalizeAreaAdjustmentOptions(options *AreaAdjustmentOptions) AreaAdjustmentOptions {
+	opts := AreaAdjustmentOptions{}
+	if options != nil {
+		opts = *options
+	}
+	decimals := 2
+	if opts.Decimals != nil && *opts.Decimals >= 0 {
+		decimals = *opts.Decimals
+	}
+	opts.Decimals = &decimals
+	if opts.DensifyDegrees == 0 {
+		opts.DensifyDegrees = 0.0001
+	}
+	if opts.EllipsoidAreaField == "" {
+		opts.EllipsoidAreaField = "ELLIP_AREA"
+	}
+	if opts.AdjustedAreaField == "" {
+		opts.AdjustedAreaField = "ADJ_AREA"
+	}
+	if opts.ControlIDOutField == "" {
+		opts.ControlIDOutField = "CTRL_ID"
+	}
+	return opts
+}
+
+// loadAreaControls 读取控制面及其控制面积
+func loadAreaControls(controlLayer *GDALLayer, parcelSRS C.OGRSpatialReferenceH, opts AreaAdjustmentOptions) ([]*areaControl, error) {
+	controlSRS := controlLayer.GetSpatialRef()
+	if controlSRS == nil && opts.ControlAreaField == "" {
+		return nil, fmt.Errorf("控制面图层没有定义空间参考系统，无法计算椭球面积")
+	}
+
+	var transform C.OGRCoordinateTransformationH
+	if controlSRS != nil && C.OSRIsSame(controlSRS, parcelSRS) == 0 {
+		transform = C.OCTNewCoordinateTransformation(controlSRS, parcelSRS)
+		if transform == nil {
+			return nil, fmt.Errorf("无法创建控制面到图斑的坐标转换器")
+		}
+		defer C.OCTDestroyCoordinateTransformation(transform)
+	}
+
+	defn := controlLayer.GetLayerDefn()
+	idIndex := -1
+	if opts.ControlIDField != "" {
+		idIndex = layerFieldIndex(defn, opts.ControlIDField)
+		if idIndex < 0 {
+			return nil, fmt.Errorf("控制面字段不存在: %s", opts.ControlIDField)
+		}
+	}
+	areaIndex := -1
+	if opts.ControlAreaField != "" {
+		areaIndex = layerFieldIndex(defn, opts.ControlAreaField)
+		if areaIndex < 0 {
+			return nil, fmt.Errorf("控制面积字段不存在: %s", opts.ControlAreaField)
+		}
+	}
+
+	var controls []*areaControl
+	controlLayer.ResetReading()
+	for {
+		feature := controlLayer.GetNextFeatureRow()
+		if feature == nil {
+			break
+		}
+
+		geometry := C.OGR_F_GetGeometryRef(feature)
+		if geometry == nil {
+			C.OGR_F_Destroy(feature)
+			continue
+		}
+
+		ctrl := &areaControl{}
+		if idIndex >= 0 {
+			ctrl.id = C.GoString(C.OGR_F_GetFieldAsString(feature, C.int(idIndex)))
+		} else {
+			ctrl.id = strconv.FormatInt(int64(C.OGR_F_GetFID(feature)), 10)
+		}
+
+		if areaIndex >= 0 {
+			ctrl.area = float64(C.OGR_F_GetFieldAsDouble(feature, C.int(areaIndex)))
+		} else {
+			area, err := ellipsoidalAreaWithDensify(geometry, controlSRS, opts.DensifyDegrees)
+			if err != nil {
+				C.OGR_F_Destroy(feature)
+				controlLayer.ResetReading()
+				return nil, fmt.Errorf("计算控制面 %s 椭球面积失败: %v", ctrl.id, err)
+			}
+			ctrl.area = area
+		}
+		ctrl.area = roundToDecimals(ctrl.area, *opts.Decimals)
+
+		ctrl.geometry = C.OGR_G_Clone(geometry)
+		if transform != nil && C.OGR_G_Transform(ctrl.geometry, transform) != C.OGRERR_NONE {
+			C.OGR_G_DestroyGeometry(ctrl.geometry)
+			C.OGR_F_Destroy(feature)
+			controlLayer.ResetReading()
+			return nil, fmt.Errorf("控制面 %s 坐标转换失败", ctrl.id)
+		}
+		C.OGR_G_GetEnvelope(ctrl.geometry, &ctrl.envelope)
+
+		controls = append(controls, ctrl)
+		C.OGR_F_Destroy(feature)
+	}
+	controlLayer.ResetReading()
+
+	if len(controls) == 0 {
+		return nil, fmt.Errorf("控制面图层没有有效要素")
+	}
+	return controls, nil
+}
+
+// collectAreaParcels 计算图斑椭球面积并确定所属控制面
+func collectAreaParcels(parcelLayer *GDALLayer, controls []*areaControl, controlIndex map[string]int, opts AreaAdjustmentOptions) ([]areaParcel, error) {
+	defn := parcelLayer.GetLayerDefn()
+	srs := parcelLayer.GetSpatialRef()
+
+	linkIndex := -1
+	if opts.ParcelControlField != "" {
+		linkIndex = layerFieldIndex(defn, opts.ParcelControlField)
+		if linkIndex < 0 {
+			return nil, fmt.Errorf("图斑字段不存在: %s", opts.ParcelControlField)
+		}
+	}
+	weightIndex := -1
+	if opts.Rule == AreaAdjustWeighted {
+		weightIndex = layerFieldIndex(defn, opts.WeightField)
+		if weightIndex < 0 {
+			return nil, fmt.Errorf("权重字段不存在: %s", opts.WeightField)
+		}
+	}
+
+	var parcels []areaParcel
+	parcelLayer.ResetReading()
+	for {
+		feature := parcelLayer.GetNextFeatureRow()
+		if feature == nil {
+			break
+		}
+
+		parcel := areaParcel{
+			fid:     int64(C.OGR_F_GetFID(feature)),
+			control: -1,
+			weight:  1,
+		}
+
+		geometry := C.OGR_F_GetGeometryRef(feature)
+		if geometry != nil {
+			area, err := ellipsoidalAreaWithDensify(geometry, srs, opts.DensifyDegrees)
+			if err != nil {
+				C.OGR_F_Destroy(feature)
+				parcelLayer.ResetReading()
+				return nil, fmt.Errorf("计算图斑 %d 椭球面积失败: %v", parcel.fid, err)
+			}
+			parcel.area = area
+		}
+
+		if linkIndex >= 0 {
+			if C.OGR_F_IsFieldSet(feature, C.int(linkIndex)) != 0 {
+				key := C.GoString(C.OGR_F_GetFieldAsString(feature, C.int(linkIndex)))
+				if idx, ok := controlIndex[key]; ok {
+					parcel.control = idx
+				}
+			}
+		} else if geometry != nil {
+			parcel.control = locateAreaControl(geometry, controls)
+		}
+
+		if weightIndex >= 0 {
+			parcel.weight = float64(C.OGR_F_GetFieldAsDouble(feature, C.int(weightIndex)))
+		}
+
+		parcels = append(parcels, parcel)
+		C.OGR_F_Destroy(feature)
+	}
+	parcelLayer.ResetReading()
+
+	return parcels, nil
+}
+
+// locateAreaControl 使用图斑内点判断图斑所属的控制面
+func locateAreaControl(geometry C.OGRGeometryH, controls []*areaControl) int {
+	point := C.OGR_G_PointOnSurface(geometry)
+	if point == nil {
+		return -1
+	}
+	defer C.OGR_G_DestroyGeometry(point)
+
+	x := float64(C.OGR_G_GetX(point, 0))
+	y := float64(C.OGR_G_GetY(point, 0))
+
+	for i, ctrl := range controls {
+		env := ctrl.envelope
+		if x < float64(env.MinX) || x > float64(env.MaxX) || y < float64(env.MinY) || y > float64(env.MaxY) {
+			continue
+		}
+		if C.OGR_G_Contains(ctrl.geometry, point) != 0 {
+			return i
+		}
+	}
+	return -1
+}
+
+// distributeAreaDifference 将控制面积差值分配到同组图斑
+func distributeAreaDifference(parcels []areaParcel, members []int, controlArea float64, opts AreaAdjustmentOptions) AreaAdjustmentControlReport {
+	report := AreaAdjustmentControlReport{
+		ControlArea: controlArea,
+		ParcelCount: len(members),
+	}
+	if len(members) == 0 {
+		report.Difference = controlArea
+		if controlArea != 0 {
+			report.RelativeError = 1
+		}
+		return report
+	}
+
+	weights := make([]float64, len(members))
+	weightSum := 0.0
+	for i, idx := range members {
+		report.ParcelAreaSum += parcels[idx].area
+		switch opts.Rule {
+		case AreaAdjustEqual:
+			weights[i] = 1
+		case AreaAdjustWeighted:
+			weights[i] = math.Max(parcels[idx].weight, 0)
+		default:
+			weights[i] = parcels[idx].area
+		}
+		weightSum += weights[i]
+	}
+	// 权重全部为0时退化为平均分配
+	if weightSum <= 0 {
+		for i := range weights {
+			weights[i] = 1
+		}
+		weightSum = float64(len(weights))
+	}
+
+	report.Difference = controlArea - report.ParcelAreaSum
+	if controlArea != 0 {
+		report.RelativeError = report.Difference / controlArea
+	}
+
+	raw := make([]float64, len(members))
+	for i, idx := range members {
+		raw[i] = parcels[idx].area + report.Difference*weights[i]/weightSum
+	}
+
+	rounded := largestRemainderRound(raw, controlArea, *opts.Decimals)
+	for i, idx := range members {
+		parcels[idx].adjusted = rounded[i]
+		report.AdjustedSum += rounded[i]
+		correction := math.Abs(rounded[i] - parcels[idx].area)
+		if correction > report.MaxCorrection {
+			report.MaxCorrection = correction
+		}
+	}
+	report.AdjustedSum = roundToDecimals(report.AdjustedSum, *opts.Decimals)
+	report.Balanced = report.AdjustedSum == controlArea
+
+	return report
+}
+
+// largestRemainderRound 按最大余数法取整，使取整结果之和等于目标值
+func largestRemainderRound(values []float64, target float64, decimals int) []float64 {
+	scale := math.Pow(10, float64(decimals))
+	targetUnits := int64(math.Round(target * scale))
+
+	units := make([]int64, len(values))
+	remainders := make([]float64, len(values))
+	var sumUnits int64
+	for i, v := range values {
+		scaled := v * scale
+		floor := math.Floor(scaled)
+		units[i] = int64(floor)
+		remainders[i] = scaled - floor
+		sumUnits += units[i]
+	}
+
+	order := make([]int, len(values))
+	for i := range order {
+		order[i] = i
+	}
+	missing := targetUnits - sumUnits
+	if missing > 0 {
+		// 余数大的优先进位
+		sort.SliceStable(order, func(a, b int) bool {
+			return remainders[order[a]] > remainders[order[b]]
+		})
+		for i := int64(0); i < missing; i++ {
+			units[order[i%int64(len(order))]]++
+		}
+	} else if missing < 0 {
+		// 余数小的优先退位
+		sort.SliceStable(order, func(a, b int) bool {
+			return remainders[order[a]] < remainders[order[b]]
+		})
+		for i := int64(0); i < -missing; i++ {
+			units[order[i%int64(len(order))]]--
+		}
+	}
+
+	result := make([]float64, len(values))
+	for i, u := range units {
+		result[i] = float64(u) / scale
+	}
+	return result
+}
+
+// roundToDecimals 按指定小数位数四舍五入
+func roundToDecimals(value float64, decimals int) float64 {
+	scale := math.Pow(10, float64(decimals))
+	return math.Round(value*scale) / scale
+}
+
+// layerFieldIndex 获取图层定义中的字段索引
+func layerFieldIndex(defn C.OGRFeatureDefnH, fieldName string) int {
+	cFieldName := C.CString(fieldName)
+	defer C.free(unsafe.Pointer(cFieldName))
+	return int(C.OGR_FD_GetFieldIndex(defn, cFieldName))
+}
+
+// writeAreaAdjustmentLayer 输出带平差面积字段的结果图层
+func writeAreaAdjustmentLayer(parcelLayer *GDALLayer, parcels []areaParcel, controls []*areaControl, opts AreaAdjustmentOptions) (*GDALLayer, error) {
+	sourceDefn := parcelLayer.GetLayerDefn()
+	geomType := C.OGR_FD_GetGeomType(sourceDefn)
+
+	resultLayer, err := newMemoryResultLayer("area_adjust_result", "area_adjusted", parcelLayer.GetSpatialRef(), geomType)
+	if err != nil {
+		return nil, err
+	}
+	copyLayerFieldDefns(sourceDefn, resultLayer.layer)
+
+	for _, name := range []string{opts.EllipsoidAreaField, opts.AdjustedAreaField} {
+		cName := C.CString(name)
+		fieldDefn := C.OGR_Fld_Create(cName, C.OFTReal)
+		C.free(unsafe.Pointer(cName))
+		C.OGR_Fld_SetWidth(fieldDefn, 18)
+		C.OGR_Fld_SetPrecision(fieldDefn, C.int(*opts.Decimals))
+		result := C.OGR_L_CreateField(resultLayer.layer, fieldDefn, C.int(1))
+		C.OGR_Fld_Destroy(fieldDefn)
+		if result != C.OGRERR_NONE {
+			resultLayer.Close()
+			return nil, fmt.Errorf("创建字段 %s 失败", name)
+		}
+	}
+	cCtrlName := C.CString(opts.ControlIDOutField)
+	ok := C.addFieldToLayer(resultLayer.layer, cCtrlName, C.OFTString)
+	C.free(unsafe.Pointer(cCtrlName))
+	if ok == 0 {
+		resultLayer.Close()
+		return nil, fmt.Errorf("创建字段 %s 失败", opts.ControlIDOutField)
+	}
+
+	resultDefn := resultLayer.GetLayerDefn()
+	ellipIndex := C.int(layerFieldIndex(resultDefn, opts.EllipsoidAreaField))
+	adjustedIndex := C.int(layerFieldIndex(resultDefn, opts.AdjustedAreaField))
+	ctrlIndex := C.int(layerFieldIndex(resultDefn, opts.ControlIDOutField))
+
+	// 与collectAreaParcels保持相同的读取顺序
+	parcelLayer.ResetReading()
+	i := 0
+	for {
+		feature := parcelLayer.GetNextFeatureRow()
+		if feature == nil {
+			break
+		}
+		if i >= len(parcels) {
+			C.OGR_F_Destroy(feature)
+			break
+		}
+		parcel := parcels[i]
+		i++
+
+		newFeature := C.OGR_F_Create(resultDefn)
+		if newFeature != nil {
+			geometry := C.OGR_F_GetGeometryRef(feature)
+			if geometry != nil {
+				C.OGR_F_SetGeometry(newFeature, geometry)
+			}
+			copyFeatureAttributes(feature, newFeature, sourceDefn, resultDefn)
+
+			C.OGR_F_SetFieldDouble(newFeature, ellipIndex, C.double(roundToDecimals(parcel.area, *opts.Decimals)))
+			C.OGR_F_SetFieldDouble(newFeature, adjustedIndex, C.double(parcel.adjusted))
+			if parcel.control >= 0 {
+				cID := C.CString(controls[parcel.control].id)
+				C.OGR_F_SetFieldString(newFeature, ctrlIndex, cID)
+				C.free(unsafe.Pointer(cID))
+			}
+
+			C.OGR_L_CreateFeature(resultLayer.layer, newFeature)
+			C.OGR_F_Destroy(newFeature)
+		}
+		C.OGR_F_Destroy(feature)
+	}
+	parcelLayer.ResetReading()
+
+	return resultLayer, nil
+}
+
+// ============================================================================
+// 椭球面积计算
+// ============================================================================
+
+// ellipsoidalArea 计算几何体在其坐标系所在椭球面上的面积（平方米）
+// 投影坐标先反算到对应的地理坐标系，再通过等积圆柱投影（以authalic纬度）计算，
+// 适用于CGCS2000、西安80、北京54等任意椭球。对外接口为 (*Geometry).EllipsoidalArea。
+func ellipsoidalArea(geometry C.OGRGeometryH, srs C.OGRSpatialReferenceH) (float64, error) {
+	return ellipsoidalAreaWithDensify(geometry, srs, 0.0001)
+}
+
+// ellipsoidalAreaWithDensify 计算椭球面积，densifyDegrees为经纬度下的边加密间距
+func ellipsoidalAreaWithDensify(geometry C.OGRGeometryH, srs C.OGRSpatialReferenceH, densifyDegrees float64) (float64, error) {
+	if geometry == nil {
+		return 0, fmt.Errorf("几何体为空")
+	}
+	if srs == nil {
+		return 0, fmt.Errorf("空间参考为空")
+	}
+
+	geogSRS := C.OSRCloneGeogCS(srs)
+	if geogSRS == nil {
+		return 0, fmt.Errorf("无法获取地理坐标系")
+	}
+	defer C.OSRDestroySpatialReference(geogSRS)
+	C.OSRSetAxisMappingStrategy(geogSRS, C.OAMS_TRADITIONAL_GIS_ORDER)
+
+	var ogrErr C.OGRErr
+	semiMajor := float64(C.OSRGetSemiMajor(geogSRS, &ogrErr))
+	if ogrErr != C.OGRERR_NONE || semiMajor <= 0 {
+		return 0, fmt.Errorf("无法获取椭球长半轴")
+	}
+	invFlattening := float64(C.OSRGetInvFlattening(geogSRS, &ogrErr))
+	if ogrErr != C.OGRERR_NONE {
+		return 0, fmt.Errorf("无法获取椭球扁率")
+	}
+
+	work := C.OGR_G_Clone(geometry)
+	if work == nil {
+		return 0, fmt.Errorf("克隆几何体失败")
+	}
+	defer C.OGR_G_DestroyGeometry(work)
+
+	if C.OSRIsGeographic(srs) == 0 {
+		transform := C.OCTNewCoordinateTransformation(srs, geogSRS)
+		if transform == nil {
+			return 0, fmt.Errorf("无法创建到地理坐标系的坐标转换器")
+		}
+		result := C.OGR_G_Transform(work, transform)
+		C.OCTDestroyCoordinateTransformation(transform)
+		if result != C.OGRERR_NONE {
+			return 0, fmt.Errorf("坐标转换失败")
+		}
+	}
+
+	if densifyDegrees > 0 {
+		C.OGR_G_Segmentize(work, C.double(densifyDegrees))
+	}
+
+	flattening := 0.0
+	if invFlattening > 0 {
+		flattening = 1 / invFlattening
+	}
+	ellipsoid := newAuthalicEllipsoid(semiMajor, flattening)
+	return math.Abs(ellipsoid.geometryArea(work)), nil
+}
+
+// authalicEllipsoid 椭球等积投影计算参数
+type authalicEllipsoid struct {
+	a  float64 // 长半轴
+	e  float64 // 第一偏心率
+	e2 float64 // 第一偏心率平方
+}
+
+func newAuthalicEllipsoid(semiMajor, flattening float64) authalicEllipsoid {
+	e2 := flattening * (2 - flattening)
+	return authalicEllipsoid{a: semiMajor, e: math.Sqrt(e2), e2: e2}
+}
+
+// q 计算纬度phi（弧度）对应的等积投影q值
+func (el authalicEllipsoid) q(phi float64) float64 {
+	sinPhi := math.Sin(phi)
+	if el.e == 0 {
+		return 2 * sinPhi
+	}
+	esin := el.e * sinPhi
+	return (1 - el.e2) * (sinPhi/(1-esin*esin) - math.Log((1-esin)/(1+esin))/(2*el.e))
+}
+
+// ringArea 计算经纬度环的椭球面积（带符号）
+func (el authalicEllipsoid) ringArea(ring C.OGRGeometryH) float64 {
+	count := int(C.OGR_G_GetPointCount(ring))
+	if count < 3 {
+		return 0
+	}
+
+	deg := math.Pi / 180
+	lon0 := float64(C.OGR_G_GetX(ring, 0)) * deg
+	y0 := el.q(float64(C.OGR_G_GetY(ring, 0))*deg) * el.a / 2
+
+	xs := make([]float64, count)
+	ys := make([]float64, count)
+	for i := 0; i < count; i++ {
+		xs[i] = el.a * (float64(C.OGR_G_GetX(ring, C.int(i)))*deg - lon0)
+		ys[i] = el.q(float64(C.OGR_G_GetY(ring, C.int(i)))*deg)*el.a/2 - y0
+	}
+
+	sum := 0.0
+	for i := 0; i < count; i++ {
+		j := (i + 1) % count
+		sum += xs[i]*ys[j] - xs[j]*ys[i]
+	}
+	return sum / 2
+}
+
+// geometryArea 计算面/多面几何体的椭球面积，内环面积被扣除
+func (el authalicEllipsoid) geometryArea(geometry C.OGRGeometryH) float64 {
+	switch C.OGR_GT_Flatten(C.OGR_G_GetGeometryType(geometry)) {
+	case C.wkbPolygon:
+		area := 0.0
+		ringCount := int(C.OGR_G_GetGeometryCount(geometry))
+		for i := 0; i < ringCount; i++ {
+			ringArea := math.Abs(el.ringArea(C.OGR_G_GetGeometryRef(geometry, C.int(i))))
+			if i == 0 {
+				area += ringArea
+			} else {
+				area -= ringArea
+			}
+		}
+		return area
+	case C.wkbMultiPolygon, C.wkbGeometryCollection:
+		area := 0.0
+		count := int(C.OGR_G_GetGeometryCount(geometry))
+		for i := 0; i < count; i++ {
+			area += el.geometryArea(C.OGR_G_GetGeometryRef(geometry, C.int(i)))
+		}
+		return area
+	default:
+		return 0
+	}
+}
+
+// ============================================================================
+// 对账报告输出
+// ============================================================================
+
+// PrintReport 打印面积平差对账报告
+func (r *AreaAdjustmentReport) PrintReport() {
+	fmt.Printf("\n=== 面积平差对账报告 ===\n")
+	fmt.Printf("分配规则: %s，保留小数位: %d\n", r.Rule, r.Decimals)
+	fmt.Printf("%-16s %8s %18s %18s %14s %18s %6s\n",
+		"控制面", "图斑数", "控制面积", "图斑面积合计", "差值", "平差后合计", "平衡")
+	for _, c := range r.Controls {
+		balanced := "是"
+		if !c.Balanced {
+			balanced = "否"
+		}
+		fmt.Printf("%-16s %8d %18.*f %18.4f %14.4f %18.*f %6s\n",
+			c.ControlID, c.ParcelCount, r.Decimals, c.ControlArea, c.ParcelAreaSum,
+			c.Difference, r.Decimals, c.AdjustedSum, balanced)
+	}
+	if r.UnassignedCount > 0 {
+		fmt.Printf("未归属控制面的图斑: %d 个\n", r.UnassignedCount)
+	}
+	fmt.Printf("========================\n\n")
+}
+
+// WriteCSV 将对账报告写出为CSV文件（UTF-8带BOM，便于Excel直接打开）
+func (r *AreaAdjustmentReport) WriteCSV(filePath string) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("创建报告文件失败: %v", err)
+	}
+	defer file.Close()
+
+	if _, err := file.Write([]byte{0xEF, 0xBB, 0xBF}); err != nil {
+		return fmt.Errorf("写入报告文件失败: %v", err)
+	}
+
+	writer := csv.NewWriter(file)
+	header := []string{"控制面", "图斑数", "控制面积", "图斑面积合计", "差值", "相对差值", "平差后合计", "最大改正数", "平衡"}
+	if err := writer.Write(header); err != nil {
+		return fmt.Errorf("写入报告文件失败: %v", err)
+	}
+
+	format := func(v float64) string {
+		return strconv.FormatFloat(v, 'f', r.Decimals, 64)
+	}
+	for _, c := range r.Controls {
+		record := []string{
+			c.ControlID,
+			strconv.Itoa(c.ParcelCount),
+			format(c.ControlArea),
+			strconv.FormatFloat(c.ParcelAreaSum, 'f', 4, 64),
+			strconv.FormatFloat(c.Difference, 'f', 4, 64),
+			strconv.FormatFloat(c.RelativeError, 'e', 6, 64),
+			format(c.AdjustedSum),
+			strconv.FormatFloat(c.MaxCorrection, 'f', 4, 64),
+			strconv.FormatBool(c.Balanced),
+		}
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("写入报告文件失败: %v", err)
+		}
+	}
+
+	for _, fid := range r.UnassignedFIDs {
+		if err := writer.Write([]string{"未归属", strconv.FormatInt(fid, 10)}); err != nil {
+			return fmt.Errorf("写入报告文件失败: %v", err)
+		}
+	}
+
+	writer.Flush()
+	return writer.Error()
+}
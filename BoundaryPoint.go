@@ -0,0 +1,319 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+/*
+#include "osgeo_utils.h"
+*/
+import "C"
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// ============================================================================
+// 界址点提取
+// ============================================================================
+
+// BoundaryPoint 界址点
+// 按测量习惯，X为纵坐标（北），Y为横坐标（东）
+type BoundaryPoint struct {
+	Number  int      // 界址点序号（从1开始）
+	Name    string   // 界址点号，如 J1
+	X       float64  // 纵坐标（北）
+	Y       float64  // 横坐标（东）
+	Parcels []string // 共用该界址点的地块编号
+}
+
+// BoundaryRing 地块的一个界址圈，点序不含闭合点
+type BoundaryRing struct {
+	RingNumber int  // 圈号（从1开始）
+	Outer      bool // 是否为外圈
+	Points     []*BoundaryPoint
+}
+
+// ParcelBoundary 单个地块的界址点序列
+type ParcelBoundary struct {
+	ParcelID string
+	FID      int64
+	Rings    []*BoundaryRing
+}
+
+// BoundaryPointOptions 界址点提取选项
+type BoundaryPointOptions struct {
+	PointPrefix   string  // 界址点号前缀，默认 "J"
+	Tolerance     float64 // 相邻地块界址点合并容差（图层单位），默认0.001
+	ParcelIDField string  // 地块编号字段，为空时使用FID
+}
+
+// BoundaryPointResult 界址点提取结果
+type BoundaryPointResult struct {
+	Points  []*BoundaryPoint
+	Parcels []*ParcelBoundary
+	Layer   *GDALLayer // 界址点图层（JZDH、XH、X、Y、DKS、DKBH字段）
+}
+
+// boundaryPointRegistry 按容差合并重合界址点并统一编号
+type boundaryPointRegistry struct {
+	prefix    string
+	tolerance float64
+	index     map[[2]int64][]*BoundaryPoint // 以容差为边长的网格 -> 网格内的界址点
+	points    []*BoundaryPoint
+}
+
+func newBoundaryPointRegistry(prefix string, tolerance float64) *boundaryPointRegistry {
+	if prefix == "" {
+		prefix = "J"
+	}
+	if tolerance <= 0 {
+		tolerance = 0.001
+	}
+	return &boundaryPointRegistry{
+		prefix:    prefix,
+		tolerance: tolerance,
+		index:     make(map[[2]int64][]*BoundaryPoint),
+	}
+}
+
+// register 登记坐标(x东, y北)，容差范围内已有界址点时直接复用距离最近的点
+// 网格边长等于容差，容差范围内的点只可能位于本网格及相邻的8个网格中
+func (r *boundaryPointRegistry) register(x, y float64, parcelID string) *BoundaryPoint {
+	key := [2]int64{int64(math.Floor(x / r.tolerance)), int64(math.Floor(y / r.tolerance))}
+
+	var point *BoundaryPoint
+	nearest := r.tolerance
+	for dx := int64(-1); dx <= 1; dx++ {
+		for dy := int64(-1); dy <= 1; dy++ {
+			for _, candidate := range r.index[[2]int64{key[0] + dx, key[1] + dy}] {
+				// 界址点X为北坐标、Y为东坐标
+				if distance := math.Hypot(candidate.Y-x, candidate.X-y); distance <= nearest {
+					point, nearest = candidate, distance
+				}
+			}
+		}
+	}
+	if point == nil {
+		point = &BoundaryPoint{
+			Number: len(r.points) + 1,
+			X:      y,
+			Y:      x,
+		}
+		point.Name = r.prefix + strconv.Itoa(point.Number)
+		r.index[key] = append(r.index[key], point)
+		r.points = append(r.points, point)
+	}
+	if len(point.Parcels) == 0 || point.Parcels[len(point.Parcels)-1] != parcelID {
+		point.Parcels = append(point.Parcels, parcelID)
+	}
+	return point
+}
+
+// parcelRings 提取面几何的界址圈：外圈顺时针、内圈逆时针，均从西北角起算
+func (r *boundaryPointRegistry) parcelRings(geometry C.OGRGeometryH, parcelID string) []*BoundaryRing {
+	var polygons []C.OGRGeometryH
+	switch C.OGR_GT_Flatten(C.OGR_G_GetGeometryType(geometry)) {
+	case C.wkbPolygon:
+		polygons = append(polygons, geometry)
+	case C.wkbMultiPolygon:
+		for i := 0; i < int(C.OGR_G_GetGeometryCount(geometry)); i++ {
+			polygons = append(polygons, C.OGR_G_GetGeometryRef(geometry, C.int(i)))
+		}
+	default:
+		return nil
+	}
+
+	var rings []*BoundaryRing
+	for _, polygon := range polygons {
+		ringCount := int(C.OGR_G_GetGeometryCount(polygon))
+		for i := 0; i < ringCount; i++ {
+			coords := ringCoordinates(C.OGR_G_GetGeometryRef(polygon, C.int(i)))
+			if len(coords) < 3 {
+				continue
+			}
+			outer := i == 0
+			coords = orientRingFromNorthWest(coords, outer)
+
+			ring := &BoundaryRing{RingNumber: len(rings) + 1, Outer: outer}
+			for _, c := range coords {
+				ring.Points = append(ring.Points, r.register(c[0], c[1], parcelID))
+			}
+			rings = append(rings, ring)
+		}
+	}
+	return rings
+}
+
+// ringCoordinates 读取环的坐标，去除闭合点和连续重复点
+func ringCoordinates(ring C.OGRGeometryH) [][2]float64 {
+	count := int(C.OGR_G_GetPointCount(ring))
+	coords := make([][2]float64, 0, count)
+	for i := 0; i < count; i++ {
+		c := [2]float64{float64(C.OGR_G_GetX(ring, C.int(i))), float64(C.OGR_G_GetY(ring, C.int(i)))}
+		if len(coords) > 0 && coords[len(coords)-1] == c {
+			continue
+		}
+		coords = append(coords, c)
+	}
+	if len(coords) > 1 && coords[0] == coords[len(coords)-1] {
+		coords = coords[:len(coords)-1]
+	}
+	return coords
+}
+
+// orientRingFromNorthWest 调整环方向（clockwise为true时顺时针），并以最靠近外包矩形西北角的顶点为起点
+func orientRingFromNorthWest(coords [][2]float64, clockwise bool) [][2]float64 {
+	signedArea := 0.0
+	minX, maxY := math.Inf(1), math.Inf(-1)
+	for i, c := range coords {
+		next := coords[(i+1)%len(coords)]
+		signedArea += c[0]*next[1] - next[0]*c[1]
+		minX = math.Min(minX, c[0])
+		maxY = math.Max(maxY, c[1])
+	}
+
+	oriented := make([][2]float64, len(coords))
+	copy(oriented, coords)
+	if (signedArea > 0) == clockwise {
+		for i, j := 0, len(oriented)-1; i < j; i, j = i+1, j-1 {
+			oriented[i], oriented[j] = oriented[j], oriented[i]
+		}
+	}
+
+	start := 0
+	best := math.Inf(1)
+	for i, c := range oriented {
+		d := math.Hypot(c[0]-minX, c[1]-maxY)
+		if d < best {
+			best, start = d, i
+		}
+	}
+	return append(oriented[start:], oriented[:start]...)
+}
+
+// featureParcelID 获取要素的地块编号
+func featureParcelID(feature C.OGRFeatureH, fieldIndex int) string {
+	if fieldIndex >= 0 && C.OGR_F_IsFieldSetAndNotNull(feature, C.int(fieldIndex)) != 0 {
+		return C.GoString(C.OGR_F_GetFieldAsString(feature, C.int(fieldIndex)))
+	}
+	return strconv.FormatInt(int64(C.OGR_F_GetFID(feature)), 10)
+}
+
+// ExtractBoundaryPoints 从地块面图层提取界址点
+// 每个地块外圈按顺时针、从西北角开始编号，相邻地块的重合界址点只编号一次
+func ExtractBoundaryPoints(parcelLayer *GDALLayer, options *BoundaryPointOptions) (*BoundaryPointResult, error) {
+	if parcelLayer == nil || parcelLayer.layer == nil {
+		return nil, fmt.Errorf("地块图层为空")
+	}
+	if options == nil {
+		options = &BoundaryPointOptions{}
+	}
+
+	defn := parcelLayer.GetLayerDefn()
+	idIndex := -1
+	if options.ParcelIDField != "" {
+		idIndex = layerFieldIndex(defn, options.ParcelIDField)
+		if idIndex < 0 {
+			return nil, fmt.Errorf("地块编号字段不存在: %s", options.ParcelIDField)
+		}
+	}
+
+	registry := newBoundaryPointRegistry(options.PointPrefix, options.Tolerance)
+	result := &BoundaryPointResult{}
+
+	parcelLayer.ResetReading()
+	for {
+		feature := parcelLayer.GetNextFeatureRow()
+		if feature == nil {
+			break
+		}
+		geometry := C.OGR_F_GetGeometryRef(feature)
+		if geometry != nil && C.OGR_G_IsEmpty(geometry) == 0 {
+			parcelID := featureParcelID(feature, idIndex)
+			rings := registry.parcelRings(geometry, parcelID)
+			if len(rings) > 0 {
+				result.Parcels = append(result.Parcels, &ParcelBoundary{
+					ParcelID: parcelID,
+					FID:      int64(C.OGR_F_GetFID(feature)),
+					Rings:    rings,
+				})
+			}
+		}
+		C.OGR_F_Destroy(feature)
+	}
+	parcelLayer.ResetReading()
+
+	result.Points = registry.points
+	layer, err := createBoundaryPointLayer(registry.points, parcelLayer.GetSpatialRef())
+	if err != nil {
+		return nil, err
+	}
+	result.Layer = layer
+	return result, nil
+}
+
+// createBoundaryPointLayer 生成界址点图层
+func createBoundaryPointLayer(points []*BoundaryPoint, srs C.OGRSpatialReferenceH) (*GDALLayer, error) {
+	resultLayer, err := newMemoryResultLayer("boundary_points", "boundary_points", srs, C.wkbPoint)
+	if err != nil {
+		return nil, err
+	}
+
+	fields := []struct {
+		name      string
+		fieldType C.OGRFieldType
+	}{
+		{"JZDH", C.OFTString},
+		{"XH", C.OFTInteger},
+		{"X", C.OFTReal},
+		{"Y", C.OFTReal},
+		{"DKS", C.OFTInteger},
+		{"DKBH", C.OFTString},
+	}
+	for _, f := range fields {
+		cName := C.CString(f.name)
+		C.addFieldToLayer(resultLayer.layer, cName, f.fieldType)
+		C.free(unsafe.Pointer(cName))
+	}
+
+	defn := resultLayer.GetLayerDefn()
+	for _, point := range points {
+		feature := C.OGR_F_Create(defn)
+
+		geometry := C.OGR_G_CreateGeometry(C.wkbPoint)
+		C.OGR_G_SetPoint_2D(geometry, 0, C.double(point.Y), C.double(point.X))
+		C.OGR_F_SetGeometryDirectly(feature, geometry)
+
+		cName := C.CString(point.Name)
+		C.OGR_F_SetFieldString(feature, 0, cName)
+		C.free(unsafe.Pointer(cName))
+		C.OGR_F_SetFieldInteger(feature, 1, C.int(point.Number))
+		C.OGR_F_SetFieldDouble(feature, 2, C.double(point.X))
+		C.OGR_F_SetFieldDouble(feature, 3, C.double(point.Y))
+		C.OGR_F_SetFieldInteger(feature, 4, C.int(len(point.Parcels)))
+		cParcels := C.CString(strings.Join(point.Parcels, ","))
+		C.OGR_F_SetFieldString(feature, 5, cParcels)
+		C.free(unsafe.Pointer(cParcels))
+
+		C.OGR_L_CreateFeature(resultLayer.layer, feature)
+		C.OGR_F_Destroy(feature)
+	}
+
+	return resultLayer, nil
+}
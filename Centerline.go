@@ -0,0 +1,425 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+/*
+#include "osgeo_utils.h"
+*/
+import "C"
+import (
+	"fmt"
+	"math"
+	"sort"
+)
+
+// ============================================================================
+// 面中心线（骨架线）提取
+// ============================================================================
+
+// CenterlineOptions 中心线提取选项
+type CenterlineOptions struct {
+	DensifyDistance   float64 // 边界加密间距（图层单位），0表示按面积自动确定；越小中心线越精细
+	MinBranchLength   float64 // 短于该长度的末端分支被剪除，0表示不剪枝
+	SimplifyTolerance float64 // 中心线道格拉斯-普克简化容差，0表示不简化
+	SmoothIterations  int     // 中心线Chaikin平滑迭代次数，0表示不平滑
+}
+
+// CenterlineLayer 提取面图层的中心线
+// 采用弦轴变换：对加密后的边界顶点做Delaunay三角剖分，保留面内三角形，
+// 连接相邻三角形公共边的中点得到骨架，再剪除短分支并合并为连续线。
+// 每个面要素输出一个多线要素，并继承源要素属性；洞（岛）会被中心线绕开。
+func CenterlineLayer(sourceLayer *GDALLayer, options *CenterlineOptions) (*GDALLayer, error) {
+	if sourceLayer == nil || sourceLayer.layer == nil {
+		return nil, fmt.Errorf("源图层为空")
+	}
+	if options == nil {
+		options = &CenterlineOptions{}
+	}
+
+	sourceDefn := sourceLayer.GetLayerDefn()
+	flatType := C.OGR_GT_Flatten(C.OGR_FD_GetGeomType(sourceDefn))
+	if flatType != C.wkbPolygon && flatType != C.wkbMultiPolygon && flatType != C.wkbUnknown {
+		return nil, fmt.Errorf("中心线提取只支持面图层")
+	}
+
+	resultLayer, err := newMemoryResultLayer("centerline_result", "centerline", sourceLayer.GetSpatialRef(), C.wkbMultiLineString)
+	if err != nil {
+		return nil, err
+	}
+	copyLayerFieldDefns(sourceDefn, resultLayer.layer)
+	resultDefn := resultLayer.GetLayerDefn()
+
+	sourceLayer.ResetReading()
+	defer sourceLayer.ResetReading()
+	for {
+		feature := sourceLayer.GetNextFeatureRow()
+		if feature == nil {
+			break
+		}
+
+		geometry := C.OGR_F_GetGeometryRef(feature)
+		if geometry == nil || C.OGR_G_IsEmpty(geometry) != 0 {
+			C.OGR_F_Destroy(feature)
+			continue
+		}
+
+		centerline := centerlineGeometry(geometry, options)
+		if centerline != nil {
+			newFeature := C.OGR_F_Create(resultDefn)
+			C.OGR_F_SetGeometryDirectly(newFeature, centerline)
+			copyFeatureAttributes(feature, newFeature, sourceDefn, resultDefn)
+			C.OGR_L_CreateFeature(resultLayer.layer, newFeature)
+			C.OGR_F_Destroy(newFeature)
+		}
+		C.OGR_F_Destroy(feature)
+	}
+
+	return resultLayer, nil
+}
+
+// centerlineGeometry 提取面几何体的中心线，返回多线几何体，无结果时返回nil（对外接口为 (*Geometry).Centerline）
+func centerlineGeometry(geometry C.OGRGeometryH, options *CenterlineOptions) C.OGRGeometryH {
+	if geometry == nil {
+		return nil
+	}
+	if options == nil {
+		options = &CenterlineOptions{}
+	}
+
+	flatType := C.OGR_GT_Flatten(C.OGR_G_GetGeometryType(geometry))
+	if flatType != C.wkbPolygon && flatType != C.wkbMultiPolygon {
+		return nil
+	}
+
+	densify := options.DensifyDistance
+	if densify <= 0 {
+		// 按面的平均宽度估计：宽度约为 2*面积/周长
+		area := float64(C.OGR_G_Area(geometry))
+		boundary := C.OGR_G_Boundary(geometry)
+		perimeter := float64(C.OGR_G_Length(boundary))
+		C.OGR_G_DestroyGeometry(boundary)
+		if area <= 0 || perimeter <= 0 {
+			return nil
+		}
+		densify = 2 * area / perimeter / 4
+	}
+
+	densified := C.OGR_G_Clone(geometry)
+	defer C.OGR_G_DestroyGeometry(densified)
+	C.OGR_G_Segmentize(densified, C.double(densify))
+
+	triangles := C.OGR_G_DelaunayTriangulation(densified, 0, 0)
+	if triangles == nil {
+		return nil
+	}
+	defer C.OGR_G_DestroyGeometry(triangles)
+
+	graph := newSkeletonGraph(densify / 1000)
+	graph.addTriangles(triangles, densified)
+	if options.MinBranchLength > 0 {
+		graph.prune(options.MinBranchLength)
+	}
+
+	chains := graph.chains()
+	if len(chains) == 0 {
+		return nil
+	}
+
+	result := C.OGR_G_CreateGeometry(C.wkbMultiLineString)
+	for _, chain := range chains {
+		if options.SimplifyTolerance > 0 {
+			chain = simplifyCoords(chain, false, options.SimplifyTolerance, SimplifyDouglasPeucker)
+		}
+		if options.SmoothIterations > 0 {
+			chain = chaikinSmooth(chain, false, options.SmoothIterations)
+		}
+		line := C.OGR_G_CreateGeometry(C.wkbLineString)
+		for _, c := range chain {
+			C.OGR_G_AddPoint_2D(line, C.double(c[0]), C.double(c[1]))
+		}
+		C.OGR_G_AddGeometryDirectly(result, line)
+	}
+	return result
+}
+
+// skeletonGraph 骨架线无向图
+type skeletonGraph struct {
+	snap   float64
+	coords map[[2]int64][2]float64
+	edges  map[[2]int64]map[[2]int64]bool
+}
+
+func newSkeletonGraph(snap float64) *skeletonGraph {
+	if snap <= 0 {
+		snap = 1e-9
+	}
+	return &skeletonGraph{
+		snap:   snap,
+		coords: make(map[[2]int64][2]float64),
+		edges:  make(map[[2]int64]map[[2]int64]bool),
+	}
+}
+
+func (g *skeletonGraph) key(c [2]float64) [2]int64 {
+	return [2]int64{int64(math.Round(c[0] / g.snap)), int64(math.Round(c[1] / g.snap))}
+}
+
+func (g *skeletonGraph) node(c [2]float64) [2]int64 {
+	key := g.key(c)
+	if _, ok := g.coords[key]; !ok {
+		g.coords[key] = c
+	}
+	return key
+}
+
+func (g *skeletonGraph) addEdge(a, b [2]float64) {
+	ka, kb := g.node(a), g.node(b)
+	if ka == kb {
+		return
+	}
+	if g.edges[ka] == nil {
+		g.edges[ka] = make(map[[2]int64]bool)
+	}
+	if g.edges[kb] == nil {
+		g.edges[kb] = make(map[[2]int64]bool)
+	}
+	g.edges[ka][kb] = true
+	g.edges[kb][ka] = true
+}
+
+func (g *skeletonGraph) removeEdge(ka, kb [2]int64) {
+	delete(g.edges[ka], kb)
+	delete(g.edges[kb], ka)
+	if len(g.edges[ka]) == 0 {
+		delete(g.edges, ka)
+	}
+	if len(g.edges[kb]) == 0 {
+		delete(g.edges, kb)
+	}
+}
+
+// sortedKeys 按坐标排序节点键，保证遍历顺序与输出结果确定
+func sortedKeys(keys [][2]int64) [][2]int64 {
+	sort.Slice(keys, func(i, j int) bool {
+		if keys[i][0] != keys[j][0] {
+			return keys[i][0] < keys[j][0]
+		}
+		return keys[i][1] < keys[j][1]
+	})
+	return keys
+}
+
+// nodes 返回排序后的全部节点
+func (g *skeletonGraph) nodes() [][2]int64 {
+	keys := make([][2]int64, 0, len(g.edges))
+	for key := range g.edges {
+		keys = append(keys, key)
+	}
+	return sortedKeys(keys)
+}
+
+// neighbours 返回排序后的相邻节点
+func (g *skeletonGraph) neighbours(key [2]int64) [][2]int64 {
+	keys := make([][2]int64, 0, len(g.edges[key]))
+	for k := range g.edges[key] {
+		keys = append(keys, k)
+	}
+	return sortedKeys(keys)
+}
+
+// addTriangles 由面内三角形构建弦轴：连接内部边中点，三岔三角形连接到重心
+// 三角形是否在面内按重心判断：全部重心组成多点与面求一次交集，避免逐个三角形遍历面的顶点
+func (g *skeletonGraph) addTriangles(triangles C.OGRGeometryH, polygon C.OGRGeometryH) {
+	type triangle [3][2]float64
+	var all []triangle
+	var inner []triangle
+	edgeCount := make(map[[2][2]int64]int)
+	edgeKey := func(a, b [2]float64) [2][2]int64 {
+		ka, kb := g.key(a), g.key(b)
+		if ka[0] > kb[0] || (ka[0] == kb[0] && ka[1] > kb[1]) {
+			ka, kb = kb, ka
+		}
+		return [2][2]int64{ka, kb}
+	}
+
+	centroidOf := func(t triangle) [2]float64 {
+		return [2]float64{(t[0][0] + t[1][0] + t[2][0]) / 3, (t[0][1] + t[1][1] + t[2][1]) / 3}
+	}
+
+	centroids := C.OGR_G_CreateGeometry(C.wkbMultiPoint)
+	defer C.OGR_G_DestroyGeometry(centroids)
+	count := int(C.OGR_G_GetGeometryCount(triangles))
+	for i := 0; i < count; i++ {
+		ring := C.OGR_G_GetGeometryRef(C.OGR_G_GetGeometryRef(triangles, C.int(i)), 0)
+		if ring == nil || C.OGR_G_GetPointCount(ring) < 3 {
+			continue
+		}
+		var t triangle
+		for k := 0; k < 3; k++ {
+			t[k] = [2]float64{float64(C.OGR_G_GetX(ring, C.int(k))), float64(C.OGR_G_GetY(ring, C.int(k)))}
+		}
+		all = append(all, t)
+		c := centroidOf(t)
+		point := C.OGR_G_CreateGeometry(C.wkbPoint)
+		C.OGR_G_SetPoint_2D(point, 0, C.double(c[0]), C.double(c[1]))
+		C.OGR_G_AddGeometryDirectly(centroids, point)
+	}
+
+	insidePoints := C.OGR_G_Intersection(centroids, polygon)
+	if insidePoints == nil {
+		return
+	}
+	inside := make(map[[2]float64]bool)
+	for _, c := range collectGeometryPoints(insidePoints) {
+		inside[c] = true
+	}
+	C.OGR_G_DestroyGeometry(insidePoints)
+
+	for _, t := range all {
+		if !inside[centroidOf(t)] {
+			continue
+		}
+		inner = append(inner, t)
+		for k := 0; k < 3; k++ {
+			edgeCount[edgeKey(t[k], t[(k+1)%3])]++
+		}
+	}
+
+	for _, t := range inner {
+		var mids [][2]float64
+		for k := 0; k < 3; k++ {
+			a, b := t[k], t[(k+1)%3]
+			if edgeCount[edgeKey(a, b)] > 1 {
+				mids = append(mids, [2]float64{(a[0] + b[0]) / 2, (a[1] + b[1]) / 2})
+			}
+		}
+		switch len(mids) {
+		case 2:
+			g.addEdge(mids[0], mids[1])
+		case 3:
+			centroid := centroidOf(t)
+			for _, m := range mids {
+				g.addEdge(m, centroid)
+			}
+		}
+	}
+}
+
+// walkChain 从节点start沿next方向行走，直到遇到度不为2的节点或回到起点
+func (g *skeletonGraph) walkChain(start, next [2]int64) [][2]int64 {
+	chain := [][2]int64{start, next}
+	prev, current := start, next
+	for len(g.edges[current]) == 2 && current != start {
+		var following [2]int64
+		for k := range g.edges[current] {
+			if k != prev {
+				following = k
+			}
+		}
+		chain = append(chain, following)
+		prev, current = current, following
+	}
+	return chain
+}
+
+func (g *skeletonGraph) chainLength(chain [][2]int64) float64 {
+	length := 0.0
+	for i := 1; i < len(chain); i++ {
+		a, b := g.coords[chain[i-1]], g.coords[chain[i]]
+		length += math.Hypot(b[0]-a[0], b[1]-a[1])
+	}
+	return length
+}
+
+// prune 反复剪除一端悬挂、另一端连接分叉点且长度小于minLength的分支
+func (g *skeletonGraph) prune(minLength float64) {
+	for {
+		removed := false
+		for _, key := range g.nodes() {
+			if len(g.edges[key]) != 1 {
+				continue
+			}
+			next := g.neighbours(key)[0]
+			chain := g.walkChain(key, next)
+			end := chain[len(chain)-1]
+			// 孤立线段（两端均为悬挂点）不剪除，避免整条中心线被删除
+			if len(g.edges[end]) < 3 || g.chainLength(chain) >= minLength {
+				continue
+			}
+			for i := 1; i < len(chain); i++ {
+				g.removeEdge(chain[i-1], chain[i])
+			}
+			removed = true
+		}
+		if !removed {
+			return
+		}
+	}
+}
+
+// chains 将骨架图合并为连续线：在悬挂点和分叉点处断开
+func (g *skeletonGraph) chains() [][][2]float64 {
+	visited := make(map[[2][2]int64]bool)
+	mark := func(a, b [2]int64) bool {
+		if visited[[2][2]int64{a, b}] {
+			return false
+		}
+		visited[[2][2]int64{a, b}] = true
+		visited[[2][2]int64{b, a}] = true
+		return true
+	}
+
+	var result [][][2]float64
+	emit := func(chain [][2]int64) {
+		coords := make([][2]float64, len(chain))
+		for i, k := range chain {
+			coords[i] = g.coords[k]
+		}
+		result = append(result, coords)
+	}
+
+	for _, key := range g.nodes() {
+		if len(g.edges[key]) == 2 {
+			continue
+		}
+		for _, next := range g.neighbours(key) {
+			if visited[[2][2]int64{key, next}] {
+				continue
+			}
+			chain := g.walkChain(key, next)
+			for i := 1; i < len(chain); i++ {
+				mark(chain[i-1], chain[i])
+			}
+			emit(chain)
+		}
+	}
+
+	// 剩余的均为闭合环（如环绕岛屿的中心线）
+	for _, key := range g.nodes() {
+		for _, next := range g.neighbours(key) {
+			if visited[[2][2]int64{key, next}] {
+				continue
+			}
+			chain := g.walkChain(key, next)
+			for i := 1; i < len(chain); i++ {
+				mark(chain[i-1], chain[i])
+			}
+			emit(chain)
+		}
+	}
+	return result
+}
@@ -155,7 +155,7 @@ func createClipResultLayer(layer1, layer2 *GDALLayer) (*GDALLayer, error) {
 	}
 
 	resultLayer := &GDALLayer{layer: resultLayerPtr}
-	runtime.SetFinalizer(resultLayer, (*GDALLayer).cleanup)
+	manageLayer(resultLayer)
 
 	// 根据策略添加字段定义 - 裁剪通常保留输入图层的字段
 	err := addLayerFields(resultLayer, layer1, "")
@@ -239,6 +239,7 @@ func executeConcurrentClipAnalysis(tileGroups []GroupTileFiles, resultLayer *GDA
 				if err != nil {
 					processingError = fmt.Errorf("合并分块 %d 结果失败: %v", result.index, err)
 					log.Printf("错误: %v", processingError)
+					result.layer.Close()
 					return
 				}
 
@@ -278,6 +279,7 @@ func executeConcurrentClipAnalysis(tileGroups []GroupTileFiles, resultLayer *GDA
 
 	// 等待结果收集完成
 	resultWg.Wait()
+	releaseTaskResults(results)
 
 	if processingError != nil {
 		return processingError
@@ -329,6 +331,7 @@ func processTileGroupforClip(tileGroup GroupTileFiles, config *ParallelGeosConfi
 	// 加载layer2的bin文件
 	eraseTileLayer, err := DeserializeLayerFromFile(tileGroup.GPBin.Layer2)
 	if err != nil {
+		inputTileLayer.Close()
 		return nil, fmt.Errorf("加载擦除分块文件失败: %v", err)
 	}
 	defer func() {
@@ -652,6 +655,7 @@ func ExecuteConcurrentClipAnalysisPG(tileGroups []GroupTileFiles, resultLayer *G
 				if err != nil {
 					processingError = fmt.Errorf("合并分块 %d 结果失败: %v", result.index, err)
 					log.Printf("错误: %v", processingError)
+					result.layer.Close()
 					return
 				}
 				// 释放临时图层资源
@@ -682,6 +686,7 @@ func ExecuteConcurrentClipAnalysisPG(tileGroups []GroupTileFiles, resultLayer *G
 	close(results)
 	// 等待结果收集完成
 	resultWg.Wait()
+	releaseTaskResults(results)
 	if processingError != nil {
 		return processingError
 	}
@@ -0,0 +1,630 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+/*
+#include "osgeo_utils.h"
+*/
+import "C"
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"math"
+	"os"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// ============================================================================
+// 自然资源部坐标交换格式（[属性描述]/[地块坐标] txt）
+// ============================================================================
+
+// CoordExchangeHeader [属性描述]节
+type CoordExchangeHeader struct {
+	FormatVersion    string // 格式版本号
+	Producer         string // 数据产生单位
+	Date             string // 数据产生日期
+	CoordinateSystem string // 坐标系
+	ZoneWidth        int    // 几度分带（3或6）
+	ProjectionType   string // 投影类型
+	Unit             string // 计量单位
+	ZoneNumber       int    // 带号
+	Precision        string // 精度
+	TransformParams  string // 转换参数
+}
+
+// CoordExchangePoint 坐标行，X为纵坐标（北），Y为横坐标（东）
+type CoordExchangePoint struct {
+	Name string
+	X    float64
+	Y    float64
+}
+
+// CoordExchangeRing 一个圈的坐标（首尾闭合）
+type CoordExchangeRing struct {
+	Number int
+	Points []CoordExchangePoint
+}
+
+// CoordExchangeParcel [地块坐标]节中的一个地块
+type CoordExchangeParcel struct {
+	PointCount   int     // 界址点数
+	Area         float64 // 地块面积（公顷）
+	ParcelNo     string  // 地块编号
+	Name         string  // 地块名称
+	GeometryKind string  // 记录图形属性（点、线、面）
+	SheetNo      string  // 图幅号
+	Usage        string  // 地块用途
+	LandCode     string  // 地类编码
+	Rings        []*CoordExchangeRing
+}
+
+// CoordExchangeFile 坐标交换文件
+type CoordExchangeFile struct {
+	Header  CoordExchangeHeader
+	Parcels []*CoordExchangeParcel
+}
+
+// coordExchangeFieldNames 交换文件地块属性对应的图层字段
+var coordExchangeFieldNames = []string{"DKBH", "DKMC", "DKMJ", "JZDS", "TXSX", "TFH", "DKYT", "DLBM"}
+
+// ParseCoordExchange 解析坐标交换文件内容，自动识别GBK与UTF-8编码
+func ParseCoordExchange(data []byte) (*CoordExchangeFile, error) {
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+	text := string(data)
+	if !utf8.ValidString(text) {
+		decoded, err := recodeString(text, "CP936", "UTF-8")
+		if err != nil {
+			return nil, err
+		}
+		text = decoded
+	}
+
+	file := &CoordExchangeFile{}
+	section := ""
+	var parcel *CoordExchangeParcel
+	scanner := bufio.NewScanner(strings.NewReader(text))
+	lineNo := 0
+	for scanner.Scan() {
+		lineNo++
+		line := strings.TrimSpace(scanner.Text())
+		if line == "" {
+			continue
+		}
+		if strings.HasPrefix(line, "[") && strings.HasSuffix(line, "]") {
+			section = strings.Trim(line, "[]")
+			continue
+		}
+
+		switch section {
+		case "属性描述":
+			key, value, ok := strings.Cut(line, "=")
+			if !ok {
+				continue
+			}
+			file.Header.set(strings.TrimSpace(key), strings.TrimSpace(value))
+
+		case "地块坐标":
+			parts := strings.Split(line, ",")
+			if strings.HasSuffix(line, "@") {
+				parcel = parseCoordExchangeParcelLine(parts)
+				file.Parcels = append(file.Parcels, parcel)
+				continue
+			}
+			if parcel == nil {
+				return nil, fmt.Errorf("第%d行: 坐标行之前缺少地块信息行", lineNo)
+			}
+			if len(parts) < 4 {
+				return nil, fmt.Errorf("第%d行: 坐标行格式错误", lineNo)
+			}
+			ringNumber, err1 := strconv.Atoi(strings.TrimSpace(parts[1]))
+			x, err2 := strconv.ParseFloat(strings.TrimSpace(parts[2]), 64)
+			y, err3 := strconv.ParseFloat(strings.TrimSpace(parts[3]), 64)
+			if err1 != nil || err2 != nil || err3 != nil {
+				return nil, fmt.Errorf("第%d行: 坐标行数值无效", lineNo)
+			}
+			if len(parcel.Rings) == 0 || parcel.Rings[len(parcel.Rings)-1].Number != ringNumber {
+				parcel.Rings = append(parcel.Rings, &CoordExchangeRing{Number: ringNumber})
+			}
+			ring := parcel.Rings[len(parcel.Rings)-1]
+			ring.Points = append(ring.Points, CoordExchangePoint{Name: strings.TrimSpace(parts[0]), X: x, Y: y})
+		}
+	}
+	if err := scanner.Err(); err != nil {
+		return nil, fmt.Errorf("读取坐标交换文件失败: %v", err)
+	}
+	if len(file.Parcels) == 0 {
+		return nil, fmt.Errorf("坐标交换文件中没有地块")
+	}
+	return file, nil
+}
+
+// ReadCoordExchangeFile 读取坐标交换文件
+func ReadCoordExchangeFile(path string) (*CoordExchangeFile, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("读取文件失败: %v", err)
+	}
+	return ParseCoordExchange(data)
+}
+
+// ReadCoordExchangeLayer 读取坐标交换文件为面图层，坐标系根据带号或坐标自动判定
+func ReadCoordExchangeLayer(path string) (*GDALLayer, error) {
+	file, err := ReadCoordExchangeFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return file.ToLayer()
+}
+
+func (h *CoordExchangeHeader) set(key, value string) {
+	switch key {
+	case "格式版本号":
+		h.FormatVersion = value
+	case "数据产生单位":
+		h.Producer = value
+	case "数据产生日期":
+		h.Date = value
+	case "坐标系":
+		h.CoordinateSystem = value
+	case "几度分带":
+		h.ZoneWidth, _ = strconv.Atoi(value)
+	case "投影类型":
+		h.ProjectionType = value
+	case "计量单位":
+		h.Unit = value
+	case "带号":
+		h.ZoneNumber, _ = strconv.Atoi(value)
+	case "精度":
+		h.Precision = value
+	case "转换参数":
+		h.TransformParams = value
+	}
+}
+
+func parseCoordExchangeParcelLine(parts []string) *CoordExchangeParcel {
+	get := func(i int) string {
+		if i < len(parts) {
+			return strings.TrimSpace(parts[i])
+		}
+		return ""
+	}
+	parcel := &CoordExchangeParcel{
+		ParcelNo:     get(2),
+		Name:         get(3),
+		GeometryKind: get(4),
+		SheetNo:      get(5),
+		Usage:        get(6),
+		LandCode:     get(7),
+	}
+	parcel.PointCount, _ = strconv.Atoi(get(0))
+	parcel.Area, _ = strconv.ParseFloat(get(1), 64)
+	return parcel
+}
+
+// DetectZone 判定坐标所在的投影带：优先根据坐标的带号前缀，其次使用[属性描述]中的带号
+func (f *CoordExchangeFile) DetectZone() (*GKZoneGuess, error) {
+	for _, parcel := range f.Parcels {
+		for _, ring := range parcel.Rings {
+			if len(ring.Points) == 0 {
+				continue
+			}
+			p := ring.Points[0]
+			if guess, err := GuessGKZoneFromCoordinate(p.Y, p.X); err == nil {
+				return guess, nil
+			}
+			break
+		}
+	}
+
+	if f.Header.ZoneNumber > 0 {
+		width := GKZoneWidth(f.Header.ZoneWidth)
+		if width != GKZone3Degree && width != GKZone6Degree {
+			width = GKZone3Degree
+		}
+		zone, err := GKZoneByNumber(f.Header.ZoneNumber, width)
+		if err != nil {
+			return nil, err
+		}
+		srs, err := zone.SpatialReference(false)
+		if err != nil {
+			return nil, err
+		}
+		return &GKZoneGuess{Zone: zone, SpatialReference: srs}, nil
+	}
+	return nil, fmt.Errorf("无法确定坐标交换文件的投影带")
+}
+
+// ToLayer 转换为面图层，字段为DKBH、DKMC、DKMJ、JZDS、TXSX、TFH、DKYT、DLBM
+// 圈号1为外圈；其余圈落在已有面内时作为内圈（洞），否则作为新的面部件
+func (f *CoordExchangeFile) ToLayer() (*GDALLayer, error) {
+	guess, err := f.DetectZone()
+	if err != nil {
+		return nil, err
+	}
+	srs, err := guess.SpatialReference.ToOGRGDBSpatialReference()
+	if err != nil {
+		return nil, err
+	}
+	defer C.OSRDestroySpatialReference(srs)
+	C.OSRSetAxisMappingStrategy(srs, C.OAMS_TRADITIONAL_GIS_ORDER)
+
+	resultLayer, err := newMemoryResultLayer("coord_exchange", "parcels", srs, C.wkbMultiPolygon)
+	if err != nil {
+		return nil, err
+	}
+	fieldTypes := []C.OGRFieldType{C.OFTString, C.OFTString, C.OFTReal, C.OFTInteger, C.OFTString, C.OFTString, C.OFTString, C.OFTString}
+	for i, name := range coordExchangeFieldNames {
+		cName := C.CString(name)
+		C.addFieldToLayer(resultLayer.layer, cName, fieldTypes[i])
+		C.free(unsafe.Pointer(cName))
+	}
+
+	defn := resultLayer.GetLayerDefn()
+	for _, parcel := range f.Parcels {
+		feature := C.OGR_F_Create(defn)
+		C.OGR_F_SetGeometryDirectly(feature, parcel.geometry())
+
+		for i, value := range []string{parcel.ParcelNo, parcel.Name, "", "", parcel.GeometryKind, parcel.SheetNo, parcel.Usage, parcel.LandCode} {
+			if value == "" {
+				continue
+			}
+			cValue := C.CString(value)
+			C.OGR_F_SetFieldString(feature, C.int(i), cValue)
+			C.free(unsafe.Pointer(cValue))
+		}
+		C.OGR_F_SetFieldDouble(feature, 2, C.double(parcel.Area))
+		C.OGR_F_SetFieldInteger(feature, 3, C.int(parcel.PointCount))
+
+		C.OGR_L_CreateFeature(resultLayer.layer, feature)
+		C.OGR_F_Destroy(feature)
+	}
+
+	return resultLayer, nil
+}
+
+// geometry 由圈坐标构建多面几何
+func (p *CoordExchangeParcel) geometry() C.OGRGeometryH {
+	multi := C.OGR_G_CreateGeometry(C.wkbMultiPolygon)
+	var current C.OGRGeometryH
+
+	for _, ring := range p.Rings {
+		if len(ring.Points) < 3 {
+			continue
+		}
+		linearRing := C.OGR_G_CreateGeometry(C.wkbLinearRing)
+		for _, pt := range ring.Points {
+			C.OGR_G_AddPoint_2D(linearRing, C.double(pt.Y), C.double(pt.X))
+		}
+		C.OGR_G_CloseRings(linearRing)
+
+		if current != nil && ringInsidePolygon(linearRing, current) {
+			C.OGR_G_AddGeometryDirectly(current, linearRing)
+			continue
+		}
+		if current != nil {
+			C.OGR_G_AddGeometryDirectly(multi, current)
+		}
+		current = C.OGR_G_CreateGeometry(C.wkbPolygon)
+		C.OGR_G_AddGeometryDirectly(current, linearRing)
+	}
+	if current != nil {
+		C.OGR_G_AddGeometryDirectly(multi, current)
+	}
+	return multi
+}
+
+// ringInsidePolygon 判断环是否位于面的外圈之内
+func ringInsidePolygon(ring, polygon C.OGRGeometryH) bool {
+	shell := C.OGR_G_CreateGeometry(C.wkbPolygon)
+	C.OGR_G_AddGeometry(shell, C.OGR_G_GetGeometryRef(polygon, 0))
+	candidate := C.OGR_G_CreateGeometry(C.wkbPolygon)
+	C.OGR_G_AddGeometry(candidate, ring)
+
+	inside := C.OGR_G_Contains(shell, candidate) != 0
+	C.OGR_G_DestroyGeometry(shell)
+	C.OGR_G_DestroyGeometry(candidate)
+	return inside
+}
+
+// ============================================================================
+// 写出
+// ============================================================================
+
+// CoordExchangeWriteOptions 坐标交换文件写出选项
+type CoordExchangeWriteOptions struct {
+	Header         CoordExchangeHeader // 为空的项自动填充
+	ParcelNoField  string              // 地块编号字段，默认 DKBH，缺失时使用FID
+	NameField      string              // 地块名称字段，默认 DKMC
+	SheetNoField   string              // 图幅号字段，默认 TFH
+	UsageField     string              // 地块用途字段，默认 DKYT
+	LandCodeField  string              // 地类编码字段，默认 DLBM
+	PointPrefix    string              // 界址点号前缀，默认 J
+	Tolerance      float64             // 界址点合并容差，默认0.001
+	Decimals       int                 // 坐标小数位数，默认3
+	Encoding       string              // 文件编码，默认GBK，可选UTF-8
+	PerParcelNames bool                // 为true时每个地块界址点号从1重新编号
+}
+
+func (o *CoordExchangeWriteOptions) setDefaults() {
+	if o.ParcelNoField == "" {
+		o.ParcelNoField = "DKBH"
+	}
+	if o.NameField == "" {
+		o.NameField = "DKMC"
+	}
+	if o.SheetNoField == "" {
+		o.SheetNoField = "TFH"
+	}
+	if o.UsageField == "" {
+		o.UsageField = "DKYT"
+	}
+	if o.LandCodeField == "" {
+		o.LandCodeField = "DLBM"
+	}
+	if o.Decimals <= 0 {
+		o.Decimals = 3
+	}
+	if o.Encoding == "" {
+		o.Encoding = "GBK"
+	}
+}
+
+// CoordExchangeFromLayer 由投影坐标系下的地块面图层生成坐标交换文件内容
+func CoordExchangeFromLayer(layer *GDALLayer, options *CoordExchangeWriteOptions) (*CoordExchangeFile, error) {
+	if layer == nil || layer.layer == nil {
+		return nil, fmt.Errorf("图层为空")
+	}
+	if options == nil {
+		options = &CoordExchangeWriteOptions{}
+	}
+	opts := *options
+	opts.setDefaults()
+
+	srs := layer.GetSpatialRef()
+	if srs != nil && C.OSRIsProjected(srs) == 0 {
+		return nil, fmt.Errorf("坐标交换文件需要投影坐标，请先将图层转换到高斯投影坐标系")
+	}
+
+	file := &CoordExchangeFile{Header: opts.Header}
+	if err := fillCoordExchangeHeader(&file.Header, layer, srs); err != nil {
+		return nil, err
+	}
+
+	defn := layer.GetLayerDefn()
+	idIndex := layerFieldIndex(defn, opts.ParcelNoField)
+	nameIndex := layerFieldIndex(defn, opts.NameField)
+	sheetIndex := layerFieldIndex(defn, opts.SheetNoField)
+	usageIndex := layerFieldIndex(defn, opts.UsageField)
+	codeIndex := layerFieldIndex(defn, opts.LandCodeField)
+	fieldString := func(feature C.OGRFeatureH, index int) string {
+		if index < 0 || C.OGR_F_IsFieldSetAndNotNull(feature, C.int(index)) == 0 {
+			return ""
+		}
+		return C.GoString(C.OGR_F_GetFieldAsString(feature, C.int(index)))
+	}
+
+	registry := newBoundaryPointRegistry(opts.PointPrefix, opts.Tolerance)
+	layer.ResetReading()
+	defer layer.ResetReading()
+	for {
+		feature := layer.GetNextFeatureRow()
+		if feature == nil {
+			break
+		}
+		geometry := C.OGR_F_GetGeometryRef(feature)
+		if geometry == nil || C.OGR_G_IsEmpty(geometry) != 0 {
+			C.OGR_F_Destroy(feature)
+			continue
+		}
+
+		parcelID := featureParcelID(feature, idIndex)
+		if opts.PerParcelNames {
+			registry = newBoundaryPointRegistry(opts.PointPrefix, opts.Tolerance)
+		}
+		rings := registry.parcelRings(geometry, parcelID)
+		if len(rings) == 0 {
+			C.OGR_F_Destroy(feature)
+			continue
+		}
+
+		parcel := &CoordExchangeParcel{
+			Area:         roundToDecimals(float64(C.OGR_G_Area(geometry))/10000, 4),
+			ParcelNo:     parcelID,
+			Name:         fieldString(feature, nameIndex),
+			GeometryKind: "面",
+			SheetNo:      fieldString(feature, sheetIndex),
+			Usage:        fieldString(feature, usageIndex),
+			LandCode:     fieldString(feature, codeIndex),
+		}
+		for _, ring := range rings {
+			exchangeRing := &CoordExchangeRing{Number: ring.RingNumber}
+			for _, point := range append(ring.Points, ring.Points[0]) {
+				exchangeRing.Points = append(exchangeRing.Points, CoordExchangePoint{
+					Name: point.Name,
+					X:    roundToDecimals(point.X, opts.Decimals),
+					Y:    roundToDecimals(point.Y, opts.Decimals),
+				})
+			}
+			parcel.PointCount += len(exchangeRing.Points)
+			parcel.Rings = append(parcel.Rings, exchangeRing)
+		}
+		file.Parcels = append(file.Parcels, parcel)
+		C.OGR_F_Destroy(feature)
+	}
+
+	if len(file.Parcels) == 0 {
+		return nil, fmt.Errorf("图层中没有有效的面要素")
+	}
+	return file, nil
+}
+
+// fillCoordExchangeHeader 填充[属性描述]中未设置的项，带号由坐标系或坐标推断
+func fillCoordExchangeHeader(h *CoordExchangeHeader, layer *GDALLayer, srs C.OGRSpatialReferenceH) error {
+	if h.FormatVersion == "" {
+		h.FormatVersion = "1.01版本"
+	}
+	if h.Date == "" {
+		h.Date = time.Now().Format("2006-01-02")
+	}
+	if h.CoordinateSystem == "" {
+		h.CoordinateSystem = "2000国家大地坐标系"
+	}
+	if h.ProjectionType == "" {
+		h.ProjectionType = "高斯克吕格"
+	}
+	if h.Unit == "" {
+		h.Unit = "米"
+	}
+	if h.Precision == "" {
+		h.Precision = "0.001"
+	}
+	if h.TransformParams == "" {
+		h.TransformParams = ",,,,,,"
+	}
+	if h.ZoneNumber > 0 && h.ZoneWidth > 0 {
+		return nil
+	}
+
+	if guess, err := GuessLayerGKZone(layer); err == nil {
+		h.ZoneNumber = guess.Zone.Number
+		h.ZoneWidth = int(guess.Zone.Width)
+		return nil
+	}
+	if zone, ok := gkZoneFromSRS(srs); ok {
+		h.ZoneNumber = zone.Number
+		h.ZoneWidth = int(zone.Width)
+		return nil
+	}
+	return fmt.Errorf("无法确定图层的投影带，请在Header中指定带号和几度分带")
+}
+
+// gkZoneFromSRS 根据中央经线推断投影带，中央经线同时符合3度和6度分带时按3度带处理
+func gkZoneFromSRS(srs C.OGRSpatialReferenceH) (GKZone, bool) {
+	if srs == nil || C.OSRIsProjected(srs) == 0 {
+		return GKZone{}, false
+	}
+	cParm := C.CString("central_meridian")
+	defer C.free(unsafe.Pointer(cParm))
+	var cErr C.OGRErr
+	cm := float64(C.OSRGetProjParm(srs, cParm, 0, &cErr))
+	if cErr != C.OGRERR_NONE {
+		return GKZone{}, false
+	}
+
+	if math.Abs(cm-math.Round(cm)) > 1e-9 {
+		return GKZone{}, false
+	}
+	for _, width := range []GKZoneWidth{GKZone3Degree, GKZone6Degree} {
+		if zone, err := GKZoneByLongitude(cm, width); err == nil && zone.CentralMeridian == int(math.Round(cm)) {
+			return zone, true
+		}
+	}
+	return GKZone{}, false
+}
+
+// Bytes 按指定编码（GBK或UTF-8）序列化为文本
+func (f *CoordExchangeFile) Bytes(encoding string, decimals int) ([]byte, error) {
+	if decimals <= 0 {
+		decimals = 3
+	}
+	var sb strings.Builder
+	h := f.Header
+	sb.WriteString("[属性描述]\r\n")
+	for _, kv := range [][2]string{
+		{"格式版本号", h.FormatVersion},
+		{"数据产生单位", h.Producer},
+		{"数据产生日期", h.Date},
+		{"坐标系", h.CoordinateSystem},
+		{"几度分带", strconv.Itoa(h.ZoneWidth)},
+		{"投影类型", h.ProjectionType},
+		{"计量单位", h.Unit},
+		{"带号", strconv.Itoa(h.ZoneNumber)},
+		{"精度", h.Precision},
+		{"转换参数", h.TransformParams},
+	} {
+		sb.WriteString(kv[0] + "=" + kv[1] + "\r\n")
+	}
+
+	sb.WriteString("[地块坐标]\r\n")
+	for _, p := range f.Parcels {
+		sb.WriteString(fmt.Sprintf("%d,%.4f,%s,%s,%s,%s,%s,%s,@\r\n",
+			p.PointCount, p.Area, p.ParcelNo, p.Name, p.GeometryKind, p.SheetNo, p.Usage, p.LandCode))
+		for _, ring := range p.Rings {
+			for _, pt := range ring.Points {
+				sb.WriteString(fmt.Sprintf("%s,%d,%.*f,%.*f\r\n", pt.Name, ring.Number, decimals, pt.X, decimals, pt.Y))
+			}
+		}
+	}
+
+	text := sb.String()
+	switch strings.ToUpper(encoding) {
+	case "", "GBK", "CP936", "GB2312", "GB18030":
+		encoded, err := recodeString(text, "UTF-8", "CP936")
+		if err != nil {
+			return nil, err
+		}
+		return []byte(encoded), nil
+	case "UTF-8", "UTF8":
+		return []byte(text), nil
+	default:
+		return nil, fmt.Errorf("不支持的编码: %s", encoding)
+	}
+}
+
+// WriteCoordExchangeFile 将地块面图层写出为坐标交换文件
+func WriteCoordExchangeFile(layer *GDALLayer, path string, options *CoordExchangeWriteOptions) error {
+	if options == nil {
+		options = &CoordExchangeWriteOptions{}
+	}
+	opts := *options
+	opts.setDefaults()
+
+	file, err := CoordExchangeFromLayer(layer, &opts)
+	if err != nil {
+		return err
+	}
+	data, err := file.Bytes(opts.Encoding, opts.Decimals)
+	if err != nil {
+		return err
+	}
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		return fmt.Errorf("写入坐标交换文件失败: %v", err)
+	}
+	return nil
+}
+
+// recodeString 使用GDAL进行字符编码转换
+func recodeString(text, fromEncoding, toEncoding string) (string, error) {
+	cText := C.CString(text)
+	defer C.free(unsafe.Pointer(cText))
+	cFrom := C.CString(fromEncoding)
+	defer C.free(unsafe.Pointer(cFrom))
+	cTo := C.CString(toEncoding)
+	defer C.free(unsafe.Pointer(cTo))
+
+	recoded := C.CPLRecode(cText, cFrom, cTo)
+	if recoded == nil {
+		return "", fmt.Errorf("编码转换失败: %s -> %s", fromEncoding, toEncoding)
+	}
+	defer C.CPLFree(unsafe.Pointer(recoded))
+	return C.GoString(recoded), nil
+}
@@ -52,9 +52,9 @@ func CreateMemoryLayer(layerName string, geomType GeomType) (*GDALLayer, error)
 		return nil, fmt.Errorf("创建图层失败")
 	}
 
-	return &GDALLayer{
+	return manageLayer(&GDALLayer{
 		layer:   layer,
 		dataset: dataset,
 		driver:  driver,
-	}, nil
+	}), nil
 }
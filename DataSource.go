@@ -0,0 +1,296 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+import (
+	"fmt"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+)
+
+// ============================================================================
+// 统一数据源URI
+//   pg://profile/schema.table      PostGIS表（profile为注册的连接名，为空时使用default）
+//   gdb:///path/to/data.gdb/layer  FileGDB图层
+//   gpkg:///path/to/data.gpkg/layer
+//   shp:///path/to/data.shp
+//   geojson:// fgb:// parquet:// kml:// kmz:// dxf:// csv:// xlsx://
+//   file:///path/to/data.ext       按扩展名判断格式；不带scheme的路径等同于file://
+// 多图层文件的图层名写在文件路径之后，也可使用 ?layer=name。
+// ============================================================================
+
+// DataSourceURI 解析后的数据源地址
+type DataSourceURI struct {
+	Scheme  string // pg 或文件格式（与FileGeoReader.FileType一致），file表示按扩展名判断
+	Profile string // PostGIS连接名
+	Schema  string // PostGIS schema，为空时使用连接的默认schema
+	Table   string // PostGIS表名
+	Path    string // 文件路径
+	Layer   string // 图层名（多图层文件）
+}
+
+// dataSourceExtensions 各文件格式scheme对应的扩展名
+var dataSourceExtensions = map[string][]string{
+	"shp":     {".shp"},
+	"gdb":     {".gdb"},
+	"gpkg":    {".gpkg"},
+	"geojson": {".geojson", ".json"},
+	"fgb":     {".fgb"},
+	"parquet": {".parquet", ".geoparquet"},
+	"kml":     {".kml"},
+	"kmz":     {".kmz"},
+	"dxf":     {".dxf"},
+	"csv":     {".csv", ".txt"},
+	"xlsx":    {".xlsx"},
+}
+
+// ParseDataSourceURI 解析数据源URI
+func ParseDataSourceURI(uri string) (*DataSourceURI, error) {
+	uri = strings.TrimSpace(uri)
+	if uri == "" {
+		return nil, fmt.Errorf("数据源地址为空")
+	}
+
+	scheme, rest, ok := strings.Cut(uri, "://")
+	if !ok {
+		scheme, rest = "file", uri
+	}
+	scheme = strings.ToLower(scheme)
+
+	switch scheme {
+	case "pg", "postgis":
+		return parsePGDataSource(rest)
+	case "json":
+		scheme = "geojson"
+	case "geoparquet":
+		scheme = "parquet"
+	}
+	if _, known := dataSourceExtensions[scheme]; !known && scheme != "file" {
+		return nil, fmt.Errorf("不支持的数据源类型: %s", scheme)
+	}
+
+	result := &DataSourceURI{Scheme: scheme}
+	if path, query, found := strings.Cut(rest, "?"); found {
+		rest = path
+		if values, err := url.ParseQuery(query); err == nil {
+			result.Layer = values.Get("layer")
+		}
+	}
+	if unescaped, err := url.PathUnescape(rest); err == nil {
+		rest = unescaped
+	}
+	// file:///C:/data.gdb 形式的Windows盘符路径
+	if len(rest) >= 3 && rest[0] == '/' && rest[2] == ':' {
+		rest = rest[1:]
+	}
+
+	result.Path = rest
+	extensions := dataSourceExtensions[scheme]
+	if scheme == "file" {
+		for _, exts := range dataSourceExtensions {
+			extensions = append(extensions, exts...)
+		}
+	}
+	// 取最靠前的"扩展名/"位置作为文件路径结尾
+	lower := strings.ToLower(rest)
+	end := -1
+	for _, ext := range extensions {
+		if index := strings.Index(lower, ext+"/"); index >= 0 && (end < 0 || index+len(ext) < end) {
+			end = index + len(ext)
+		}
+	}
+	if end >= 0 {
+		result.Path = rest[:end]
+		if layer := strings.Trim(rest[end+1:], "/"); layer != "" {
+			result.Layer = layer
+		}
+	}
+	result.Path = strings.TrimSuffix(result.Path, "/")
+	if result.Path == "" {
+		return nil, fmt.Errorf("数据源地址缺少文件路径: %s", uri)
+	}
+	return result, nil
+}
+
+// parsePGDataSource 解析 profile/schema.table
+func parsePGDataSource(rest string) (*DataSourceURI, error) {
+	profile, table, ok := strings.Cut(rest, "/")
+	if !ok || table == "" {
+		return nil, fmt.Errorf("PostGIS地址缺少表名: pg://%s", rest)
+	}
+	if profile == "" {
+		profile = DefaultConnectionName
+	}
+
+	result := &DataSourceURI{Scheme: "pg", Profile: profile, Table: table}
+	if schema, name, found := strings.Cut(table, "."); found {
+		result.Schema, result.Table = schema, name
+	}
+	return result, nil
+}
+
+// String 返回URI字符串
+func (u *DataSourceURI) String() string {
+	if u.Scheme == "pg" {
+		if u.Schema != "" {
+			return fmt.Sprintf("pg://%s/%s.%s", u.Profile, u.Schema, u.Table)
+		}
+		return fmt.Sprintf("pg://%s/%s", u.Profile, u.Table)
+	}
+
+	path := filepath.ToSlash(u.Path)
+	if len(path) >= 2 && path[1] == ':' {
+		path = "/" + path
+	}
+	if u.Layer != "" {
+		path += "/" + u.Layer
+	}
+	return u.Scheme + "://" + path
+}
+
+// postGISConfig 根据连接名生成PostGIS配置
+func (u *DataSourceURI) postGISConfig() (*PostGISConfig, error) {
+	profile, err := GetConnection(u.Profile)
+	if err != nil {
+		return nil, err
+	}
+	return profile.PostGISConfig(u.Schema, u.Table), nil
+}
+
+// checkFileType 检查文件扩展名与scheme是否一致
+func (u *DataSourceURI) checkFileType(fileType string) error {
+	if u.Scheme != "file" && u.Scheme != fileType {
+		return fmt.Errorf("文件 %s 的类型(%s)与地址类型(%s)不一致", u.Path, fileType, u.Scheme)
+	}
+	return nil
+}
+
+// checkTableType 检查表格文件与scheme是否一致
+func (u *DataSourceURI) checkTableType() error {
+	if u.Scheme != "file" && u.Scheme != "csv" && u.Scheme != "xlsx" {
+		return fmt.Errorf("文件 %s 是表格文件，与地址类型(%s)不一致", u.Path, u.Scheme)
+	}
+	return nil
+}
+
+// OpenLayer 按URI打开图层
+// CSV/XLSX按默认选项自动识别坐标列，需要指定列名或坐标系时请使用 ReadTableLayer。
+func OpenLayer(uri string) (*GDALLayer, error) {
+	source, err := ParseDataSourceURI(uri)
+	if err != nil {
+		return nil, err
+	}
+
+	if source.Scheme == "pg" {
+		config, err := source.postGISConfig()
+		if err != nil {
+			return nil, err
+		}
+		return NewPostGISReader(config).ReadGeometryTable()
+	}
+
+	if isTableDataSource(source.Path) {
+		if err := source.checkTableType(); err != nil {
+			return nil, err
+		}
+		result, err := ReadTableLayer(source.Path, &TableImportOptions{Sheet: source.Layer})
+		if err != nil {
+			return nil, err
+		}
+		return result.Layer, nil
+	}
+
+	reader, err := NewFileGeoReader(source.Path)
+	if err != nil {
+		return nil, err
+	}
+	if err := source.checkFileType(reader.FileType); err != nil {
+		return nil, err
+	}
+	if source.Layer != "" {
+		return reader.ReadLayer(source.Layer)
+	}
+	return reader.ReadLayer()
+}
+
+// WriteLayer 按URI写入图层
+// 文件目标的图层名取URI中的图层名，未指定时使用源图层名；PostGIS目标写入URI指定的表。
+func WriteLayer(sourceLayer *GDALLayer, uri string, overwrite bool) error {
+	if sourceLayer == nil || sourceLayer.layer == nil {
+		return fmt.Errorf("源图层为空")
+	}
+	target, err := ParseDataSourceURI(uri)
+	if err != nil {
+		return err
+	}
+
+	if target.Scheme == "pg" {
+		config, err := target.postGISConfig()
+		if err != nil {
+			return err
+		}
+		return NewPostGISWriter(config, overwrite).WriteLayer(sourceLayer)
+	}
+
+	if isTableDataSource(target.Path) {
+		if err := target.checkTableType(); err != nil {
+			return err
+		}
+		// 表格导出总是重建文件
+		if _, err := os.Stat(target.Path); err == nil && !overwrite {
+			return fmt.Errorf("目标文件已存在: %s", target.Path)
+		}
+		return ExportLayerTable(sourceLayer, target.Path, &TableExportOptions{Sheet: target.Layer})
+	}
+
+	writer, err := NewFileGeoWriter(target.Path, overwrite)
+	if err != nil {
+		return err
+	}
+	if err := target.checkFileType(writer.FileType); err != nil {
+		return err
+	}
+
+	layerName := target.Layer
+	if layerName == "" {
+		layerName = sourceLayer.GetLayerName()
+	}
+	if layerName == "" {
+		layerName = strings.TrimSuffix(filepath.Base(target.Path), filepath.Ext(target.Path))
+	}
+	return writer.WriteLayer(sourceLayer, layerName)
+}
+
+// CopyLayer 在两个URI之间复制图层
+func CopyLayer(sourceURI, targetURI string, overwrite bool) error {
+	layer, err := OpenLayer(sourceURI)
+	if err != nil {
+		return err
+	}
+	defer layer.Close()
+	return WriteLayer(layer, targetURI, overwrite)
+}
+
+func isTableDataSource(path string) bool {
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".csv", ".txt", ".xlsx":
+		return true
+	}
+	return false
+}
@@ -372,6 +372,9 @@ func convertMultiPolygon(hGeometry C.OGRGeometryH) orb.MultiPolygon {
 
 // getFieldValue 获取字段值
 func getFieldValue(hFeature C.OGRFeatureH, fieldIndex C.int, fieldType C.OGRFieldType) interface{} {
+	if featureFieldState(hFeature, fieldIndex) != FieldSet {
+		return nil
+	}
 	switch fieldType {
 	case C.OFTString:
 		value := C.OGR_F_GetFieldAsString(hFeature, fieldIndex)
@@ -401,6 +404,14 @@ func getFieldValue(hFeature C.OGRFeatureH, fieldIndex C.int, fieldType C.OGRFiel
 		}
 		// 如果获取失败，返回 nil 而不是空字符串
 		return nil
+	case C.OFTBinary:
+		return featureBinary(hFeature, fieldIndex)
+	case C.OFTIntegerList, C.OFTInteger64List:
+		return featureInteger64List(hFeature, fieldIndex)
+	case C.OFTRealList:
+		return featureDoubleList(hFeature, fieldIndex)
+	case C.OFTStringList:
+		return featureStringList(hFeature, fieldIndex)
 	default:
 		value := C.OGR_F_GetFieldAsString(hFeature, fieldIndex)
 		return C.GoString(value)
@@ -1385,17 +1396,9 @@ func createShapefileFromLayer(layer PostGISLayer, outputPath, geomType string) e
 		return fmt.Errorf("无法获取Shapefile驱动")
 	}
 
-	// 创建数据源 - 添加GBK编码选项
+	// 创建数据源
 	cOutputPath := C.CString(outputPath)
 	defer C.free(unsafe.Pointer(cOutputPath))
-	// 设置Shapefile编码为GBK/GB2312（中文Windows系统）
-	C.CPLSetConfigOption(C.CString("SHAPE_ENCODING"), C.CString("GBK"))
-	defer C.CPLSetConfigOption(C.CString("SHAPE_ENCODING"), nil)
-	// 创建选项数组，指定GBK编码
-	cEncodingOption := C.CString("ENCODING=GBK")
-	defer C.free(unsafe.Pointer(cEncodingOption))
-
-	// 创建选项指针数组
 
 	hDataSource := C.OGR_Dr_CreateDataSource(hDriver, cOutputPath, nil)
 
@@ -1427,7 +1430,13 @@ func createShapefileFromLayer(layer PostGISLayer, outputPath, geomType string) e
 	cLayerName := C.CString(layer.LayerName)
 	defer C.free(unsafe.Pointer(cLayerName))
 
-	hLayer := C.OGR_DS_CreateLayer(hDataSource, cLayerName, hSRS.cPtr, ogrGeomType, nil)
+	// Shapefile编码为GBK（中文Windows系统），通过图层创建选项传递
+	cEncodingOption := C.CString("ENCODING=" + SHPEncodingGBK)
+	defer C.free(unsafe.Pointer(cEncodingOption))
+	layerOptions := C.CSLAddString(nil, cEncodingOption)
+	defer C.CSLDestroy(layerOptions)
+
+	hLayer := C.OGR_DS_CreateLayer(hDataSource, cLayerName, hSRS.cPtr, ogrGeomType, layerOptions)
 	if hLayer == nil {
 		return fmt.Errorf("无法创建图层")
 	}
@@ -1448,10 +1457,12 @@ func createShapefileFromLayer(layer PostGISLayer, outputPath, geomType string) e
 		}
 	}
 	cpg := strings.Replace(outputPath, ".shp", ".cpg", -1)
-	createCpgFile(cpg)
+	createCpgFile(cpg, SHPEncodingGBK)
 	return nil
 }
-func createCpgFile(filename string) error {
+
+// createCpgFile 创建.cpg文件，内容为ArcGIS识别的代码页写法（GBK为936）
+func createCpgFile(filename string, encoding string) error {
 	// 创建一个.cpg文件
 	file, err := os.Create(filename)
 	if err != nil {
@@ -1459,8 +1470,7 @@ func createCpgFile(filename string) error {
 	}
 	defer file.Close()
 
-	// 写入内容"GBK"
-	_, err = file.WriteString("GBK")
+	_, err = file.WriteString(shpCPGValue(encoding))
 	if err != nil {
 		return fmt.Errorf("写入文件失败: %v", err)
 	}
@@ -1524,7 +1534,7 @@ func addFeatureToLayer(hLayer C.OGRLayerH, record PostGISRecord) error {
 		hGeometry, _ := CreateGeometryFromWKBHex(record.WKBHex)
 		if hGeometry != nil {
 			C.OGR_F_SetGeometry(hFeature, hGeometry.cPtr)
-			C.OGR_G_DestroyGeometry(hGeometry.cPtr)
+			hGeometry.Close()
 		}
 	}
 
@@ -1586,33 +1596,9 @@ func SHPToPostGIS(shpPath string) (SHPLayerInfo, error) {
 
 	var layer SHPLayerInfo
 
-	// 检测编码
+	// 按检测到的编码打开SHP文件
 	encoding := detectSHPEncoding(shpPath)
-
-	// 设置GDAL编码选项
-	cEncodingKey := C.CString("SHAPE_ENCODING")
-	cEncodingValue := C.CString(encoding)
-
-	cFilenameValue := C.CString("NO")
-
-	C.CPLSetConfigOption(cEncodingKey, cEncodingValue)
-
-	InitializeGDALSHPCoding(encoding)
-
-	defer func() {
-		C.CPLSetConfigOption(cEncodingKey, nil)
-
-		C.free(unsafe.Pointer(cEncodingKey))
-		C.free(unsafe.Pointer(cEncodingValue))
-
-		C.free(unsafe.Pointer(cFilenameValue))
-	}()
-
-	// 打开SHP文件
-	cShpPath := C.CString(shpPath)
-	defer C.free(unsafe.Pointer(cShpPath))
-
-	hDataSource := C.OGROpen(cShpPath, 0, nil)
+	hDataSource := openShapefile(shpPath, false, encoding)
 	if hDataSource == nil {
 		return layer, fmt.Errorf("无法打开SHP文件: %s", shpPath)
 	}
@@ -1647,21 +1633,6 @@ func SHPToPostGIS(shpPath string) (SHPLayerInfo, error) {
 	return layerInfo, nil
 }
 
-func detectSHPEncoding(shpPath string) string {
-	// 检查是否存在.cpg文件（编码文件）
-	cpgPath := strings.TrimSuffix(shpPath, filepath.Ext(shpPath)) + ".cpg"
-	if _, err := os.Stat(cpgPath); err == nil {
-		// 读取.cpg文件内容
-		if content, err := os.ReadFile(cpgPath); err == nil {
-			encoding := strings.TrimSpace(string(content))
-			return encoding
-		}
-	}
-
-	// 默认返回GBK（中国常用编码）
-	return "GBK"
-}
-
 // processSHPLayerDirect 直接处理SHP图层
 func processSHPLayerDirect(hLayer C.OGRLayerH, hTargetSRS C.OGRSpatialReferenceH, shpPath string) (SHPLayerInfo, error) {
 	var layerInfo SHPLayerInfo
@@ -1986,11 +1957,11 @@ func ConvertGeoJSONToGDALLayer(fc *geojson.FeatureCollection, layerName string)
 	}
 
 	// 8. 创建并返回 GDALLayer 结构体
-	return &GDALLayer{
+	return manageLayer(&GDALLayer{
 		layer:   hLayer,
 		dataset: hDataSource,
 		driver:  hDriver,
-	}, nil
+	}), nil
 }
 
 // getOGRGeometryTypeFromOrb 根据 orb.Geometry GeoJSONType 获取对应的 OGRwkbGeometryType
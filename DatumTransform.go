@@ -0,0 +1,751 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+/*
+#include "osgeo_utils.h"
+*/
+import "C"
+import (
+	"fmt"
+	"math"
+	"runtime"
+)
+
+// ============================================================================
+// 七参数/四参数坐标转换（北京54、西安80、CGCS2000之间的转换）
+// ============================================================================
+
+// HelmertConvention 七参数旋转角的符号约定
+type HelmertConvention int
+
+const (
+	// PositionVector 位置矢量约定（EPSG:9606，布尔莎模型常用约定）
+	PositionVector HelmertConvention = iota
+	// CoordinateFrame 坐标框架约定（EPSG:9607），旋转角符号与位置矢量约定相反
+	CoordinateFrame
+)
+
+func (c HelmertConvention) String() string {
+	if c == CoordinateFrame {
+		return "坐标框架"
+	}
+	return "位置矢量"
+}
+
+// SevenParameters 布尔莎-沃尔夫七参数（空间直角坐标系下）
+type SevenParameters struct {
+	Tx         float64           // X平移（米）
+	Ty         float64           // Y平移（米）
+	Tz         float64           // Z平移（米）
+	Rx         float64           // X旋转（角秒）
+	Ry         float64           // Y旋转（角秒）
+	Rz         float64           // Z旋转（角秒）
+	ScalePPM   float64           // 尺度因子（ppm）
+	Convention HelmertConvention // 旋转角约定
+}
+
+// FourParameters 平面四参数（同一投影平面内的相似变换）
+// X' = Dx + Scale * (X*cos(Angle) - Y*sin(Angle))
+// Y' = Dy + Scale * (X*sin(Angle) + Y*cos(Angle))
+type FourParameters struct {
+	Dx    float64 // X平移（米）
+	Dy    float64 // Y平移（米）
+	Scale float64 // 缩放因子（如1.0000035）
+	Angle float64 // 旋转角度（度）
+}
+
+// ControlPointPair 公共点坐标对
+type ControlPointPair struct {
+	ID      string
+	SourceX float64
+	SourceY float64
+	SourceZ float64
+	TargetX float64
+	TargetY float64
+	TargetZ float64
+}
+
+// ControlPointResidual 公共点残差
+type ControlPointResidual struct {
+	ID       string
+	DX       float64 // X方向残差（目标值 - 转换值）
+	DY       float64 // Y方向残差
+	DZ       float64 // Z方向残差（四参数时为0）
+	Distance float64 // 点位残差
+}
+
+// TransformResidualReport 参数解算残差报告
+type TransformResidualReport struct {
+	Model            string  // 转换模型（四参数/七参数）
+	PointCount       int     // 公共点数量
+	DegreesOfFreedom int     // 多余观测数
+	SigmaZero        float64 // 单位权中误差（多余观测数为0时为0）
+	RMSE             float64 // 点位残差均方根
+	MaxResidual      float64 // 最大点位残差
+	MaxResidualID    string  // 最大残差对应的点号
+	Residuals        []ControlPointResidual
+}
+
+// arcSecondToRadian 角秒转弧度
+const arcSecondToRadian = math.Pi / (180 * 3600)
+
+// ApplyGeocentric 对空间直角坐标应用七参数
+func (p *SevenParameters) ApplyGeocentric(x, y, z float64) (float64, float64, float64) {
+	rx := p.Rx * arcSecondToRadian
+	ry := p.Ry * arcSecondToRadian
+	rz := p.Rz * arcSecondToRadian
+	if p.Convention == CoordinateFrame {
+		rx, ry, rz = -rx, -ry, -rz
+	}
+	k := 1 + p.ScalePPM*1e-6
+
+	nx := p.Tx + k*(x-rz*y+ry*z)
+	ny := p.Ty + k*(rz*x+y-rx*z)
+	nz := p.Tz + k*(-ry*x+rx*y+z)
+	return nx, ny, nz
+}
+
+// Apply 对平面坐标应用四参数
+func (p *FourParameters) Apply(x, y float64) (float64, float64) {
+	angle := p.Angle * math.Pi / 180
+	cosA := math.Cos(angle)
+	sinA := math.Sin(angle)
+	return p.Dx + p.Scale*(x*cosA-y*sinA), p.Dy + p.Scale*(x*sinA+y*cosA)
+}
+
+// ============================================================================
+// 椭球与空间直角坐标
+// ============================================================================
+
+// geodeticEllipsoid 参考椭球参数
+type geodeticEllipsoid struct {
+	a  float64 // 长半轴
+	e2 float64 // 第一偏心率平方
+}
+
+// srsEllipsoid 读取空间参考的椭球参数
+func srsEllipsoid(srs C.OGRSpatialReferenceH) (geodeticEllipsoid, error) {
+	var ogrErr C.OGRErr
+	a := float64(C.OSRGetSemiMajor(srs, &ogrErr))
+	if ogrErr != C.OGRERR_NONE || a <= 0 {
+		return geodeticEllipsoid{}, fmt.Errorf("无法获取椭球长半轴")
+	}
+	invFlattening := float64(C.OSRGetInvFlattening(srs, &ogrErr))
+	if ogrErr != C.OGRERR_NONE {
+		return geodeticEllipsoid{}, fmt.Errorf("无法获取椭球扁率")
+	}
+	f := 0.0
+	if invFlattening > 0 {
+		f = 1 / invFlattening
+	}
+	return geodeticEllipsoid{a: a, e2: f * (2 - f)}, nil
+}
+
+// toGeocentric 大地坐标（经度、纬度为度）转空间直角坐标
+func (el geodeticEllipsoid) toGeocentric(lon, lat, h float64) (float64, float64, float64) {
+	lambda := lon * math.Pi / 180
+	phi := lat * math.Pi / 180
+	sinPhi := math.Sin(phi)
+	n := el.a / math.Sqrt(1-el.e2*sinPhi*sinPhi)
+	x := (n + h) * math.Cos(phi) * math.Cos(lambda)
+	y := (n + h) * math.Cos(phi) * math.Sin(lambda)
+	z := (n*(1-el.e2) + h) * sinPhi
+	return x, y, z
+}
+
+// fromGeocentric 空间直角坐标转大地坐标（经度、纬度为度）
+func (el geodeticEllipsoid) fromGeocentric(x, y, z float64) (float64, float64, float64) {
+	p := math.Hypot(x, y)
+	lambda := math.Atan2(y, x)
+	phi := math.Atan2(z, p*(1-el.e2))
+	h := 0.0
+	for i := 0; i < 10; i++ {
+		sinPhi := math.Sin(phi)
+		n := el.a / math.Sqrt(1-el.e2*sinPhi*sinPhi)
+		if math.Abs(math.Cos(phi)) > 1e-12 {
+			h = p/math.Cos(phi) - n
+		} else {
+			h = math.Abs(z) - n*(1-el.e2)
+		}
+		next := math.Atan2(z, p*(1-el.e2*n/(n+h)))
+		if math.Abs(next-phi) < 1e-14 {
+			phi = next
+			break
+		}
+		phi = next
+	}
+	return lambda * 180 / math.Pi, phi * 180 / math.Pi, h
+}
+
+// geographicTransforms 创建投影坐标与对应地理坐标之间的转换器
+type geographicTransforms struct {
+	geogSRS   C.OGRSpatialReferenceH
+	toGeog    C.OGRCoordinateTransformationH
+	fromGeog  C.OGRCoordinateTransformationH
+	ellipsoid geodeticEllipsoid
+}
+
+// newGeographicTransforms 为空间参考创建到其地理坐标系的双向转换
+func newGeographicTransforms(srs C.OGRSpatialReferenceH) (*geographicTransforms, error) {
+	ellipsoid, err := srsEllipsoid(srs)
+	if err != nil {
+		return nil, err
+	}
+
+	geogSRS := C.OSRCloneGeogCS(srs)
+	if geogSRS == nil {
+		return nil, fmt.Errorf("无法获取地理坐标系")
+	}
+	C.OSRSetAxisMappingStrategy(geogSRS, C.OAMS_TRADITIONAL_GIS_ORDER)
+
+	gt := &geographicTransforms{geogSRS: geogSRS, ellipsoid: ellipsoid}
+	gt.toGeog = C.OCTNewCoordinateTransformation(srs, geogSRS)
+	gt.fromGeog = C.OCTNewCoordinateTransformation(geogSRS, srs)
+	if gt.toGeog == nil || gt.fromGeog == nil {
+		gt.destroy()
+		return nil, fmt.Errorf("无法创建地理坐标转换器")
+	}
+	return gt, nil
+}
+
+func (gt *geographicTransforms) destroy() {
+	if gt.toGeog != nil {
+		C.OCTDestroyCoordinateTransformation(gt.toGeog)
+		gt.toGeog = nil
+	}
+	if gt.fromGeog != nil {
+		C.OCTDestroyCoordinateTransformation(gt.fromGeog)
+		gt.fromGeog = nil
+	}
+	if gt.geogSRS != nil {
+		C.OSRDestroySpatialReference(gt.geogSRS)
+		gt.geogSRS = nil
+	}
+}
+
+// toGeocentric 将坐标系下的一个点转换为空间直角坐标
+func (gt *geographicTransforms) toGeocentric(x, y, z float64) (float64, float64, float64, error) {
+	cx, cy, cz := C.double(x), C.double(y), C.double(z)
+	if C.OCTTransform(gt.toGeog, 1, &cx, &cy, &cz) == 0 {
+		return 0, 0, 0, fmt.Errorf("坐标(%f, %f)转换到地理坐标失败", x, y)
+	}
+	gx, gy, gz := gt.ellipsoid.toGeocentric(float64(cx), float64(cy), float64(cz))
+	return gx, gy, gz, nil
+}
+
+// mapGeometryVertices 就地修改几何体的全部顶点
+func mapGeometryVertices(geometry C.OGRGeometryH, fn func(x, y, z float64) (float64, float64, float64)) {
+	if geometry == nil {
+		return
+	}
+
+	subCount := int(C.OGR_G_GetGeometryCount(geometry))
+	if subCount > 0 {
+		for i := 0; i < subCount; i++ {
+			mapGeometryVertices(C.OGR_G_GetGeometryRef(geometry, C.int(i)), fn)
+		}
+		return
+	}
+
+	is3D := C.OGR_G_Is3D(geometry) != 0
+	pointCount := int(C.OGR_G_GetPointCount(geometry))
+	for i := 0; i < pointCount; i++ {
+		var x, y, z C.double
+		C.OGR_G_GetPoint(geometry, C.int(i), &x, &y, &z)
+		nx, ny, nz := fn(float64(x), float64(y), float64(z))
+		if is3D {
+			C.OGR_G_SetPoint(geometry, C.int(i), C.double(nx), C.double(ny), C.double(nz))
+		} else {
+			C.OGR_G_SetPoint_2D(geometry, C.int(i), C.double(nx), C.double(ny))
+		}
+	}
+}
+
+// ============================================================================
+// 几何体与图层转换
+// ============================================================================
+
+// TransformGeometrySevenParams 使用七参数转换几何体，返回新几何体
+// sourceSRS、targetSRS可以是地理坐标系或投影坐标系，分别确定源、目标椭球；
+// 转换流程：源坐标 -> 源椭球大地坐标 -> 空间直角坐标 -> 七参数 -> 目标椭球大地坐标 -> 目标坐标。
+// 二维几何体按大地高为0处理。
+func TransformGeometrySevenParams(geometry *Geometry, sourceSRS, targetSRS *SpatialReference, params *SevenParameters) (*Geometry, error) {
+	if geometry.IsNil() {
+		return nil, fmt.Errorf("几何体为空")
+	}
+	converter, err := newSevenParamConverter(sourceSRS.handle(), targetSRS.handle(), params)
+	runtime.KeepAlive(sourceSRS)
+	runtime.KeepAlive(targetSRS)
+	if err != nil {
+		return nil, err
+	}
+	defer converter.destroy()
+
+	result := C.OGR_G_Clone(geometry.handle())
+	runtime.KeepAlive(geometry)
+	if err := converter.transform(result); err != nil {
+		C.OGR_G_DestroyGeometry(result)
+		return nil, err
+	}
+	return wrapGeometry(result), nil
+}
+
+// TransformGeometryFourParams 使用四参数转换几何体，返回新几何体
+func TransformGeometryFourParams(geometry *Geometry, params *FourParameters) (*Geometry, error) {
+	if geometry.IsNil() {
+		return nil, fmt.Errorf("几何体为空")
+	}
+	if params == nil {
+		return nil, fmt.Errorf("四参数为空")
+	}
+	return geometry.derive("四参数转换", func(h C.OGRGeometryH) C.OGRGeometryH {
+		result := C.OGR_G_Clone(h)
+		mapGeometryVertices(result, func(x, y, z float64) (float64, float64, float64) {
+			nx, ny := params.Apply(x, y)
+			return nx, ny, z
+		})
+		return result
+	})
+}
+
+// sevenParamConverter 七参数几何转换器
+type sevenParamConverter struct {
+	source *geographicTransforms
+	target *geographicTransforms
+	params *SevenParameters
+}
+
+func newSevenParamConverter(sourceSRS, targetSRS C.OGRSpatialReferenceH, params *SevenParameters) (*sevenParamConverter, error) {
+	if params == nil {
+		return nil, fmt.Errorf("七参数为空")
+	}
+	if sourceSRS == nil || targetSRS == nil {
+		return nil, fmt.Errorf("源或目标空间参考为空")
+	}
+
+	source, err := newGeographicTransforms(sourceSRS)
+	if err != nil {
+		return nil, fmt.Errorf("源坐标系: %v", err)
+	}
+	target, err := newGeographicTransforms(targetSRS)
+	if err != nil {
+		source.destroy()
+		return nil, fmt.Errorf("目标坐标系: %v", err)
+	}
+	return &sevenParamConverter{source: source, target: target, params: params}, nil
+}
+
+func (sc *sevenParamConverter) destroy() {
+	sc.source.destroy()
+	sc.target.destroy()
+}
+
+// transform 就地转换几何体
+func (sc *sevenParamConverter) transform(geometry C.OGRGeometryH) error {
+	if C.OGR_G_Transform(geometry, sc.source.toGeog) != C.OGRERR_NONE {
+		return fmt.Errorf("转换到源地理坐标失败")
+	}
+
+	mapGeometryVertices(geometry, func(lon, lat, h float64) (float64, float64, float64) {
+		x, y, z := sc.source.ellipsoid.toGeocentric(lon, lat, h)
+		x, y, z = sc.params.ApplyGeocentric(x, y, z)
+		return sc.target.ellipsoid.fromGeocentric(x, y, z)
+	})
+
+	if C.OGR_G_Transform(geometry, sc.target.fromGeog) != C.OGRERR_NONE {
+		return fmt.Errorf("转换到目标坐标失败")
+	}
+	return nil
+}
+
+// TransformLayerSevenParams 使用七参数对图层进行坐标转换（返回新的内存图层）
+// targetSRS为目标坐标系（如CGCS2000 3度带），决定目标椭球及投影
+func TransformLayerSevenParams(sourceLayer *GDALLayer, params *SevenParameters, targetSRS *SpatialReference) (*GDALLayer, error) {
+	if sourceLayer == nil || sourceLayer.layer == nil {
+		return nil, fmt.Errorf("源图层为空")
+	}
+	sourceSRS := sourceLayer.GetSpatialRef()
+	if sourceSRS == nil {
+		return nil, fmt.Errorf("源图层没有定义空间参考系统")
+	}
+	defer runtime.KeepAlive(targetSRS)
+
+	converter, err := newSevenParamConverter(sourceSRS, targetSRS.handle(), params)
+	if err != nil {
+		return nil, err
+	}
+	defer converter.destroy()
+
+	return transformLayerGeometries(sourceLayer, targetSRS.handle(), "seven_param_result", converter.transform)
+}
+
+// TransformLayerFourParams 使用四参数对图层进行坐标转换（返回新的内存图层）
+// targetSRS为nil时沿用源图层的空间参考
+func TransformLayerFourParams(sourceLayer *GDALLayer, params *FourParameters, targetSRS *SpatialReference) (*GDALLayer, error) {
+	if sourceLayer == nil || sourceLayer.layer == nil {
+		return nil, fmt.Errorf("源图层为空")
+	}
+	if params == nil {
+		return nil, fmt.Errorf("四参数为空")
+	}
+	defer runtime.KeepAlive(targetSRS)
+	resultSRS := targetSRS.handle()
+	if resultSRS == nil {
+		resultSRS = sourceLayer.GetSpatialRef()
+	}
+
+	return transformLayerGeometries(sourceLayer, resultSRS, "four_param_result", func(geometry C.OGRGeometryH) error {
+		mapGeometryVertices(geometry, func(x, y, z float64) (float64, float64, float64) {
+			nx, ny := params.Apply(x, y)
+			return nx, ny, z
+		})
+		return nil
+	})
+}
+
+// transformLayerGeometries 复制图层要素并对几何体逐一应用转换函数
+func transformLayerGeometries(sourceLayer *GDALLayer, targetSRS C.OGRSpatialReferenceH, datasetName string,
+	fn func(geometry C.OGRGeometryH) error) (*GDALLayer, error) {
+
+	sourceDefn := sourceLayer.GetLayerDefn()
+	geomType := C.OGR_FD_GetGeomType(sourceDefn)
+
+	resultLayer, err := newMemoryResultLayer(datasetName, sourceLayer.GetLayerName(), targetSRS, geomType)
+	if err != nil {
+		return nil, err
+	}
+	copyLayerFieldDefns(sourceDefn, resultLayer.layer)
+	resultDefn := resultLayer.GetLayerDefn()
+
+	sourceLayer.ResetReading()
+	defer sourceLayer.ResetReading()
+	for {
+		feature := sourceLayer.GetNextFeatureRow()
+		if feature == nil {
+			break
+		}
+
+		newFeature := C.OGR_F_Create(resultDefn)
+		if newFeature == nil {
+			C.OGR_F_Destroy(feature)
+			continue
+		}
+
+		geometry := C.OGR_F_GetGeometryRef(feature)
+		if geometry != nil {
+			clonedGeom := C.OGR_G_Clone(geometry)
+			if err := fn(clonedGeom); err != nil {
+				fid := int64(C.OGR_F_GetFID(feature))
+				C.OGR_G_DestroyGeometry(clonedGeom)
+				C.OGR_F_Destroy(newFeature)
+				C.OGR_F_Destroy(feature)
+				resultLayer.Close()
+				return nil, fmt.Errorf("要素 %d 坐标转换失败: %v", fid, err)
+			}
+			C.OGR_F_SetGeometryDirectly(newFeature, clonedGeom)
+		}
+		copyFeatureAttributes(feature, newFeature, sourceDefn, resultDefn)
+
+		C.OGR_L_CreateFeature(resultLayer.layer, newFeature)
+		C.OGR_F_Destroy(newFeature)
+		C.OGR_F_Destroy(feature)
+	}
+
+	return resultLayer, nil
+}
+
+// ============================================================================
+// 最小二乘参数解算
+// ============================================================================
+
+// EstimateFourParameters 根据平面公共点以最小二乘法解算四参数（至少2个点）
+func EstimateFourParameters(pairs []ControlPointPair) (*FourParameters, *TransformResidualReport, error) {
+	if len(pairs) < 2 {
+		return nil, nil, fmt.Errorf("四参数解算至少需要2个公共点，当前 %d 个", len(pairs))
+	}
+
+	// 重心化以提高解算稳定性
+	var sxc, syc, txc, tyc float64
+	for _, p := range pairs {
+		sxc += p.SourceX
+		syc += p.SourceY
+		txc += p.TargetX
+		tyc += p.TargetY
+	}
+	n := float64(len(pairs))
+	sxc, syc, txc, tyc = sxc/n, syc/n, txc/n, tyc/n
+
+	// 观测方程：x' = c + a*x - b*y ; y' = d + b*x + a*y，未知数 [c, d, a, b]
+	design := make([][]float64, 0, 2*len(pairs))
+	observed := make([]float64, 0, 2*len(pairs))
+	for _, p := range pairs {
+		x, y := p.SourceX-sxc, p.SourceY-syc
+		design = append(design, []float64{1, 0, x, -y})
+		observed = append(observed, p.TargetX-txc)
+		design = append(design, []float64{0, 1, y, x})
+		observed = append(observed, p.TargetY-tyc)
+	}
+
+	solution, err := solveLeastSquares(design, observed)
+	if err != nil {
+		return nil, nil, err
+	}
+	c, d, a, b := solution[0], solution[1], solution[2], solution[3]
+
+	params := &FourParameters{
+		Dx:    txc + c - a*sxc + b*syc,
+		Dy:    tyc + d - b*sxc - a*syc,
+		Scale: math.Hypot(a, b),
+		Angle: math.Atan2(b, a) * 180 / math.Pi,
+	}
+
+	report := &TransformResidualReport{
+		Model:            "四参数",
+		PointCount:       len(pairs),
+		DegreesOfFreedom: 2*len(pairs) - 4,
+	}
+	for _, p := range pairs {
+		x, y := params.Apply(p.SourceX, p.SourceY)
+		report.add(p.ID, p.TargetX-x, p.TargetY-y, 0)
+	}
+	report.finish()
+
+	return params, report, nil
+}
+
+// EstimateSevenParameters 根据空间直角坐标公共点以最小二乘法解算七参数（至少3个点）
+func EstimateSevenParameters(pairs []ControlPointPair, convention HelmertConvention) (*SevenParameters, *TransformResidualReport, error) {
+	if len(pairs) < 3 {
+		return nil, nil, fmt.Errorf("七参数解算至少需要3个公共点，当前 %d 个", len(pairs))
+	}
+
+	var xc, yc, zc float64
+	for _, p := range pairs {
+		xc += p.SourceX
+		yc += p.SourceY
+		zc += p.SourceZ
+	}
+	n := float64(len(pairs))
+	xc, yc, zc = xc/n, yc/n, zc/n
+
+	// 坐标归一化系数，避免法方程病态
+	norm := 0.0
+	for _, p := range pairs {
+		dx, dy, dz := p.SourceX-xc, p.SourceY-yc, p.SourceZ-zc
+		norm += dx*dx + dy*dy + dz*dz
+	}
+	norm = math.Sqrt(norm / n)
+	if norm == 0 {
+		return nil, nil, fmt.Errorf("公共点重合，无法解算七参数")
+	}
+
+	// 线性化布尔莎模型（位置矢量约定），未知数 [Tx, Ty, Tz, rx, ry, rz, m]
+	design := make([][]float64, 0, 3*len(pairs))
+	observed := make([]float64, 0, 3*len(pairs))
+	for _, p := range pairs {
+		x := (p.SourceX - xc) / norm
+		y := (p.SourceY - yc) / norm
+		z := (p.SourceZ - zc) / norm
+		design = append(design, []float64{1, 0, 0, 0, z, -y, x})
+		observed = append(observed, p.TargetX-p.SourceX)
+		design = append(design, []float64{0, 1, 0, -z, 0, x, y})
+		observed = append(observed, p.TargetY-p.SourceY)
+		design = append(design, []float64{0, 0, 1, y, -x, 0, z})
+		observed = append(observed, p.TargetZ-p.SourceZ)
+	}
+
+	solution, err := solveLeastSquares(design, observed)
+	if err != nil {
+		return nil, nil, err
+	}
+	rx := solution[3] / norm
+	ry := solution[4] / norm
+	rz := solution[5] / norm
+	m := solution[6] / norm
+
+	// 由重心化平移恢复原点平移
+	tx := solution[0] - m*xc + rz*yc - ry*zc
+	ty := solution[1] - m*yc - rz*xc + rx*zc
+	tz := solution[2] - m*zc + ry*xc - rx*yc
+
+	if convention == CoordinateFrame {
+		rx, ry, rz = -rx, -ry, -rz
+	}
+	params := &SevenParameters{
+		Tx:         tx,
+		Ty:         ty,
+		Tz:         tz,
+		Rx:         rx / arcSecondToRadian,
+		Ry:         ry / arcSecondToRadian,
+		Rz:         rz / arcSecondToRadian,
+		ScalePPM:   m * 1e6,
+		Convention: convention,
+	}
+
+	report := &TransformResidualReport{
+		Model:            "七参数",
+		PointCount:       len(pairs),
+		DegreesOfFreedom: 3*len(pairs) - 7,
+	}
+	for _, p := range pairs {
+		x, y, z := params.ApplyGeocentric(p.SourceX, p.SourceY, p.SourceZ)
+		report.add(p.ID, p.TargetX-x, p.TargetY-y, p.TargetZ-z)
+	}
+	report.finish()
+
+	return params, report, nil
+}
+
+// EstimateSevenParametersFromSRS 根据给定坐标系下的公共点解算七参数
+// 公共点坐标分别位于sourceSRS、targetSRS中（可为投影平面坐标或经纬度），
+// Z为大地高，缺省为0。残差在空间直角坐标系中计算（米）。
+func EstimateSevenParametersFromSRS(pairs []ControlPointPair, sourceSRS, targetSRS *SpatialReference,
+	convention HelmertConvention) (*SevenParameters, *TransformResidualReport, error) {
+
+	if sourceSRS.handle() == nil || targetSRS.handle() == nil {
+		return nil, nil, fmt.Errorf("源或目标空间参考为空")
+	}
+	defer runtime.KeepAlive(sourceSRS)
+	defer runtime.KeepAlive(targetSRS)
+
+	source, err := newGeographicTransforms(sourceSRS.handle())
+	if err != nil {
+		return nil, nil, fmt.Errorf("源坐标系: %v", err)
+	}
+	defer source.destroy()
+	target, err := newGeographicTransforms(targetSRS.handle())
+	if err != nil {
+		return nil, nil, fmt.Errorf("目标坐标系: %v", err)
+	}
+	defer target.destroy()
+
+	geocentric := make([]ControlPointPair, len(pairs))
+	for i, p := range pairs {
+		sx, sy, sz, err := source.toGeocentric(p.SourceX, p.SourceY, p.SourceZ)
+		if err != nil {
+			return nil, nil, fmt.Errorf("公共点 %s: %v", p.ID, err)
+		}
+		tx, ty, tz, err := target.toGeocentric(p.TargetX, p.TargetY, p.TargetZ)
+		if err != nil {
+			return nil, nil, fmt.Errorf("公共点 %s: %v", p.ID, err)
+		}
+		geocentric[i] = ControlPointPair{
+			ID:      p.ID,
+			SourceX: sx, SourceY: sy, SourceZ: sz,
+			TargetX: tx, TargetY: ty, TargetZ: tz,
+		}
+	}
+
+	return EstimateSevenParameters(geocentric, convention)
+}
+
+// solveLeastSquares 通过法方程求解最小二乘问题
+func solveLeastSquares(design [][]float64, observed []float64) ([]float64, error) {
+	if len(design) == 0 {
+		return nil, fmt.Errorf("观测方程为空")
+	}
+	u := len(design[0])
+
+	normal := make([][]float64, u)
+	for i := range normal {
+		normal[i] = make([]float64, u+1)
+	}
+	for r, row := range design {
+		for i := 0; i < u; i++ {
+			for j := 0; j < u; j++ {
+				normal[i][j] += row[i] * row[j]
+			}
+			normal[i][u] += row[i] * observed[r]
+		}
+	}
+
+	// 列主元高斯消元
+	for col := 0; col < u; col++ {
+		pivot := col
+		for r := col + 1; r < u; r++ {
+			if math.Abs(normal[r][col]) > math.Abs(normal[pivot][col]) {
+				pivot = r
+			}
+		}
+		if math.Abs(normal[pivot][col]) < 1e-12 {
+			return nil, fmt.Errorf("法方程奇异，公共点分布不足以解算参数")
+		}
+		normal[col], normal[pivot] = normal[pivot], normal[col]
+
+		for r := col + 1; r < u; r++ {
+			factor := normal[r][col] / normal[col][col]
+			for c := col; c <= u; c++ {
+				normal[r][c] -= factor * normal[col][c]
+			}
+		}
+	}
+
+	solution := make([]float64, u)
+	for i := u - 1; i >= 0; i-- {
+		sum := normal[i][u]
+		for j := i + 1; j < u; j++ {
+			sum -= normal[i][j] * solution[j]
+		}
+		solution[i] = sum / normal[i][i]
+	}
+	return solution, nil
+}
+
+// add 记录单个公共点残差
+func (r *TransformResidualReport) add(id string, dx, dy, dz float64) {
+	distance := math.Sqrt(dx*dx + dy*dy + dz*dz)
+	r.Residuals = append(r.Residuals, ControlPointResidual{
+		ID:       id,
+		DX:       dx,
+		DY:       dy,
+		DZ:       dz,
+		Distance: distance,
+	})
+	if distance > r.MaxResidual || r.MaxResidualID == "" {
+		r.MaxResidual = distance
+		r.MaxResidualID = id
+	}
+}
+
+// finish 汇总残差统计
+func (r *TransformResidualReport) finish() {
+	if len(r.Residuals) == 0 {
+		return
+	}
+	sumSquares := 0.0
+	for _, res := range r.Residuals {
+		sumSquares += res.Distance * res.Distance
+	}
+	r.RMSE = math.Sqrt(sumSquares / float64(len(r.Residuals)))
+	if r.DegreesOfFreedom > 0 {
+		r.SigmaZero = math.Sqrt(sumSquares / float64(r.DegreesOfFreedom))
+	}
+}
+
+// PrintReport 打印残差报告
+func (r *TransformResidualReport) PrintReport() {
+	fmt.Printf("\n=== %s解算残差报告 ===\n", r.Model)
+	fmt.Printf("公共点数: %d，多余观测数: %d\n", r.PointCount, r.DegreesOfFreedom)
+	fmt.Printf("单位权中误差: %.4f 米，点位均方根: %.4f 米\n", r.SigmaZero, r.RMSE)
+	fmt.Printf("最大残差: %.4f 米（点号 %s）\n", r.MaxResidual, r.MaxResidualID)
+	fmt.Printf("%-12s %12s %12s %12s %12s\n", "点号", "dX", "dY", "dZ", "点位残差")
+	for _, res := range r.Residuals {
+		fmt.Printf("%-12s %12.4f %12.4f %12.4f %12.4f\n", res.ID, res.DX, res.DY, res.DZ, res.Distance)
+	}
+	fmt.Printf("========================\n\n")
+}
@@ -501,11 +501,11 @@ func DeserializeLayerFromBinary(data []byte) (*DeserializeResult, error) {
 	}
 
 	if result.success == 1 {
-		goResult.Layer = &GDALLayer{
+		goResult.Layer = manageLayer(&GDALLayer{
 			layer:   result.layer,
 			dataset: result.dataSource,
 			driver:  nil,
-		}
+		})
 	} else {
 		if result.errorMessage != nil {
 			goResult.ErrorMessage = C.GoString(result.errorMessage)
@@ -541,7 +541,11 @@ func DeserializeLayerFromFile(filePath string) (*GDALLayer, error) {
 			if featureCount == 0 {
 				log.Printf("文件 %s 是空瓦片，跳过", filePath)
 				// 返回一个空图层或特殊标记
-				return createEmptyMemoryLayer(), nil
+				emptyLayer := createEmptyMemoryLayer()
+				if emptyLayer == nil {
+					return nil, fmt.Errorf("failed to create empty layer for %s", filePath)
+				}
+				return emptyLayer, nil
 			}
 		}
 	}
@@ -556,12 +560,16 @@ func DeserializeLayerFromFile(filePath string) (*GDALLayer, error) {
 	return result.Layer, nil
 }
 func createEmptyMemoryLayer() *GDALLayer {
-	driver := C.OGRGetDriverByName(C.CString("Memory"))
+	driverName := C.CString("Memory")
+	defer C.free(unsafe.Pointer(driverName))
+	driver := C.OGRGetDriverByName(driverName)
 	if driver == nil {
 		return nil
 	}
 
-	ds := C.OGR_Dr_CreateDataSource(driver, C.CString(""), nil)
+	dsName := C.CString("")
+	defer C.free(unsafe.Pointer(dsName))
+	ds := C.OGR_Dr_CreateDataSource(driver, dsName, nil)
 	if ds == nil {
 		return nil
 	}
@@ -575,11 +583,11 @@ func createEmptyMemoryLayer() *GDALLayer {
 		return nil
 	}
 
-	return &GDALLayer{
+	return manageLayer(&GDALLayer{
 		layer:   layer,
 		dataset: ds,
 		driver:  driver,
-	}
+	})
 }
 
 // SafeDeserializeLayerFromFile 安全版本的文件反序列化
@@ -701,8 +709,7 @@ type LayerMetadata struct {
 // CleanupDeserializedLayer 清理反序列化的图层资源（修复版本）
 func (layer *GDALLayer) CleanupDeserializedLayer() {
 	if layer != nil && layer.dataset != nil {
-		C.OGR_DS_Destroy(layer.dataset)
-		layer.dataset = nil
+		layer.Close()
 		layer.layer = nil
 		layer.driver = nil
 	}
@@ -227,6 +227,7 @@ func executeConcurrentEraseAnalysis(tileGroups []GroupTileFiles, resultLayer *GD
 				if err != nil {
 					processingError = fmt.Errorf("合并分块 %d 结果失败: %v", result.index, err)
 					log.Printf("错误: %v", processingError)
+					result.layer.Close()
 					return
 				}
 
@@ -266,6 +267,7 @@ func executeConcurrentEraseAnalysis(tileGroups []GroupTileFiles, resultLayer *GD
 
 	// 等待结果收集完成
 	resultWg.Wait()
+	releaseTaskResults(results)
 
 	if processingError != nil {
 		return processingError
@@ -317,6 +319,7 @@ func processTileGroup(tileGroup GroupTileFiles, config *ParallelGeosConfig) (*GD
 	// 加载layer2的bin文件
 	eraseTileLayer, err := DeserializeLayerFromFile(tileGroup.GPBin.Layer2)
 	if err != nil {
+		inputTileLayer.Close()
 		return nil, fmt.Errorf("加载擦除分块文件失败: %v", err)
 	}
 	defer func() {
@@ -356,7 +359,7 @@ func createEraseAnalysisResultLayer(inputLayer *GDALLayer) (*GDALLayer, error) {
 	}
 
 	resultLayer := &GDALLayer{layer: resultLayerPtr}
-	runtime.SetFinalizer(resultLayer, (*GDALLayer).cleanup)
+	manageLayer(resultLayer)
 
 	// 添加字段定义 - 只需要输入图层的字段
 	err := addLayerFields(resultLayer, inputLayer, "")
@@ -669,6 +672,7 @@ func ExecuteConcurrentEraseAnalysisPG(tileGroups []GroupTileFiles, resultLayer *
 				if err != nil {
 					processingError = fmt.Errorf("合并分块 %d 结果失败: %v", result.index, err)
 					log.Printf("错误: %v", processingError)
+					result.layer.Close()
 					return
 				}
 				// 释放临时图层资源
@@ -699,6 +703,7 @@ func ExecuteConcurrentEraseAnalysisPG(tileGroups []GroupTileFiles, resultLayer *
 	close(results)
 	// 等待结果收集完成
 	resultWg.Wait()
+	releaseTaskResults(results)
 	if processingError != nil {
 		return processingError
 	}
@@ -0,0 +1,836 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+/*
+#include "osgeo_utils.h"
+*/
+import "C"
+import (
+	"fmt"
+	"math"
+	"reflect"
+	"runtime"
+	"strings"
+	"sync"
+	"time"
+	"unsafe"
+)
+
+// ============================================================================
+// 要素字段的类型化访问
+// ============================================================================
+
+// FieldState 字段状态：未设置、显式NULL、有值
+type FieldState int
+
+const (
+	FieldUnset FieldState = iota // 未设置（写出时由数据源决定默认值）
+	FieldNull                    // 显式NULL
+	FieldSet                     // 有值
+)
+
+// fieldIndex 按名称查找字段索引
+func (f *GDALFeature) fieldIndex(fieldName string) (C.int, error) {
+	if f == nil || f.Feature == nil {
+		return -1, fmt.Errorf("要素为空")
+	}
+	cFieldName := C.CString(fieldName)
+	defer C.free(unsafe.Pointer(cFieldName))
+
+	index := C.OGR_F_GetFieldIndex(f.Feature, cFieldName)
+	if index < 0 {
+		return -1, fmt.Errorf("字段 %s 不存在", fieldName)
+	}
+	return index, nil
+}
+
+// GetFieldState 获取字段状态，字段不存在时返回FieldUnset
+func (f *GDALFeature) GetFieldState(fieldName string) FieldState {
+	defer runtime.KeepAlive(f)
+	index, err := f.fieldIndex(fieldName)
+	if err != nil {
+		return FieldUnset
+	}
+	return featureFieldState(f.Feature, index)
+}
+
+// featureFieldState 获取字段状态
+func featureFieldState(feature C.OGRFeatureH, index C.int) FieldState {
+	if C.OGR_F_IsFieldSet(feature, index) == 0 {
+		return FieldUnset
+	}
+	if C.OGR_F_IsFieldNull(feature, index) != 0 {
+		return FieldNull
+	}
+	return FieldSet
+}
+
+// IsFieldNull 字段是否为显式NULL
+func (f *GDALFeature) IsFieldNull(fieldName string) bool {
+	return f.GetFieldState(fieldName) == FieldNull
+}
+
+// IsFieldSet 字段是否有值（非NULL且已设置）
+func (f *GDALFeature) IsFieldSet(fieldName string) bool {
+	return f.GetFieldState(fieldName) == FieldSet
+}
+
+// SetFieldNull 将字段设为NULL
+func (f *GDALFeature) SetFieldNull(fieldName string) error {
+	defer runtime.KeepAlive(f)
+	index, err := f.fieldIndex(fieldName)
+	if err != nil {
+		return err
+	}
+	C.OGR_F_SetFieldNull(f.Feature, index)
+	return nil
+}
+
+// UnsetField 清除字段值（恢复为未设置状态）
+func (f *GDALFeature) UnsetField(fieldName string) error {
+	defer runtime.KeepAlive(f)
+	index, err := f.fieldIndex(fieldName)
+	if err != nil {
+		return err
+	}
+	C.OGR_F_UnsetField(f.Feature, index)
+	return nil
+}
+
+// GetFieldType 获取字段的OGR类型名称（如 "Integer64"、"DateTime"、"StringList"）
+func (f *GDALFeature) GetFieldType(fieldName string) string {
+	defer runtime.KeepAlive(f)
+	index, err := f.fieldIndex(fieldName)
+	if err != nil {
+		return ""
+	}
+	fieldDefn := C.OGR_F_GetFieldDefnRef(f.Feature, index)
+	return C.GoString(C.OGR_GetFieldTypeName(C.OGR_Fld_GetType(fieldDefn)))
+}
+
+// ==================== 读取 ====================
+
+// GetFieldAsInteger64 获取64位整数字段值
+func (f *GDALFeature) GetFieldAsInteger64(fieldName string) int64 {
+	defer runtime.KeepAlive(f)
+	index, err := f.fieldIndex(fieldName)
+	if err != nil {
+		return 0
+	}
+	return int64(C.OGR_F_GetFieldAsInteger64(f.Feature, index))
+}
+
+// GetFieldAsDateTime 获取日期/时间字段值，第二个返回值表示字段是否有值
+// 时区按OGR时区标志解析：本地时间使用time.Local，未知时区按UTC处理，其余使用对应的固定偏移。
+func (f *GDALFeature) GetFieldAsDateTime(fieldName string) (time.Time, bool) {
+	defer runtime.KeepAlive(f)
+	index, err := f.fieldIndex(fieldName)
+	if err != nil || featureFieldState(f.Feature, index) != FieldSet {
+		return time.Time{}, false
+	}
+	return featureDateTime(f.Feature, index)
+}
+
+// featureDateTime 读取日期时间字段
+func featureDateTime(feature C.OGRFeatureH, index C.int) (time.Time, bool) {
+	var year, month, day, hour, minute, tzFlag C.int
+	var second C.float
+	if C.OGR_F_GetFieldAsDateTimeEx(feature, index, &year, &month, &day, &hour, &minute, &second, &tzFlag) == 0 {
+		return time.Time{}, false
+	}
+
+	// 纯时间字段没有日期部分
+	if year == 0 && month == 0 && day == 0 {
+		year, month, day = 1, 1, 1
+	}
+	whole, frac := math.Modf(float64(second))
+	return time.Date(int(year), time.Month(month), int(day), int(hour), int(minute),
+		int(whole), int(math.Round(frac*1e9)), ogrTZFlagLocation(int(tzFlag))), true
+}
+
+// ogrTZFlagLocation OGR时区标志转换为time.Location
+// 0=未知，1=本地时间，100=UTC，100±n 表示偏移 n×15 分钟
+func ogrTZFlagLocation(tzFlag int) *time.Location {
+	switch {
+	case tzFlag == 1:
+		return time.Local
+	case tzFlag == 100 || tzFlag <= 0:
+		return time.UTC
+	default:
+		offset := (tzFlag - 100) * 15 * 60
+		return time.FixedZone("", offset)
+	}
+}
+
+// timeToOGRTZFlag time.Time的时区转换为OGR时区标志
+func timeToOGRTZFlag(t time.Time) int {
+	if t.Location() == time.Local {
+		return 1
+	}
+	_, offset := t.Zone()
+	return 100 + offset/(15*60)
+}
+
+// GetFieldAsBinary 获取二进制字段值
+func (f *GDALFeature) GetFieldAsBinary(fieldName string) []byte {
+	defer runtime.KeepAlive(f)
+	index, err := f.fieldIndex(fieldName)
+	if err != nil || featureFieldState(f.Feature, index) != FieldSet {
+		return nil
+	}
+	return featureBinary(f.Feature, index)
+}
+
+func featureBinary(feature C.OGRFeatureH, index C.int) []byte {
+	var size C.int
+	data := C.OGR_F_GetFieldAsBinary(feature, index, &size)
+	if data == nil || size <= 0 {
+		return []byte{}
+	}
+	return C.GoBytes(unsafe.Pointer(data), size)
+}
+
+// GetFieldAsIntegerList 获取整数列表字段值（IntegerList/Integer64List均可读取）
+func (f *GDALFeature) GetFieldAsIntegerList(fieldName string) []int64 {
+	defer runtime.KeepAlive(f)
+	index, err := f.fieldIndex(fieldName)
+	if err != nil || featureFieldState(f.Feature, index) != FieldSet {
+		return nil
+	}
+	return featureInteger64List(f.Feature, index)
+}
+
+// featureInteger64List 读取整数列表，OGR_F_GetFieldAsInteger64List对IntegerList字段返回NULL，需按类型分别读取
+func featureInteger64List(feature C.OGRFeatureH, index C.int) []int64 {
+	var count C.int
+	if C.OGR_Fld_GetType(C.OGR_F_GetFieldDefnRef(feature, index)) == C.OFTIntegerList {
+		data := C.OGR_F_GetFieldAsIntegerList(feature, index, &count)
+		result := make([]int64, int(count))
+		if data == nil || count <= 0 {
+			return result
+		}
+		for i, v := range unsafe.Slice((*C.int)(unsafe.Pointer(data)), int(count)) {
+			result[i] = int64(v)
+		}
+		return result
+	}
+
+	data := C.OGR_F_GetFieldAsInteger64List(feature, index, &count)
+	result := make([]int64, int(count))
+	if data == nil || count <= 0 {
+		return result
+	}
+	for i, v := range unsafe.Slice((*C.GIntBig)(unsafe.Pointer(data)), int(count)) {
+		result[i] = int64(v)
+	}
+	return result
+}
+
+// GetFieldAsDoubleList 获取浮点列表字段值
+func (f *GDALFeature) GetFieldAsDoubleList(fieldName string) []float64 {
+	defer runtime.KeepAlive(f)
+	index, err := f.fieldIndex(fieldName)
+	if err != nil || featureFieldState(f.Feature, index) != FieldSet {
+		return nil
+	}
+	return featureDoubleList(f.Feature, index)
+}
+
+func featureDoubleList(feature C.OGRFeatureH, index C.int) []float64 {
+	var count C.int
+	data := C.OGR_F_GetFieldAsDoubleList(feature, index, &count)
+	result := make([]float64, int(count))
+	if data == nil || count <= 0 {
+		return result
+	}
+	for i, v := range unsafe.Slice((*C.double)(unsafe.Pointer(data)), int(count)) {
+		result[i] = float64(v)
+	}
+	return result
+}
+
+// GetFieldAsStringList 获取字符串列表字段值
+func (f *GDALFeature) GetFieldAsStringList(fieldName string) []string {
+	defer runtime.KeepAlive(f)
+	index, err := f.fieldIndex(fieldName)
+	if err != nil || featureFieldState(f.Feature, index) != FieldSet {
+		return nil
+	}
+	return featureStringList(f.Feature, index)
+}
+
+func featureStringList(feature C.OGRFeatureH, index C.int) []string {
+	list := C.OGR_F_GetFieldAsStringList(feature, index)
+	result := []string{}
+	if list == nil {
+		return result
+	}
+	for p := list; *p != nil; p = (**C.char)(unsafe.Add(unsafe.Pointer(p), unsafe.Sizeof(*p))) {
+		result = append(result, C.GoString(*p))
+	}
+	return result
+}
+
+// GetFieldValue 按字段类型返回Go值，NULL或未设置时返回nil
+// 类型对应：Integer→int32，Integer64→int64，Real→float64，String→string，
+// Date/Time/DateTime→time.Time，Binary→[]byte，IntegerList/Integer64List→[]int64，
+// RealList→[]float64，StringList→[]string。
+func (f *GDALFeature) GetFieldValue(fieldName string) (interface{}, error) {
+	defer runtime.KeepAlive(f)
+	index, err := f.fieldIndex(fieldName)
+	if err != nil {
+		return nil, err
+	}
+	return featureFieldValue(f.Feature, index), nil
+}
+
+// featureFieldValue 按字段类型读取值
+func featureFieldValue(feature C.OGRFeatureH, index C.int) interface{} {
+	if featureFieldState(feature, index) != FieldSet {
+		return nil
+	}
+	fieldDefn := C.OGR_F_GetFieldDefnRef(feature, index)
+	switch C.OGR_Fld_GetType(fieldDefn) {
+	case C.OFTInteger:
+		return int32(C.OGR_F_GetFieldAsInteger(feature, index))
+	case C.OFTInteger64:
+		return int64(C.OGR_F_GetFieldAsInteger64(feature, index))
+	case C.OFTReal:
+		return float64(C.OGR_F_GetFieldAsDouble(feature, index))
+	case C.OFTDate, C.OFTTime, C.OFTDateTime:
+		if t, ok := featureDateTime(feature, index); ok {
+			return t
+		}
+		return nil
+	case C.OFTBinary:
+		return featureBinary(feature, index)
+	case C.OFTIntegerList, C.OFTInteger64List:
+		return featureInteger64List(feature, index)
+	case C.OFTRealList:
+		return featureDoubleList(feature, index)
+	case C.OFTStringList:
+		return featureStringList(feature, index)
+	default:
+		return C.GoString(C.OGR_F_GetFieldAsString(feature, index))
+	}
+}
+
+// ==================== 写入 ====================
+
+// SetFieldInteger64 设置64位整数字段值
+func (f *GDALFeature) SetFieldInteger64(fieldName string, value int64) error {
+	defer runtime.KeepAlive(f)
+	index, err := f.fieldIndex(fieldName)
+	if err != nil {
+		return err
+	}
+	C.OGR_F_SetFieldInteger64(f.Feature, index, C.GIntBig(value))
+	return nil
+}
+
+// SetFieldDateTime 设置日期/时间字段值，保留时区信息
+func (f *GDALFeature) SetFieldDateTime(fieldName string, value time.Time) error {
+	defer runtime.KeepAlive(f)
+	index, err := f.fieldIndex(fieldName)
+	if err != nil {
+		return err
+	}
+	setFeatureDateTime(f.Feature, index, value)
+	return nil
+}
+
+func setFeatureDateTime(feature C.OGRFeatureH, index C.int, value time.Time) {
+	second := float64(value.Second()) + float64(value.Nanosecond())/1e9
+	C.OGR_F_SetFieldDateTimeEx(feature, index,
+		C.int(value.Year()), C.int(value.Month()), C.int(value.Day()),
+		C.int(value.Hour()), C.int(value.Minute()), C.float(second),
+		C.int(timeToOGRTZFlag(value)))
+}
+
+// SetFieldBinary 设置二进制字段值
+func (f *GDALFeature) SetFieldBinary(fieldName string, value []byte) error {
+	defer runtime.KeepAlive(f)
+	index, err := f.fieldIndex(fieldName)
+	if err != nil {
+		return err
+	}
+	setFeatureBinary(f.Feature, index, value)
+	return nil
+}
+
+func setFeatureBinary(feature C.OGRFeatureH, index C.int, value []byte) {
+	if len(value) == 0 {
+		C.OGR_F_SetFieldBinary(feature, index, 0, nil)
+		return
+	}
+	data := C.CBytes(value)
+	defer C.free(data)
+	C.OGR_F_SetFieldBinary(feature, index, C.int(len(value)), data)
+}
+
+// SetFieldIntegerList 设置整数列表字段值（IntegerList/Integer64List）
+func (f *GDALFeature) SetFieldIntegerList(fieldName string, values []int64) error {
+	defer runtime.KeepAlive(f)
+	index, err := f.fieldIndex(fieldName)
+	if err != nil {
+		return err
+	}
+	setFeatureInteger64List(f.Feature, index, values)
+	return nil
+}
+
+func setFeatureInteger64List(feature C.OGRFeatureH, index C.int, values []int64) {
+	if len(values) == 0 {
+		C.OGR_F_SetFieldInteger64List(feature, index, 0, nil)
+		return
+	}
+	list := make([]C.GIntBig, len(values))
+	for i, v := range values {
+		list[i] = C.GIntBig(v)
+	}
+	C.OGR_F_SetFieldInteger64List(feature, index, C.int(len(list)), &list[0])
+}
+
+// SetFieldDoubleList 设置浮点列表字段值
+func (f *GDALFeature) SetFieldDoubleList(fieldName string, values []float64) error {
+	defer runtime.KeepAlive(f)
+	index, err := f.fieldIndex(fieldName)
+	if err != nil {
+		return err
+	}
+	setFeatureDoubleList(f.Feature, index, values)
+	return nil
+}
+
+func setFeatureDoubleList(feature C.OGRFeatureH, index C.int, values []float64) {
+	if len(values) == 0 {
+		C.OGR_F_SetFieldDoubleList(feature, index, 0, nil)
+		return
+	}
+	list := make([]C.double, len(values))
+	for i, v := range values {
+		list[i] = C.double(v)
+	}
+	C.OGR_F_SetFieldDoubleList(feature, index, C.int(len(list)), &list[0])
+}
+
+// SetFieldStringList 设置字符串列表字段值
+func (f *GDALFeature) SetFieldStringList(fieldName string, values []string) error {
+	defer runtime.KeepAlive(f)
+	index, err := f.fieldIndex(fieldName)
+	if err != nil {
+		return err
+	}
+	setFeatureStringList(f.Feature, index, values)
+	return nil
+}
+
+func setFeatureStringList(feature C.OGRFeatureH, index C.int, values []string) {
+	var list **C.char
+	for _, v := range values {
+		cValue := C.CString(v)
+		list = C.CSLAddString(list, cValue)
+		C.free(unsafe.Pointer(cValue))
+	}
+	C.OGR_F_SetFieldStringList(feature, index, list)
+	C.CSLDestroy(list)
+}
+
+// SetFieldValue 按Go值类型设置字段，nil设为NULL
+// 支持整数、浮点、布尔、字符串、time.Time、[]byte、整数/浮点/字符串切片及其指针。
+func (f *GDALFeature) SetFieldValue(fieldName string, value interface{}) error {
+	defer runtime.KeepAlive(f)
+	index, err := f.fieldIndex(fieldName)
+	if err != nil {
+		return err
+	}
+	return setFieldReflectValue(f.Feature, index, reflect.ValueOf(value))
+}
+
+var (
+	timeType     = reflect.TypeOf(time.Time{})
+	bytesType    = reflect.TypeOf([]byte(nil))
+	geometryType = reflect.TypeOf((*Geometry)(nil))
+)
+
+// setFieldReflectValue 按反射值设置字段
+func setFieldReflectValue(feature C.OGRFeatureH, index C.int, value reflect.Value) error {
+	for value.IsValid() && (value.Kind() == reflect.Ptr || value.Kind() == reflect.Interface) {
+		if value.IsNil() {
+			C.OGR_F_SetFieldNull(feature, index)
+			return nil
+		}
+		value = value.Elem()
+	}
+	if !value.IsValid() {
+		C.OGR_F_SetFieldNull(feature, index)
+		return nil
+	}
+
+	if value.Type() == timeType {
+		setFeatureDateTime(feature, index, value.Interface().(time.Time))
+		return nil
+	}
+
+	switch value.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		C.OGR_F_SetFieldInteger64(feature, index, C.GIntBig(value.Int()))
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		C.OGR_F_SetFieldInteger64(feature, index, C.GIntBig(value.Uint()))
+	case reflect.Float32, reflect.Float64:
+		C.OGR_F_SetFieldDouble(feature, index, C.double(value.Float()))
+	case reflect.Bool:
+		v := 0
+		if value.Bool() {
+			v = 1
+		}
+		C.OGR_F_SetFieldInteger(feature, index, C.int(v))
+	case reflect.String:
+		cValue := C.CString(value.String())
+		C.OGR_F_SetFieldString(feature, index, cValue)
+		C.free(unsafe.Pointer(cValue))
+	case reflect.Slice:
+		if value.Type() == bytesType {
+			setFeatureBinary(feature, index, value.Bytes())
+			return nil
+		}
+		switch value.Type().Elem().Kind() {
+		case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+			list := make([]int64, value.Len())
+			for i := range list {
+				list[i] = value.Index(i).Int()
+			}
+			setFeatureInteger64List(feature, index, list)
+		case reflect.Float32, reflect.Float64:
+			list := make([]float64, value.Len())
+			for i := range list {
+				list[i] = value.Index(i).Float()
+			}
+			setFeatureDoubleList(feature, index, list)
+		case reflect.String:
+			list := make([]string, value.Len())
+			for i := range list {
+				list[i] = value.Index(i).String()
+			}
+			setFeatureStringList(feature, index, list)
+		default:
+			return fmt.Errorf("不支持的切片类型: %s", value.Type())
+		}
+	default:
+		return fmt.Errorf("不支持的字段值类型: %s", value.Type())
+	}
+	return nil
+}
+
+// ============================================================================
+// 结构体映射（Scan/Populate）
+// 标签格式：`ogr:"字段名[,选项]"`
+//   - 未写标签时按结构体字段名匹配（不区分大小写），匹配不到则忽略
+//   - `ogr:"-"` 忽略该字段
+//   - 选项 fid：映射要素FID（整数类型）
+//   - 选项 omitempty：Populate时零值不写入（保持未设置）
+//   - *Geometry 类型字段映射要素几何（Scan得到独立副本，Populate复制写入）
+// 指针类型字段可区分NULL：Scan遇到NULL或未设置时置为nil。
+// ============================================================================
+
+type structFieldMapping struct {
+	index     []int
+	name      string
+	tagged    bool
+	fid       bool
+	geometry  bool
+	omitEmpty bool
+}
+
+var structMappingCache sync.Map // reflect.Type -> []structFieldMapping
+
+// structMappings 解析结构体字段映射（带缓存）
+func structMappings(t reflect.Type) []structFieldMapping {
+	if cached, ok := structMappingCache.Load(t); ok {
+		return cached.([]structFieldMapping)
+	}
+
+	var mappings []structFieldMapping
+	for _, field := range reflect.VisibleFields(t) {
+		if !field.IsExported() || field.Anonymous {
+			continue
+		}
+		tag, tagged := field.Tag.Lookup("ogr")
+		if tag == "-" {
+			continue
+		}
+		parts := strings.Split(tag, ",")
+		mapping := structFieldMapping{
+			index:  field.Index,
+			name:   parts[0],
+			tagged: tagged && parts[0] != "",
+		}
+		for _, option := range parts[1:] {
+			switch strings.TrimSpace(option) {
+			case "fid":
+				mapping.fid = true
+			case "omitempty":
+				mapping.omitEmpty = true
+			}
+		}
+		if field.Type == geometryType {
+			mapping.geometry = true
+		}
+		if mapping.name == "" {
+			mapping.name = field.Name
+		}
+		mappings = append(mappings, mapping)
+	}
+
+	structMappingCache.Store(t, mappings)
+	return mappings
+}
+
+// structFieldIndex 查找映射对应的要素字段索引，未写标签的字段按名称不区分大小写匹配
+func structFieldIndex(defn C.OGRFeatureDefnH, mapping structFieldMapping) int {
+	index := layerFieldIndex(defn, mapping.name)
+	if index >= 0 || mapping.tagged {
+		return index
+	}
+	for i := 0; i < int(C.OGR_FD_GetFieldCount(defn)); i++ {
+		name := C.GoString(C.OGR_Fld_GetNameRef(C.OGR_FD_GetFieldDefn(defn, C.int(i))))
+		if strings.EqualFold(name, mapping.name) {
+			return i
+		}
+	}
+	return -1
+}
+
+// Scan 将要素属性读取到结构体指针dest
+func (f *GDALFeature) Scan(dest interface{}) error {
+	defer runtime.KeepAlive(f)
+	if f == nil || f.Feature == nil {
+		return fmt.Errorf("要素为空")
+	}
+	target := reflect.ValueOf(dest)
+	if target.Kind() != reflect.Ptr || target.IsNil() || target.Elem().Kind() != reflect.Struct {
+		return fmt.Errorf("Scan目标必须是非空结构体指针")
+	}
+	target = target.Elem()
+
+	defn := C.OGR_F_GetDefnRef(f.Feature)
+	for _, mapping := range structMappings(target.Type()) {
+		field := target.FieldByIndex(mapping.index)
+		switch {
+		case mapping.fid:
+			if err := assignFieldValue(field, int64(C.OGR_F_GetFID(f.Feature))); err != nil {
+				return fmt.Errorf("字段 %s: %v", mapping.name, err)
+			}
+		case mapping.geometry:
+			field.Set(reflect.ValueOf(f.GetGeometryCopy()))
+		default:
+			index := structFieldIndex(defn, mapping)
+			if index < 0 {
+				if mapping.tagged {
+					return fmt.Errorf("字段 %s 不存在", mapping.name)
+				}
+				continue
+			}
+			if err := assignFieldValue(field, featureFieldValue(f.Feature, C.int(index))); err != nil {
+				return fmt.Errorf("字段 %s: %v", mapping.name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// assignFieldValue 将字段值赋给结构体字段，nil时置零值
+func assignFieldValue(field reflect.Value, value interface{}) error {
+	if value == nil {
+		field.Set(reflect.Zero(field.Type()))
+		return nil
+	}
+	if field.Kind() == reflect.Ptr {
+		elem := reflect.New(field.Type().Elem())
+		if err := assignFieldValue(elem.Elem(), value); err != nil {
+			return err
+		}
+		field.Set(elem)
+		return nil
+	}
+
+	source := reflect.ValueOf(value)
+	if field.Type() == timeType {
+		if t, ok := value.(time.Time); ok {
+			field.Set(reflect.ValueOf(t))
+			return nil
+		}
+		return fmt.Errorf("无法将 %T 赋值给 time.Time", value)
+	}
+
+	switch field.Kind() {
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		switch v := value.(type) {
+		case int32:
+			field.SetInt(int64(v))
+		case int64:
+			field.SetInt(v)
+		case float64:
+			field.SetInt(int64(v))
+		default:
+			return fmt.Errorf("无法将 %T 赋值给 %s", value, field.Type())
+		}
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		switch v := value.(type) {
+		case int32:
+			field.SetUint(uint64(v))
+		case int64:
+			field.SetUint(uint64(v))
+		default:
+			return fmt.Errorf("无法将 %T 赋值给 %s", value, field.Type())
+		}
+	case reflect.Float32, reflect.Float64:
+		switch v := value.(type) {
+		case int32:
+			field.SetFloat(float64(v))
+		case int64:
+			field.SetFloat(float64(v))
+		case float64:
+			field.SetFloat(v)
+		default:
+			return fmt.Errorf("无法将 %T 赋值给 %s", value, field.Type())
+		}
+	case reflect.Bool:
+		switch v := value.(type) {
+		case int32:
+			field.SetBool(v != 0)
+		case int64:
+			field.SetBool(v != 0)
+		case string:
+			field.SetBool(v == "1" || strings.EqualFold(v, "true") || strings.EqualFold(v, "t"))
+		default:
+			return fmt.Errorf("无法将 %T 赋值给 bool", value)
+		}
+	case reflect.String:
+		if s, ok := value.(string); ok {
+			field.SetString(s)
+		} else {
+			field.SetString(fmt.Sprint(value))
+		}
+	case reflect.Slice:
+		if source.Kind() != reflect.Slice {
+			return fmt.Errorf("无法将 %T 赋值给 %s", value, field.Type())
+		}
+		if source.Type().AssignableTo(field.Type()) {
+			field.Set(source)
+			return nil
+		}
+		list := reflect.MakeSlice(field.Type(), source.Len(), source.Len())
+		for i := 0; i < source.Len(); i++ {
+			if err := assignFieldValue(list.Index(i), source.Index(i).Interface()); err != nil {
+				return err
+			}
+		}
+		field.Set(list)
+	default:
+		return fmt.Errorf("不支持的结构体字段类型: %s", field.Type())
+	}
+	return nil
+}
+
+// Populate 将结构体src的字段写入要素属性（src可为结构体或结构体指针）
+func (f *GDALFeature) Populate(src interface{}) error {
+	defer runtime.KeepAlive(f)
+	if f == nil || f.Feature == nil {
+		return fmt.Errorf("要素为空")
+	}
+	source := reflect.ValueOf(src)
+	for source.Kind() == reflect.Ptr && !source.IsNil() {
+		source = source.Elem()
+	}
+	if source.Kind() != reflect.Struct {
+		return fmt.Errorf("Populate来源必须是结构体")
+	}
+
+	defn := C.OGR_F_GetDefnRef(f.Feature)
+	for _, mapping := range structMappings(source.Type()) {
+		field := source.FieldByIndex(mapping.index)
+		switch {
+		case mapping.fid:
+			if field.CanInt() {
+				C.OGR_F_SetFID(f.Feature, C.GIntBig(field.Int()))
+			}
+		case mapping.geometry:
+			if geom, ok := field.Interface().(*Geometry); ok && !geom.IsNil() {
+				if err := f.SetGeometry(geom); err != nil {
+					return err
+				}
+			}
+		default:
+			index := structFieldIndex(defn, mapping)
+			if index < 0 {
+				if mapping.tagged {
+					return fmt.Errorf("字段 %s 不存在", mapping.name)
+				}
+				continue
+			}
+			if mapping.omitEmpty && field.IsZero() {
+				continue
+			}
+			if err := setFieldReflectValue(f.Feature, C.int(index), field); err != nil {
+				return fmt.Errorf("字段 %s: %v", mapping.name, err)
+			}
+		}
+	}
+	return nil
+}
+
+// ScanAll 读取图层全部要素到切片指针dest（元素可为结构体或结构体指针）
+func (gl *GDALLayer) ScanAll(dest interface{}) error {
+	target := reflect.ValueOf(dest)
+	if target.Kind() != reflect.Ptr || target.IsNil() || target.Elem().Kind() != reflect.Slice {
+		return fmt.Errorf("ScanAll目标必须是切片指针")
+	}
+	slice := target.Elem()
+	elemType := slice.Type().Elem()
+	isPtr := elemType.Kind() == reflect.Ptr
+	structType := elemType
+	if isPtr {
+		structType = elemType.Elem()
+	}
+	if structType.Kind() != reflect.Struct {
+		return fmt.Errorf("切片元素必须是结构体或结构体指针")
+	}
+
+	gl.ResetReading()
+	defer gl.ResetReading()
+	for {
+		feature := gl.GetNextFeature()
+		if feature == nil {
+			break
+		}
+		item := reflect.New(structType)
+		err := feature.Scan(item.Interface())
+		feature.Destroy()
+		if err != nil {
+			return err
+		}
+		if isPtr {
+			slice = reflect.Append(slice, item)
+		} else {
+			slice = reflect.Append(slice, item.Elem())
+		}
+	}
+	target.Elem().Set(slice)
+	return nil
+}
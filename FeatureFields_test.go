@@ -0,0 +1,258 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+import (
+	"reflect"
+	"testing"
+	"time"
+)
+
+// GeoJSON驱动将32位整数数组识别为IntegerList，超出32位的为Integer64List
+const integerListFeatures = `{"type": "FeatureCollection", "features": [
+{"type": "Feature", "properties": {"ids": [1, 2, 3], "big": [1, 5000000000]}, "geometry": {"type": "Point", "coordinates": [116.1, 39.1]}}
+]}`
+
+func TestIntegerListFields(t *testing.T) {
+	layer := testGeoJSONLayer(t, integerListFeatures)
+	feature := layer.GetNextFeature()
+	if feature == nil {
+		t.Fatal("读取要素失败")
+	}
+	defer feature.Destroy()
+
+	if fieldType := feature.GetFieldType("ids"); fieldType != "IntegerList" {
+		t.Fatalf("ids字段类型应为IntegerList，实际 %s", fieldType)
+	}
+	if fieldType := feature.GetFieldType("big"); fieldType != "Integer64List" {
+		t.Fatalf("big字段类型应为Integer64List，实际 %s", fieldType)
+	}
+
+	if got := feature.GetFieldAsIntegerList("ids"); !reflect.DeepEqual(got, []int64{1, 2, 3}) {
+		t.Fatalf("GetFieldAsIntegerList(ids) = %v", got)
+	}
+	if got := feature.GetFieldAsIntegerList("big"); !reflect.DeepEqual(got, []int64{1, 5000000000}) {
+		t.Fatalf("GetFieldAsIntegerList(big) = %v", got)
+	}
+
+	value, err := feature.GetFieldValue("ids")
+	if err != nil || !reflect.DeepEqual(value, []int64{1, 2, 3}) {
+		t.Fatalf("GetFieldValue(ids) = %v, %v", value, err)
+	}
+
+	var row struct {
+		IDs []int64 `ogr:"ids"`
+		Big []int64 `ogr:"big"`
+	}
+	if err := feature.Scan(&row); err != nil {
+		t.Fatalf("Scan失败: %v", err)
+	}
+	if !reflect.DeepEqual(row.IDs, []int64{1, 2, 3}) || !reflect.DeepEqual(row.Big, []int64{1, 5000000000}) {
+		t.Fatalf("Scan结果不正确: %+v", row)
+	}
+}
+
+// 第一个要素note为显式null，第二个要素没有note属性（未设置）
+const fieldStateFeatures = `{"type": "FeatureCollection", "features": [
+{"type": "Feature", "properties": {"name": "甲", "cnt": 3, "value": 1.5, "ts": "2024-05-01T08:30:15.250+08:00", "note": null}, "geometry": {"type": "Point", "coordinates": [116.1, 39.1]}},
+{"type": "Feature", "properties": {"name": "乙", "cnt": 4, "value": 2.5, "ts": "2024-05-02T00:00:00Z"}, "geometry": {"type": "Point", "coordinates": [116.2, 39.2]}}
+]}`
+
+func TestFieldNullAndUnset(t *testing.T) {
+	layer := testGeoJSONLayer(t, fieldStateFeatures)
+	first := layer.GetNextFeature()
+	second := layer.GetNextFeature()
+	if first == nil || second == nil {
+		t.Fatal("读取要素失败")
+	}
+	defer first.Destroy()
+	defer second.Destroy()
+
+	if state := first.GetFieldState("note"); state != FieldNull || !first.IsFieldNull("note") {
+		t.Fatalf("null属性应为FieldNull，实际 %v", state)
+	}
+	if state := second.GetFieldState("note"); state != FieldUnset || second.IsFieldNull("note") || second.IsFieldSet("note") {
+		t.Fatalf("缺少的属性应为FieldUnset，实际 %v", state)
+	}
+	if !first.IsFieldSet("name") {
+		t.Fatal("name应为FieldSet")
+	}
+	if state := first.GetFieldState("missing"); state != FieldUnset {
+		t.Fatalf("不存在的字段应返回FieldUnset，实际 %v", state)
+	}
+
+	// NULL与未设置的字段读取时都没有值
+	if value, err := first.GetFieldValue("note"); err != nil || value != nil {
+		t.Fatalf("GetFieldValue(note) = %v, %v", value, err)
+	}
+
+	if err := first.SetFieldNull("name"); err != nil {
+		t.Fatalf("SetFieldNull失败: %v", err)
+	}
+	if state := first.GetFieldState("name"); state != FieldNull {
+		t.Fatalf("SetFieldNull后应为FieldNull，实际 %v", state)
+	}
+	if err := first.UnsetField("name"); err != nil {
+		t.Fatalf("UnsetField失败: %v", err)
+	}
+	if state := first.GetFieldState("name"); state != FieldUnset {
+		t.Fatalf("UnsetField后应为FieldUnset，实际 %v", state)
+	}
+	if err := first.SetFieldNull("missing"); err == nil {
+		t.Fatal("不存在的字段应报错")
+	}
+	if err := first.UnsetField("missing"); err == nil {
+		t.Fatal("不存在的字段应报错")
+	}
+}
+
+func TestFieldDateTimeTimezone(t *testing.T) {
+	layer := testGeoJSONLayer(t, fieldStateFeatures)
+	feature := layer.GetNextFeature()
+	if feature == nil {
+		t.Fatal("读取要素失败")
+	}
+	defer feature.Destroy()
+
+	if fieldType := feature.GetFieldType("ts"); fieldType != "DateTime" {
+		t.Fatalf("ts字段类型应为DateTime，实际 %s", fieldType)
+	}
+	got, ok := feature.GetFieldAsDateTime("ts")
+	want := time.Date(2024, 5, 1, 8, 30, 15, 250e6, time.FixedZone("", 8*3600))
+	if !ok || !got.Equal(want) {
+		t.Fatalf("GetFieldAsDateTime(ts) = %v, %v，期望 %v", got, ok, want)
+	}
+	if _, offset := got.Zone(); offset != 8*3600 {
+		t.Fatalf("时区偏移应为+08:00，实际 %d 秒", offset)
+	}
+
+	cases := []struct {
+		name     string
+		location *time.Location
+	}{
+		{"UTC", time.UTC},
+		{"本地时间", time.Local},
+		{"西五区", time.FixedZone("", -5*3600)},
+		{"东五区半", time.FixedZone("", 5*3600+30*60)},
+	}
+	for _, c := range cases {
+		value := time.Date(2023, 12, 31, 23, 59, 58, 500e6, c.location)
+		if err := feature.SetFieldDateTime("ts", value); err != nil {
+			t.Fatalf("%s: SetFieldDateTime失败: %v", c.name, err)
+		}
+		got, ok := feature.GetFieldAsDateTime("ts")
+		if !ok || !got.Equal(value) {
+			t.Fatalf("%s: 读取 %v，期望 %v", c.name, got, value)
+		}
+		_, gotOffset := got.Zone()
+		_, wantOffset := value.Zone()
+		if gotOffset != wantOffset {
+			t.Fatalf("%s: 时区偏移 %d，期望 %d", c.name, gotOffset, wantOffset)
+		}
+		if c.location == time.Local && got.Location() != time.Local {
+			t.Fatalf("%s: 应读取为time.Local，实际 %v", c.name, got.Location())
+		}
+	}
+
+	// NULL的日期字段没有值
+	if err := feature.SetFieldNull("ts"); err != nil {
+		t.Fatalf("SetFieldNull失败: %v", err)
+	}
+	if _, ok := feature.GetFieldAsDateTime("ts"); ok {
+		t.Fatal("NULL字段不应返回日期")
+	}
+}
+
+type fieldStateRow struct {
+	ID    int64     `ogr:",fid"`
+	Name  string    // 未写标签，按名称不区分大小写匹配
+	Count int       `ogr:"cnt"`
+	Value float64   `ogr:"value,omitempty"`
+	Time  time.Time `ogr:"ts"`
+	Note  *string   `ogr:"note"`
+	Geom  *Geometry
+	Skip  string `ogr:"-"`
+}
+
+func TestFeatureScanPopulate(t *testing.T) {
+	layer := testGeoJSONLayer(t, fieldStateFeatures)
+	first := layer.GetNextFeature()
+	second := layer.GetNextFeature()
+	if first == nil || second == nil {
+		t.Fatal("读取要素失败")
+	}
+	defer first.Destroy()
+	defer second.Destroy()
+
+	var row fieldStateRow
+	if err := first.Scan(&row); err != nil {
+		t.Fatalf("Scan失败: %v", err)
+	}
+	want := time.Date(2024, 5, 1, 8, 30, 15, 250e6, time.FixedZone("", 8*3600))
+	if row.Name != "甲" || row.Count != 3 || row.Value != 1.5 || !row.Time.Equal(want) {
+		t.Fatalf("Scan结果不正确: %+v", row)
+	}
+	if row.Note != nil {
+		t.Fatalf("NULL字段应扫描为nil，实际 %q", *row.Note)
+	}
+	if row.Geom == nil || row.Geom.IsNil() {
+		t.Fatal("Scan应得到几何副本")
+	}
+
+	// Populate写入第二个要素：nil指针写为NULL，omitempty的零值保持未设置
+	note := "备注"
+	if err := second.UnsetField("value"); err != nil {
+		t.Fatalf("UnsetField失败: %v", err)
+	}
+	populated := fieldStateRow{ID: 42, Name: "丙", Count: 7, Time: want, Note: &note}
+	if err := second.Populate(&populated); err != nil {
+		t.Fatalf("Populate失败: %v", err)
+	}
+	if state := second.GetFieldState("value"); state != FieldUnset {
+		t.Fatalf("omitempty零值不应写入，实际 %v", state)
+	}
+
+	var back fieldStateRow
+	if err := second.Scan(&back); err != nil {
+		t.Fatalf("Scan失败: %v", err)
+	}
+	if back.ID != 42 || back.Name != "丙" || back.Count != 7 || !back.Time.Equal(want) || back.Note == nil || *back.Note != note {
+		t.Fatalf("Populate后Scan结果不正确: %+v", back)
+	}
+
+	populated.Note = nil
+	if err := second.Populate(populated); err != nil {
+		t.Fatalf("Populate失败: %v", err)
+	}
+	if state := second.GetFieldState("note"); state != FieldNull {
+		t.Fatalf("nil指针应写为NULL，实际 %v", state)
+	}
+
+	// 标签指定的字段不存在时报错
+	var missing struct {
+		Code string `ogr:"code"`
+	}
+	if err := first.Scan(&missing); err == nil {
+		t.Fatal("Scan不存在的字段应报错")
+	}
+	if err := first.Populate(missing); err == nil {
+		t.Fatal("Populate不存在的字段应报错")
+	}
+	if err := first.Scan(row); err == nil {
+		t.Fatal("Scan非指针应报错")
+	}
+}
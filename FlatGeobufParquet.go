@@ -0,0 +1,237 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+/*
+#include "osgeo_utils.h"
+*/
+import "C"
+import (
+	"fmt"
+	"os"
+	"unsafe"
+)
+
+// ========== FlatGeobuf / GeoParquet 读写 ==========
+// FlatGeobuf写入时生成Hilbert R-tree空间索引，GeoParquet写入时生成bbox覆盖列，
+// 两者读取时均可通过范围过滤只读取相关数据。
+
+// ReadFlatGeobufFile 读取FlatGeobuf文件
+func (r *FileGeoReader) ReadFlatGeobufFile(layerName ...string) (*GDALLayer, error) {
+	if r.FileType != "fgb" {
+		return nil, fmt.Errorf("文件类型不是FlatGeobuf: %s", r.FileType)
+	}
+	return openVectorFileLayer(r.FilePath, "FlatGeobuf", layerName...)
+}
+
+// ReadFlatGeobufFileInBBox 读取FlatGeobuf文件中与范围相交的要素
+// 返回的图层已设置空间过滤，遍历时利用文件内的空间索引只读取范围内的要素。
+func (r *FileGeoReader) ReadFlatGeobufFileInBBox(minX, minY, maxX, maxY float64, layerName ...string) (*GDALLayer, error) {
+	layer, err := r.ReadFlatGeobufFile(layerName...)
+	if err != nil {
+		return nil, err
+	}
+	setLayerBBoxFilter(layer, minX, minY, maxX, maxY)
+	return layer, nil
+}
+
+// ReadGeoParquetFile 读取GeoParquet文件
+func (r *FileGeoReader) ReadGeoParquetFile(layerName ...string) (*GDALLayer, error) {
+	if r.FileType != "parquet" {
+		return nil, fmt.Errorf("文件类型不是GeoParquet: %s", r.FileType)
+	}
+	return openVectorFileLayer(r.FilePath, "Parquet", layerName...)
+}
+
+// ReadGeoParquetFileInBBox 读取GeoParquet文件中与范围相交的要素
+// 文件含bbox覆盖列或行组统计信息时，不相交的行组会被直接跳过。
+func (r *FileGeoReader) ReadGeoParquetFileInBBox(minX, minY, maxX, maxY float64, layerName ...string) (*GDALLayer, error) {
+	layer, err := r.ReadGeoParquetFile(layerName...)
+	if err != nil {
+		return nil, err
+	}
+	setLayerBBoxFilter(layer, minX, minY, maxX, maxY)
+	return layer, nil
+}
+
+// WriteFlatGeobufFile 写入FlatGeobuf文件（带空间索引）
+func (w *FileGeoWriter) WriteFlatGeobufFile(sourceLayer *GDALLayer, layerName string) error {
+	if w.FileType != "fgb" {
+		return fmt.Errorf("文件类型不是FlatGeobuf: %s", w.FileType)
+	}
+	return w.writeVectorFile(sourceLayer, layerName, "FlatGeobuf", []string{
+		"SPATIAL_INDEX=YES",
+	})
+}
+
+// WriteGeoParquetFile 写入GeoParquet文件
+// 几何以WKB编码，使用Snappy压缩，并写入bbox覆盖列以支持范围过滤读取。
+func (w *FileGeoWriter) WriteGeoParquetFile(sourceLayer *GDALLayer, layerName string) error {
+	if w.FileType != "parquet" {
+		return fmt.Errorf("文件类型不是GeoParquet: %s", w.FileType)
+	}
+	return w.writeVectorFile(sourceLayer, layerName, "Parquet", []string{
+		"GEOMETRY_ENCODING=WKB",
+		"COMPRESSION=SNAPPY",
+		"WRITE_COVERING_BBOX=YES",
+	})
+}
+
+// openVectorFileLayer 以只读方式打开单文件矢量数据的图层
+func openVectorFileLayer(filePath string, driverName string, layerName ...string) (*GDALLayer, error) {
+	cDriverName := C.CString(driverName)
+	defer C.free(unsafe.Pointer(cDriverName))
+	driver := C.OGRGetDriverByName(cDriverName)
+	if driver == nil {
+		return nil, fmt.Errorf("无法获取%s驱动，请确认GDAL已编译该驱动", driverName)
+	}
+
+	cFilePath := C.CString(filePath)
+	defer C.free(unsafe.Pointer(cFilePath))
+
+	dataset := C.OGROpen(cFilePath, C.int(0), nil)
+	if dataset == nil {
+		return nil, fmt.Errorf("无法打开%s文件: %s", driverName, filePath)
+	}
+
+	var layer C.OGRLayerH
+	if len(layerName) > 0 && layerName[0] != "" {
+		cLayerName := C.CString(layerName[0])
+		defer C.free(unsafe.Pointer(cLayerName))
+		layer = C.OGR_DS_GetLayerByName(dataset, cLayerName)
+	} else if C.OGR_DS_GetLayerCount(dataset) > 0 {
+		layer = C.OGR_DS_GetLayer(dataset, C.int(0))
+	}
+
+	if layer == nil {
+		C.OGR_DS_Destroy(dataset)
+		return nil, fmt.Errorf("无法获取图层")
+	}
+
+	gdalLayer := &GDALLayer{
+		layer:   layer,
+		dataset: dataset,
+		driver:  driver,
+	}
+
+	manageLayer(gdalLayer)
+	return gdalLayer, nil
+}
+
+// setLayerBBoxFilter 设置矩形空间过滤并重置读取位置
+func setLayerBBoxFilter(layer *GDALLayer, minX, minY, maxX, maxY float64) {
+	C.OGR_L_SetSpatialFilterRect(layer.layer, C.double(minX), C.double(minY), C.double(maxX), C.double(maxY))
+	layer.ResetReading()
+}
+
+// writeVectorFile 创建单图层矢量文件并复制源图层的字段与要素
+func (w *FileGeoWriter) writeVectorFile(sourceLayer *GDALLayer, layerName string, driverName string, layerOptions []string) error {
+	if sourceLayer == nil || sourceLayer.layer == nil {
+		return fmt.Errorf("源图层为空")
+	}
+	if layerName == "" {
+		layerName = sourceLayer.GetLayerName()
+	}
+
+	if w.Overwrite {
+		if _, err := os.Stat(w.FilePath); err == nil {
+			os.Remove(w.FilePath)
+		}
+	}
+
+	cDriverName := C.CString(driverName)
+	defer C.free(unsafe.Pointer(cDriverName))
+	driver := C.OGRGetDriverByName(cDriverName)
+	if driver == nil {
+		return fmt.Errorf("无法获取%s驱动，请确认GDAL已编译该驱动", driverName)
+	}
+
+	cFilePath := C.CString(w.FilePath)
+	defer C.free(unsafe.Pointer(cFilePath))
+
+	dataset := C.OGR_Dr_CreateDataSource(driver, cFilePath, nil)
+	if dataset == nil {
+		return fmt.Errorf("无法创建%s文件: %s", driverName, w.FilePath)
+	}
+	defer C.OGR_DS_Destroy(dataset)
+
+	var cOptions **C.char
+	for _, option := range layerOptions {
+		cOption := C.CString(option)
+		cOptions = C.CSLAddString(cOptions, cOption)
+		C.free(unsafe.Pointer(cOption))
+	}
+	defer C.CSLDestroy(cOptions)
+
+	sourceDefn := sourceLayer.GetLayerDefn()
+	geomType := C.OGR_FD_GetGeomType(sourceDefn)
+	srs := sourceLayer.GetSpatialRef()
+
+	cLayerName := C.CString(layerName)
+	defer C.free(unsafe.Pointer(cLayerName))
+
+	newLayer := C.OGR_DS_CreateLayer(dataset, cLayerName, srs, geomType, cOptions)
+	if newLayer == nil {
+		return fmt.Errorf("无法创建图层: %s", layerName)
+	}
+
+	if err := w.copyFieldDefinitions(sourceDefn, newLayer, nil); err != nil {
+		return err
+	}
+
+	if err := w.copyFeatures(sourceLayer, newLayer); err != nil {
+		return err
+	}
+
+	return nil
+}
+
+// ReadFlatGeobufLayer 直接读取FlatGeobuf图层
+func ReadFlatGeobufLayer(filePath string, layerName ...string) (*GDALLayer, error) {
+	reader, err := NewFileGeoReader(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return reader.ReadFlatGeobufFile(layerName...)
+}
+
+// ReadGeoParquetLayer 直接读取GeoParquet图层
+func ReadGeoParquetLayer(filePath string, layerName ...string) (*GDALLayer, error) {
+	reader, err := NewFileGeoReader(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return reader.ReadGeoParquetFile(layerName...)
+}
+
+// WriteFlatGeobufLayer 直接写入FlatGeobuf图层
+func WriteFlatGeobufLayer(sourceLayer *GDALLayer, filePath string, layerName string, overwrite bool) error {
+	writer, err := NewFileGeoWriter(filePath, overwrite)
+	if err != nil {
+		return err
+	}
+	return writer.WriteFlatGeobufFile(sourceLayer, layerName)
+}
+
+// WriteGeoParquetLayer 直接写入GeoParquet图层
+func WriteGeoParquetLayer(sourceLayer *GDALLayer, filePath string, layerName string, overwrite bool) error {
+	writer, err := NewFileGeoWriter(filePath, overwrite)
+	if err != nil {
+		return err
+	}
+	return writer.WriteGeoParquetFile(sourceLayer, layerName)
+}
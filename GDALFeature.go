@@ -22,6 +22,7 @@ package Gogeo
 import "C"
 import (
 	"fmt"
+	"runtime"
 	"unsafe"
 )
 
@@ -57,35 +58,38 @@ func (f *GDALFeature) IsValid() bool {
 	return f != nil && f.Feature != nil
 }
 
-// GetGeometry 获取几何对象
-func (f *GDALFeature) GetGeometry() C.OGRGeometryH {
+// GetGeometry 获取几何对象（借用引用，持有要素防止其被回收；要素Destroy或几何被替换后失效，无需Close）
+func (f *GDALFeature) GetGeometry() *Geometry {
 	if f == nil || f.Feature == nil {
 		return nil
 	}
-	return C.OGR_F_GetGeometryRef(f.Feature)
+	return borrowGeometry(C.OGR_F_GetGeometryRef(f.Feature), f)
 }
 
-// GetGeometryCopy 获取几何对象的副本（调用者需要负责释放）
-func (f *GDALFeature) GetGeometryCopy() C.OGRGeometryH {
+// GetGeometryCopy 获取几何对象的副本（独立于要素，可调用Close释放，否则由终结器回收）
+func (f *GDALFeature) GetGeometryCopy() *Geometry {
 	if f == nil || f.Feature == nil {
 		return nil
 	}
+	defer runtime.KeepAlive(f)
 	geom := C.OGR_F_GetGeometryRef(f.Feature)
 	if geom == nil {
 		return nil
 	}
-	return C.OGR_G_Clone(geom)
+	return wrapGeometry(C.OGR_G_Clone(geom))
 }
 
-// SetGeometry 设置几何对象
-func (f *GDALFeature) SetGeometry(geom C.OGRGeometryH) error {
+// SetGeometry 设置几何对象（复制，geom仍由调用方持有）
+func (f *GDALFeature) SetGeometry(geom *Geometry) error {
 	if f == nil || f.Feature == nil {
 		return fmt.Errorf("要素为空")
 	}
-	if geom == nil {
+	if geom.IsNil() {
 		return fmt.Errorf("几何为空")
 	}
-	result := C.OGR_F_SetGeometry(f.Feature, geom)
+	result := C.OGR_F_SetGeometry(f.Feature, geom.handle())
+	runtime.KeepAlive(f)
+	runtime.KeepAlive(geom)
 	if result != C.OGRERR_NONE {
 		return fmt.Errorf("设置几何失败，错误码: %d", result)
 	}
@@ -93,14 +97,20 @@ func (f *GDALFeature) SetGeometry(geom C.OGRGeometryH) error {
 }
 
 // SetGeometryDirectly 设置几何对象（转移所有权，不复制）
-func (f *GDALFeature) SetGeometryDirectly(geom C.OGRGeometryH) error {
+// 成功后geom变为借用引用，由要素负责释放
+func (f *GDALFeature) SetGeometryDirectly(geom *Geometry) error {
 	if f == nil || f.Feature == nil {
 		return fmt.Errorf("要素为空")
 	}
-	result := C.OGR_F_SetGeometryDirectly(f.Feature, geom)
+	if geom.IsNil() || geom.IsBorrowed() {
+		return fmt.Errorf("几何为空或不属于调用方")
+	}
+	result := C.OGR_F_SetGeometryDirectly(f.Feature, geom.handle())
+	runtime.KeepAlive(f)
 	if result != C.OGRERR_NONE {
 		return fmt.Errorf("设置几何失败，错误码: %d", result)
 	}
+	geom.release(f)
 	return nil
 }
 
@@ -109,6 +119,7 @@ func (f *GDALFeature) GetFieldIndex(fieldName string) int {
 	if f == nil || f.Feature == nil {
 		return -1
 	}
+	defer runtime.KeepAlive(f)
 	cFieldName := C.CString(fieldName)
 	defer C.free(unsafe.Pointer(cFieldName))
 	return int(C.OGR_F_GetFieldIndex(f.Feature, cFieldName))
@@ -119,6 +130,7 @@ func (f *GDALFeature) GetFieldCount() int {
 	if f == nil || f.Feature == nil {
 		return 0
 	}
+	defer runtime.KeepAlive(f)
 	return int(C.OGR_F_GetFieldCount(f.Feature))
 }
 
@@ -127,6 +139,7 @@ func (f *GDALFeature) SetFieldString(fieldName, value string) error {
 	if f == nil || f.Feature == nil {
 		return fmt.Errorf("要素为空")
 	}
+	defer runtime.KeepAlive(f)
 	cFieldName := C.CString(fieldName)
 	defer C.free(unsafe.Pointer(cFieldName))
 	cValue := C.CString(value)
@@ -145,6 +158,7 @@ func (f *GDALFeature) SetFieldStringByIndex(index int, value string) {
 	if f == nil || f.Feature == nil {
 		return
 	}
+	defer runtime.KeepAlive(f)
 	cValue := C.CString(value)
 	defer C.free(unsafe.Pointer(cValue))
 	C.OGR_F_SetFieldString(f.Feature, C.int(index), cValue)
@@ -155,6 +169,7 @@ func (f *GDALFeature) SetFieldInteger(fieldName string, value int) error {
 	if f == nil || f.Feature == nil {
 		return fmt.Errorf("要素为空")
 	}
+	defer runtime.KeepAlive(f)
 	cFieldName := C.CString(fieldName)
 	defer C.free(unsafe.Pointer(cFieldName))
 
@@ -171,6 +186,7 @@ func (f *GDALFeature) SetFieldDouble(fieldName string, value float64) error {
 	if f == nil || f.Feature == nil {
 		return fmt.Errorf("要素为空")
 	}
+	defer runtime.KeepAlive(f)
 	cFieldName := C.CString(fieldName)
 	defer C.free(unsafe.Pointer(cFieldName))
 
@@ -187,6 +203,7 @@ func (f *GDALFeature) GetFieldAsString(fieldName string) string {
 	if f == nil || f.Feature == nil {
 		return ""
 	}
+	defer runtime.KeepAlive(f)
 	cFieldName := C.CString(fieldName)
 	defer C.free(unsafe.Pointer(cFieldName))
 
@@ -202,6 +219,7 @@ func (f *GDALFeature) GetFieldAsStringByIndex(index int) string {
 	if f == nil || f.Feature == nil {
 		return ""
 	}
+	defer runtime.KeepAlive(f)
 	return C.GoString(C.OGR_F_GetFieldAsString(f.Feature, C.int(index)))
 }
 
@@ -210,6 +228,7 @@ func (f *GDALFeature) GetFieldAsInteger(fieldName string) int {
 	if f == nil || f.Feature == nil {
 		return 0
 	}
+	defer runtime.KeepAlive(f)
 	cFieldName := C.CString(fieldName)
 	defer C.free(unsafe.Pointer(cFieldName))
 
@@ -225,6 +244,7 @@ func (f *GDALFeature) GetFieldAsDouble(fieldName string) float64 {
 	if f == nil || f.Feature == nil {
 		return 0
 	}
+	defer runtime.KeepAlive(f)
 	cFieldName := C.CString(fieldName)
 	defer C.free(unsafe.Pointer(cFieldName))
 
@@ -240,16 +260,22 @@ func (f *GDALFeature) Clone() *GDALFeature {
 	if f == nil || f.Feature == nil {
 		return nil
 	}
+	defer runtime.KeepAlive(f)
 	cloned := C.OGR_F_Clone(f.Feature)
 	if cloned == nil {
 		return nil
 	}
-	return &GDALFeature{Feature: cloned}
+	return manageFeature(&GDALFeature{Feature: cloned})
 }
 
-// Destroy 销毁要素，释放内存
+// Destroy 销毁要素，释放内存（重复调用安全）
 func (f *GDALFeature) Destroy() {
-	if f != nil && f.Feature != nil {
+	if f == nil {
+		return
+	}
+	runtime.SetFinalizer(f, nil)
+	untrackResource(resourceKey(ResourceFeature, unsafe.Pointer(f)))
+	if f.Feature != nil {
 		C.OGR_F_Destroy(f.Feature)
 		f.Feature = nil
 	}
@@ -31,6 +31,8 @@ type GDALLayer struct {
 	layer   C.OGRLayerH
 	dataset C.OGRDataSourceH
 	driver  C.OGRSFDriverH
+
+	resultSet bool // layer为OGR_DS_ExecuteSQL返回的结果图层，关闭前需释放
 }
 
 // GetFeatureCount 获取要素数量
@@ -130,7 +132,7 @@ func (gl *GDALLayer) GetNextFeature() *GDALFeature {
 	if Feature == nil {
 		return nil
 	}
-	return &GDALFeature{Feature: Feature}
+	return manageFeature(&GDALFeature{Feature: Feature})
 }
 
 // PrintLayerInfo 打印图层信息（增强版）
@@ -413,7 +415,12 @@ func (gl *GDALLayer) IterateFeatures(callback func(feature C.OGRFeatureH)) {
 
 // cleanup 清理资源
 func (gl *GDALLayer) cleanup() {
+	untrackResource(resourceKey(ResourceLayer, unsafe.Pointer(gl)))
 	if gl.dataset != nil {
+		if gl.resultSet && gl.layer != nil {
+			C.OGR_DS_ReleaseResultSet(gl.dataset, gl.layer)
+			gl.layer = nil
+		}
 		C.OGR_DS_Destroy(gl.dataset)
 		gl.dataset = nil
 	}
@@ -435,7 +442,7 @@ func (gl *GDALLayer) CreateEmptyFeature() *GDALFeature {
 	if handle == nil {
 		return nil
 	}
-	return &GDALFeature{Feature: handle}
+	return manageFeature(&GDALFeature{Feature: handle})
 }
 
 // CreateFeature 将要素添加到图层
@@ -468,6 +475,45 @@ func (gl *GDALLayer) CreateFeatureFromHandle(handle C.OGRFeatureH) error {
 	return nil
 }
 
+// ==================== 几何相关方法 ====================
+
+// SetSpatialFilter 设置空间过滤几何，geom为nil时清除过滤（几何被复制，调用后可释放）
+func (gl *GDALLayer) SetSpatialFilter(geom *Geometry) {
+	if gl.layer == nil {
+		return
+	}
+	C.OGR_L_SetSpatialFilter(gl.layer, geom.handle())
+	runtime.KeepAlive(geom)
+}
+
+// ClearSpatialFilter 清除空间过滤
+func (gl *GDALLayer) ClearSpatialFilter() {
+	if gl.layer == nil {
+		return
+	}
+	C.OGR_L_SetSpatialFilter(gl.layer, nil)
+}
+
+// FilterByGeometry 按几何过滤要素，返回相交要素组成的新图层
+func (gl *GDALLayer) FilterByGeometry(geom *Geometry) (*GDALLayer, error) {
+	if geom.IsNil() {
+		return nil, ErrGeometryClosed
+	}
+	defer runtime.KeepAlive(geom)
+	return FilterByGeometry(gl, geom.handle())
+}
+
+// ExtentGeometry 返回图层范围矩形
+func (gl *GDALLayer) ExtentGeometry() (*Geometry, error) {
+	minX, minY, maxX, maxY, err := GetLayerExtent(gl)
+	if err != nil {
+		return nil, err
+	}
+	return NewPolygonGeometry([][][2]float64{{
+		{minX, minY}, {maxX, minY}, {maxX, maxY}, {minX, maxY}, {minX, minY},
+	}}), nil
+}
+
 // ==================== 便捷的复制函数 ====================
 
 // CopyAllFeatures 复制所有要素从源图层到目标图层
@@ -566,11 +612,11 @@ func (gl *GDALLayer) ReprojectLayer(targetEPSG int) (*GDALLayer, error) {
 	}
 
 	// 包装为GDALLayer
-	resultLayer := &GDALLayer{
+	resultLayer := manageLayer(&GDALLayer{
 		layer:   newLayer,
 		dataset: nil,
 		driver:  nil,
-	}
+	})
 
 	// 复制字段定义
 	err := CopyFieldDefinitions(gl, resultLayer)
@@ -640,6 +686,23 @@ func (gl *GDALLayer) GetEPSGCode() int {
 	return epsg
 }
 
+// ReprojectLayerToSRS 将图层投影到指定空间参考（返回新的内存图层）
+func (gl *GDALLayer) ReprojectLayerToSRS(targetSRS *SpatialReference) (*GDALLayer, error) {
+	if gl.layer == nil {
+		return nil, fmt.Errorf("源图层为空")
+	}
+	if gl.GetSpatialRef() == nil {
+		return nil, fmt.Errorf("源图层没有定义空间参考系统")
+	}
+	if targetSRS == nil || targetSRS.cPtr == nil {
+		return nil, fmt.Errorf("目标空间参考为空")
+	}
+	defer runtime.KeepAlive(targetSRS)
+
+	// reprojectToSRS 接管传入的空间参考，这里传入副本
+	return gl.reprojectToSRS(C.OSRClone(targetSRS.cPtr), "reprojected")
+}
+
 // reprojectToSRS 内部投影转换实现
 func (gl *GDALLayer) reprojectToSRS(dstSRS C.OGRSpatialReferenceH, suffix string) (*GDALLayer, error) {
 	srcSRS := gl.GetSpatialRef()
@@ -663,7 +726,7 @@ func (gl *GDALLayer) reprojectToSRS(dstSRS C.OGRSpatialReferenceH, suffix string
 		return nil, fmt.Errorf("创建内存图层失败")
 	}
 
-	resultLayer := &GDALLayer{layer: newLayer}
+	resultLayer := manageLayer(&GDALLayer{layer: newLayer})
 
 	if err := CopyFieldDefinitions(gl, resultLayer); err != nil {
 		C.OSRDestroySpatialReference(dstSRS)
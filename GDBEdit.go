@@ -499,10 +499,11 @@ func copyFieldValue(sourceFeature, targetFeature C.OGRFeatureH,
 
 // InsertOptions 插入选项
 type InsertOptions struct {
-	StrictMode          bool // 严格模式，遇到错误立即停止
-	SyncInterval        int  // 同步间隔（每插入多少条要素同步一次）
-	SkipInvalidGeometry bool // 跳过无效几何
-	CreateMissingFields bool // 创建缺失的字段（如果目标图层支持）
+	StrictMode          bool   // 严格模式，遇到错误立即停止
+	SyncInterval        int    // 同步间隔（每插入多少条要素同步一次）
+	SkipInvalidGeometry bool   // 跳过无效几何
+	CreateMissingFields bool   // 创建缺失的字段（如果目标图层支持）
+	Encoding            string // Shapefile DBF编码，为空时自动检测（仅InsertLayerToShapefile使用）
 }
 
 //字段修改相关
@@ -2161,10 +2162,10 @@ func (r *PostGISReaderFiltered) ReadGeometryTable() (*GDALLayer, error) {
 		}
 	}
 
-	return &GDALLayer{
+	return manageLayer(&GDALLayer{
 		layer:   hLayer,
 		dataset: hDataSource,
-	}, nil
+	}), nil
 }
 
 // ImportGDALLayerToGDB 将GDALLayer直接导入到GDB（通用方法）
@@ -0,0 +1,597 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+/*
+#include "osgeo_utils.h"
+*/
+import "C"
+import (
+	"fmt"
+	"math"
+	"os"
+	"path/filepath"
+	"sort"
+	"strings"
+	"unsafe"
+)
+
+// =====================================================
+// 高斯-克吕格分带工具：分带识别、按带拆分与投影带推断
+// =====================================================
+
+// GKZoneWidth 高斯-克吕格投影分带宽度
+type GKZoneWidth int
+
+const (
+	GKZone3Degree GKZoneWidth = 3 // 3度带（25-45带）
+	GKZone6Degree GKZoneWidth = 6 // 6度带（13-23带）
+)
+
+// GKZone 高斯-克吕格投影带
+type GKZone struct {
+	Width           GKZoneWidth // 分带宽度
+	Number          int         // 带号
+	CentralMeridian int         // 中央经线（度）
+}
+
+// GKZoneByNumber 根据带号获取投影带
+func GKZoneByNumber(number int, width GKZoneWidth) (GKZone, error) {
+	switch width {
+	case GKZone3Degree:
+		if number < 25 || number > 45 {
+			return GKZone{}, fmt.Errorf("无效的3度带带号: %d (有效范围: 25-45)", number)
+		}
+		return GKZone{Width: width, Number: number, CentralMeridian: number * 3}, nil
+	case GKZone6Degree:
+		if number < 13 || number > 23 {
+			return GKZone{}, fmt.Errorf("无效的6度带带号: %d (有效范围: 13-23)", number)
+		}
+		return GKZone{Width: width, Number: number, CentralMeridian: number*6 - 3}, nil
+	default:
+		return GKZone{}, fmt.Errorf("不支持的分带宽度: %d", int(width))
+	}
+}
+
+// GKZoneByLongitude 根据经度获取所在投影带
+func GKZoneByLongitude(longitude float64, width GKZoneWidth) (GKZone, error) {
+	switch width {
+	case GKZone3Degree:
+		return GKZoneByNumber(int(math.Floor((longitude+1.5)/3)), width)
+	case GKZone6Degree:
+		return GKZoneByNumber(int(math.Floor(longitude/6))+1, width)
+	default:
+		return GKZone{}, fmt.Errorf("不支持的分带宽度: %d", int(width))
+	}
+}
+
+// WestLongitude 投影带西边界经度
+func (z GKZone) WestLongitude() float64 {
+	return float64(z.CentralMeridian) - float64(z.Width)/2
+}
+
+// EastLongitude 投影带东边界经度
+func (z GKZone) EastLongitude() float64 {
+	return float64(z.CentralMeridian) + float64(z.Width)/2
+}
+
+// String 返回投影带的字符串表示
+func (z GKZone) String() string {
+	return fmt.Sprintf("%d度带 %d带 (中央经线%d°)", int(z.Width), z.Number, z.CentralMeridian)
+}
+
+// SpatialReference 获取投影带对应的CGCS2000投影坐标系
+// withZonePrefix: 横坐标是否带带号前缀（按EPSG定义：3度带4513-4533、6度带4491-4501带前缀），
+// 与 GetCGCS2000_3DegreeByLongitude 的同名参数含义不同
+func (z GKZone) SpatialReference(withZonePrefix bool) (*GDBSpatialReference, error) {
+	if z.Width == GKZone3Degree {
+		if withZonePrefix {
+			return GetCGCS2000_3DegreeZone(z.Number)
+		}
+		return GetCGCS2000_3DegreeByCentralMeridian(z.CentralMeridian)
+	}
+	if z.Width != GKZone6Degree || z.Number < 13 || z.Number > 23 {
+		return nil, fmt.Errorf("无效的投影带: %s", z)
+	}
+
+	// CGCS2000 6度带：EPSG 4491-4501带带号前缀，4502-4512不带带号前缀
+	if withZonePrefix {
+		return &GDBSpatialReference{
+			EPSG:        4491 + z.Number - 13,
+			Name:        fmt.Sprintf("CGCS2000 / Gauss-Kruger zone %d", z.Number),
+			Type:        SRSTypeProjected,
+			Description: fmt.Sprintf("CGCS2000 6度带 %d带 (中央经线%d°)", z.Number, z.CentralMeridian),
+		}, nil
+	}
+	return &GDBSpatialReference{
+		EPSG:        4502 + z.Number - 13,
+		Name:        fmt.Sprintf("CGCS2000 / Gauss-Kruger CM %dE", z.CentralMeridian),
+		Type:        SRSTypeProjected,
+		Description: fmt.Sprintf("CGCS2000 6度带 中央经线%d° (不带带号前缀)", z.CentralMeridian),
+	}, nil
+}
+
+// DatasetName 投影带对应的要素数据集名称
+func (z GKZone) DatasetName() string {
+	return fmt.Sprintf("CGCS2000_%dDegree_Zone%d", int(z.Width), z.Number)
+}
+
+// zonesInRange 获取经度范围覆盖的全部投影带
+func zonesInRange(minLon, maxLon float64, width GKZoneWidth) []GKZone {
+	first, last := 25, 45
+	if width == GKZone6Degree {
+		first, last = 13, 23
+	}
+
+	var zones []GKZone
+	for n := first; n <= last; n++ {
+		zone, err := GKZoneByNumber(n, width)
+		if err != nil {
+			break
+		}
+		if zone.EastLongitude() > minLon && zone.WestLongitude() <= maxLon {
+			zones = append(zones, zone)
+		}
+	}
+	return zones
+}
+
+// newZoneStrip 创建投影带经度范围的条带面（经纬度）
+func newZoneStrip(zone GKZone) C.OGRGeometryH {
+	return C.createTileClipGeometry(C.double(zone.WestLongitude()), -90, C.double(zone.EastLongitude()), 90)
+}
+
+// =====================================================
+// 分带识别
+// =====================================================
+
+// GKZoneCoverage 图层在某投影带内的要素统计
+type GKZoneCoverage struct {
+	Zone         GKZone
+	FeatureCount int // 与该带相交的要素数量
+}
+
+// DetectLayerGKZones 识别图层要素所涉及的全部投影带
+func DetectLayerGKZones(layer *GDALLayer, width GKZoneWidth) ([]GKZoneCoverage, error) {
+	if layer == nil || layer.layer == nil {
+		return nil, fmt.Errorf("图层为空")
+	}
+	srs := layer.GetSpatialRef()
+	if srs == nil {
+		return nil, fmt.Errorf("图层没有定义空间参考系统")
+	}
+	geog, err := newGeographicTransforms(srs)
+	if err != nil {
+		return nil, err
+	}
+	defer geog.destroy()
+
+	counts := make(map[int]*GKZoneCoverage)
+	layer.ResetReading()
+	defer layer.ResetReading()
+	for {
+		feature := layer.GetNextFeatureRow()
+		if feature == nil {
+			break
+		}
+		geometry := C.OGR_F_GetGeometryRef(feature)
+		if geometry != nil {
+			geogGeom := C.OGR_G_Clone(geometry)
+			if C.OGR_G_Transform(geogGeom, geog.toGeog) == C.OGRERR_NONE {
+				for _, zone := range geometryGKZones(geogGeom, width) {
+					if counts[zone.Number] == nil {
+						counts[zone.Number] = &GKZoneCoverage{Zone: zone}
+					}
+					counts[zone.Number].FeatureCount++
+				}
+			}
+			C.OGR_G_DestroyGeometry(geogGeom)
+		}
+		C.OGR_F_Destroy(feature)
+	}
+
+	result := make([]GKZoneCoverage, 0, len(counts))
+	for _, c := range counts {
+		result = append(result, *c)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Zone.Number < result[j].Zone.Number
+	})
+	return result, nil
+}
+
+// geometryGKZones 获取经纬度几何体实际相交的投影带
+func geometryGKZones(geogGeom C.OGRGeometryH, width GKZoneWidth) []GKZone {
+	var env C.OGREnvelope
+	C.OGR_G_GetEnvelope(geogGeom, &env)
+
+	candidates := zonesInRange(float64(env.MinX), float64(env.MaxX), width)
+	if len(candidates) <= 1 {
+		return candidates
+	}
+
+	var zones []GKZone
+	for _, zone := range candidates {
+		strip := newZoneStrip(zone)
+		if C.OGR_G_Intersects(geogGeom, strip) != 0 {
+			zones = append(zones, zone)
+		}
+		C.OGR_G_DestroyGeometry(strip)
+	}
+	return zones
+}
+
+// =====================================================
+// 按带拆分并投影
+// =====================================================
+
+// GKZoneSplitOptions 按投影带拆分配置
+type GKZoneSplitOptions struct {
+	WithZonePrefix bool   // 输出坐标是否带带号前缀
+	AssignWhole    bool   // 跨带要素不切分，整体归入其内点所在的投影带
+	ZoneField      string // 记录带号的字段名，为空则不添加
+}
+
+// GKZoneLayer 单个投影带的拆分结果
+type GKZoneLayer struct {
+	Zone             GKZone
+	SpatialReference *GDBSpatialReference
+	Layer            *GDALLayer
+	FeatureCount     int
+}
+
+// zoneOutput 拆分过程中单个投影带的输出状态
+type zoneOutput struct {
+	result    *GKZoneLayer
+	srs       C.OGRSpatialReferenceH
+	transform C.OGRCoordinateTransformationH
+}
+
+// SplitLayerByGKZone 将图层按投影带边界拆分，并将各部分投影到所在带的CGCS2000投影坐标系
+// 返回按带号排序的各带图层
+func SplitLayerByGKZone(sourceLayer *GDALLayer, width GKZoneWidth, options *GKZoneSplitOptions) ([]*GKZoneLayer, error) {
+	if sourceLayer == nil || sourceLayer.layer == nil {
+		return nil, fmt.Errorf("源图层为空")
+	}
+	if options == nil {
+		options = &GKZoneSplitOptions{}
+	}
+	sourceSRS := sourceLayer.GetSpatialRef()
+	if sourceSRS == nil {
+		return nil, fmt.Errorf("源图层没有定义空间参考系统")
+	}
+
+	geog, err := newGeographicTransforms(sourceSRS)
+	if err != nil {
+		return nil, err
+	}
+	defer geog.destroy()
+
+	sourceDefn := sourceLayer.GetLayerDefn()
+	geomType := C.OGR_FD_GetGeomType(sourceDefn)
+	outputs := make(map[int]*zoneOutput)
+	defer func() {
+		for _, out := range outputs {
+			C.OCTDestroyCoordinateTransformation(out.transform)
+			C.OSRDestroySpatialReference(out.srs)
+		}
+	}()
+
+	// 获取或创建投影带输出图层
+	getOutput := func(zone GKZone) (*zoneOutput, error) {
+		if out, ok := outputs[zone.Number]; ok {
+			return out, nil
+		}
+		zoneSRS, err := zone.SpatialReference(options.WithZonePrefix)
+		if err != nil {
+			return nil, err
+		}
+		ogrSRS, err := zoneSRS.ToOGRGDBSpatialReference()
+		if err != nil {
+			return nil, err
+		}
+		C.OSRSetAxisMappingStrategy(ogrSRS, C.OAMS_TRADITIONAL_GIS_ORDER)
+
+		transform := C.OCTNewCoordinateTransformation(geog.geogSRS, ogrSRS)
+		if transform == nil {
+			C.OSRDestroySpatialReference(ogrSRS)
+			return nil, fmt.Errorf("无法创建到 %s 的坐标转换器", zoneSRS.Name)
+		}
+
+		layerName := fmt.Sprintf("%s_zone%d", sourceLayer.GetLayerName(), zone.Number)
+		layer, err := newMemoryResultLayer("gk_zone_split", layerName, ogrSRS, geomType)
+		if err != nil {
+			C.OCTDestroyCoordinateTransformation(transform)
+			C.OSRDestroySpatialReference(ogrSRS)
+			return nil, err
+		}
+		copyLayerFieldDefns(sourceDefn, layer.layer)
+		if options.ZoneField != "" {
+			cZoneField := C.CString(options.ZoneField)
+			C.addFieldToLayer(layer.layer, cZoneField, C.OFTInteger)
+			C.free(unsafe.Pointer(cZoneField))
+		}
+
+		out := &zoneOutput{
+			result:    &GKZoneLayer{Zone: zone, SpatialReference: zoneSRS, Layer: layer},
+			srs:       ogrSRS,
+			transform: transform,
+		}
+		outputs[zone.Number] = out
+		return out, nil
+	}
+
+	// 将一个经纬度几何部分写入投影带图层
+	writePart := func(out *zoneOutput, sourceFeature C.OGRFeatureH, part C.OGRGeometryH) {
+		if C.OGR_G_Transform(part, out.transform) != C.OGRERR_NONE {
+			return
+		}
+		if geomType != C.wkbUnknown && C.OGR_GT_Flatten(C.OGR_G_GetGeometryType(part)) != C.OGR_GT_Flatten(geomType) {
+			forced := C.forceGeometryType(part, geomType)
+			if forced != nil {
+				C.OGR_G_DestroyGeometry(part)
+				part = forced
+			}
+		}
+
+		resultDefn := out.result.Layer.GetLayerDefn()
+		newFeature := C.OGR_F_Create(resultDefn)
+		if newFeature == nil {
+			C.OGR_G_DestroyGeometry(part)
+			return
+		}
+		C.OGR_F_SetGeometryDirectly(newFeature, part)
+		copyFeatureAttributes(sourceFeature, newFeature, sourceDefn, resultDefn)
+		if options.ZoneField != "" {
+			idx := layerFieldIndex(resultDefn, options.ZoneField)
+			if idx >= 0 {
+				C.OGR_F_SetFieldInteger(newFeature, C.int(idx), C.int(out.result.Zone.Number))
+			}
+		}
+		if C.OGR_L_CreateFeature(out.result.Layer.layer, newFeature) == C.OGRERR_NONE {
+			out.result.FeatureCount++
+		}
+		C.OGR_F_Destroy(newFeature)
+	}
+
+	sourceLayer.ResetReading()
+	defer sourceLayer.ResetReading()
+	for {
+		feature := sourceLayer.GetNextFeatureRow()
+		if feature == nil {
+			break
+		}
+		geometry := C.OGR_F_GetGeometryRef(feature)
+		if geometry == nil {
+			C.OGR_F_Destroy(feature)
+			continue
+		}
+
+		geogGeom := C.OGR_G_Clone(geometry)
+		if C.OGR_G_Transform(geogGeom, geog.toGeog) != C.OGRERR_NONE {
+			C.OGR_G_DestroyGeometry(geogGeom)
+			C.OGR_F_Destroy(feature)
+			continue
+		}
+
+		zones := geometryGKZones(geogGeom, width)
+		if len(zones) > 1 && options.AssignWhole {
+			zones = nil
+			if point := C.OGR_G_PointOnSurface(geogGeom); point != nil {
+				if zone, err := GKZoneByLongitude(float64(C.OGR_G_GetX(point, 0)), width); err == nil {
+					zones = []GKZone{zone}
+				}
+				C.OGR_G_DestroyGeometry(point)
+			}
+		}
+
+		for _, zone := range zones {
+			out, err := getOutput(zone)
+			if err != nil {
+				C.OGR_G_DestroyGeometry(geogGeom)
+				C.OGR_F_Destroy(feature)
+				return nil, err
+			}
+
+			var part C.OGRGeometryH
+			if len(zones) == 1 {
+				part = C.OGR_G_Clone(geogGeom)
+			} else {
+				strip := newZoneStrip(zone)
+				part = C.OGR_G_Intersection(geogGeom, strip)
+				C.OGR_G_DestroyGeometry(strip)
+			}
+			if part == nil {
+				continue
+			}
+			if C.OGR_G_IsEmpty(part) != 0 {
+				C.OGR_G_DestroyGeometry(part)
+				continue
+			}
+			writePart(out, feature, part)
+		}
+
+		C.OGR_G_DestroyGeometry(geogGeom)
+		C.OGR_F_Destroy(feature)
+	}
+
+	result := make([]*GKZoneLayer, 0, len(outputs))
+	for _, out := range outputs {
+		result = append(result, out.result)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].Zone.Number < result[j].Zone.Number
+	})
+	return result, nil
+}
+
+// WriteGKZoneLayersToGDB 将按带拆分的结果写入GDB，每个投影带写入各自的要素数据集
+// 图层命名为 layerName_带号，要素数据集命名见GKZone.DatasetName
+func WriteGKZoneLayersToGDB(zoneLayers []*GKZoneLayer, gdbPath string, layerName string, overwrite bool) error {
+	// 只在实际写入的第一个图层时覆盖已有GDB
+	first := true
+	for _, zl := range zoneLayers {
+		if zl == nil || zl.Layer == nil {
+			continue
+		}
+		writer := &FileGeoWriter{
+			FilePath:  gdbPath,
+			FileType:  "gdb",
+			Overwrite: overwrite && first,
+		}
+		name := fmt.Sprintf("%s_%d", layerName, zl.Zone.Number)
+		if err := writer.WriteGDBFileToDataset(zl.Layer, name, zl.Zone.DatasetName()); err != nil {
+			return fmt.Errorf("写入 %s 失败: %v", zl.Zone, err)
+		}
+		first = false
+	}
+	return nil
+}
+
+// =====================================================
+// 根据坐标推断投影带（无.prj的数据）
+// =====================================================
+
+// GKZoneGuess 投影带推断结果
+type GKZoneGuess struct {
+	Zone             GKZone
+	HasZonePrefix    bool // 横坐标是否带带号前缀
+	AxisSwapped      bool // 坐标是否为(北坐标, 东坐标)顺序
+	SpatialReference *GDBSpatialReference
+}
+
+// gkNorthingRange 国内高斯坐标北坐标的合理范围（米）
+const (
+	gkMinNorthing = 0.0
+	gkMaxNorthing = 7000000.0
+)
+
+// zoneFromPrefixedEasting 从带带号前缀的东坐标中解析带号
+func zoneFromPrefixedEasting(easting float64) (GKZone, bool) {
+	number := int(math.Floor(easting / 1000000))
+	if number >= 25 && number <= 45 {
+		zone, err := GKZoneByNumber(number, GKZone3Degree)
+		return zone, err == nil
+	}
+	if number >= 13 && number <= 23 {
+		zone, err := GKZoneByNumber(number, GKZone6Degree)
+		return zone, err == nil
+	}
+	return GKZone{}, false
+}
+
+// GuessGKZoneFromCoordinate 根据单个坐标的数值量级推断投影带
+// 仅当横坐标带带号前缀（如39512345.67）时能够确定带号
+func GuessGKZoneFromCoordinate(x, y float64) (*GKZoneGuess, error) {
+	isNorthing := func(v float64) bool { return v >= gkMinNorthing && v <= gkMaxNorthing }
+
+	if isNorthing(y) {
+		if zone, ok := zoneFromPrefixedEasting(x); ok {
+			return newGKZoneGuess(zone, false)
+		}
+	}
+	if isNorthing(x) {
+		if zone, ok := zoneFromPrefixedEasting(y); ok {
+			return newGKZoneGuess(zone, true)
+		}
+	}
+
+	if x >= -180 && x <= 180 && y >= -90 && y <= 90 {
+		return nil, fmt.Errorf("坐标(%.6f, %.6f)为经纬度，不是高斯投影坐标", x, y)
+	}
+	if x > 0 && x < 1000000 && isNorthing(y) {
+		return nil, fmt.Errorf("坐标(%.3f, %.3f)的横坐标不带带号前缀，无法确定投影带", x, y)
+	}
+	return nil, fmt.Errorf("无法根据坐标(%.3f, %.3f)推断投影带", x, y)
+}
+
+func newGKZoneGuess(zone GKZone, swapped bool) (*GKZoneGuess, error) {
+	srs, err := zone.SpatialReference(true)
+	if err != nil {
+		return nil, err
+	}
+	return &GKZoneGuess{
+		Zone:             zone,
+		HasZonePrefix:    true,
+		AxisSwapped:      swapped,
+		SpatialReference: srs,
+	}, nil
+}
+
+// GuessLayerGKZone 根据图层范围推断投影带，图层范围的两个角点必须落在同一投影带
+func GuessLayerGKZone(layer *GDALLayer) (*GKZoneGuess, error) {
+	minX, minY, maxX, maxY, err := GetLayerExtent(layer)
+	if err != nil {
+		return nil, err
+	}
+
+	lower, err := GuessGKZoneFromCoordinate(minX, minY)
+	if err != nil {
+		return nil, err
+	}
+	upper, err := GuessGKZoneFromCoordinate(maxX, maxY)
+	if err != nil {
+		return nil, err
+	}
+	if lower.Zone != upper.Zone || lower.AxisSwapped != upper.AxisSwapped {
+		return nil, fmt.Errorf("图层范围跨越多个投影带（%s 与 %s），无法推断", lower.Zone, upper.Zone)
+	}
+	return lower, nil
+}
+
+// GuessShapefileGKZone 推断Shapefile的投影带，writePrj为true且文件缺少.prj时写出对应的.prj文件
+func GuessShapefileGKZone(shpPath string, writePrj bool) (*GKZoneGuess, error) {
+	layer, err := ReadShapeFileLayer(shpPath)
+	if err != nil {
+		return nil, err
+	}
+	defer layer.Close()
+
+	guess, err := GuessLayerGKZone(layer)
+	if err != nil {
+		return nil, err
+	}
+	if !writePrj {
+		return guess, nil
+	}
+
+	prjPath := strings.TrimSuffix(shpPath, filepath.Ext(shpPath)) + ".prj"
+	if _, err := os.Stat(prjPath); err == nil {
+		return guess, fmt.Errorf(".prj文件已存在: %s", prjPath)
+	}
+
+	ogrSRS, err := guess.SpatialReference.ToOGRGDBSpatialReference()
+	if err != nil {
+		return guess, err
+	}
+	defer C.OSRDestroySpatialReference(ogrSRS)
+
+	if C.OSRMorphToESRI(ogrSRS) != C.OGRERR_NONE {
+		return guess, fmt.Errorf("转换为ESRI坐标系定义失败")
+	}
+	var cWKT *C.char
+	if C.OSRExportToWkt(ogrSRS, &cWKT) != C.OGRERR_NONE {
+		return guess, fmt.Errorf("导出WKT失败")
+	}
+	wkt := C.GoString(cWKT)
+	C.CPLFree(unsafe.Pointer(cWKT))
+
+	if err := os.WriteFile(prjPath, []byte(wkt), 0644); err != nil {
+		return guess, fmt.Errorf("写入.prj文件失败: %v", err)
+	}
+	return guess, nil
+}
@@ -77,6 +77,7 @@ type FieldAnalysisResult struct {
 	Width     int
 	Precision int
 	DBType    string
+	Index     int // 字段在源图层中的索引
 }
 
 // FeatureAnalysisResult 要素分析结果
@@ -197,6 +198,7 @@ func analyzeFields(hLayerDefn C.OGRFeatureDefnH) ([]FieldAnalysisResult, error)
 			Width:     width,
 			Precision: precision,
 			DBType:    dbType,
+			Index:     i,
 		}
 
 		fields = append(fields, field)
@@ -234,8 +236,8 @@ func analyzeFeature(hFeature C.OGRFeatureH, fields []FieldAnalysisResult, hTrans
 	}
 
 	// 处理属性数据
-	for i, field := range fields {
-		fieldIndex := C.int(i)
+	for _, field := range fields {
+		fieldIndex := C.int(field.Index)
 
 		// 检查字段是否为空
 		if C.OGR_F_IsFieldSet(hFeature, fieldIndex) == 0 {
@@ -0,0 +1,354 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+/*
+#include "osgeo_utils.h"
+*/
+import "C"
+import (
+	"fmt"
+	"os"
+	"sort"
+	"unsafe"
+)
+
+// ========== GeoPackage 读写 ==========
+
+// GPKGWriteMode GeoPackage写入模式
+type GPKGWriteMode int
+
+const (
+	GPKGCreateLayer    GPKGWriteMode = iota // 新建图层，同名图层已存在时报错
+	GPKGOverwriteLayer                      // 删除同名图层后重建，文件中其他图层保留
+	GPKGAppend                              // 追加到同名图层（不存在时新建），缺少的字段自动添加
+	GPKGOverwriteFile                       // 删除整个文件后重建
+)
+
+// GPKGWriteOptions GeoPackage写入选项
+type GPKGWriteOptions struct {
+	Mode                GPKGWriteMode
+	DisableSpatialIndex bool   // 不创建空间索引（默认创建R-tree索引）
+	AttributeOnly       bool   // 写为无几何的属性表
+	GeometryColumn      string // 几何列名，默认geom
+	FIDColumn           string // 主键列名，默认fid
+	Description         string // 图层描述（gpkg_contents.description）
+}
+
+// ReadGPKGFile 读取GeoPackage文件，未指定图层名时读取第一个图层（含属性表）
+func (r *FileGeoReader) ReadGPKGFile(layerName ...string) (*GDALLayer, error) {
+	if r.FileType != "gpkg" {
+		return nil, fmt.Errorf("文件类型不是GeoPackage: %s", r.FileType)
+	}
+
+	cFilePath := C.CString(r.FilePath)
+	defer C.free(unsafe.Pointer(cFilePath))
+
+	cDriverName := C.CString("GPKG")
+	defer C.free(unsafe.Pointer(cDriverName))
+	driver := C.OGRGetDriverByName(cDriverName)
+	if driver == nil {
+		return nil, fmt.Errorf("无法获取GPKG驱动")
+	}
+
+	dataset := C.OGROpen(cFilePath, C.int(0), nil)
+	if dataset == nil {
+		return nil, fmt.Errorf("无法打开GeoPackage文件: %s", r.FilePath)
+	}
+
+	var layer C.OGRLayerH
+	if len(layerName) > 0 && layerName[0] != "" {
+		cLayerName := C.CString(layerName[0])
+		defer C.free(unsafe.Pointer(cLayerName))
+		layer = C.OGR_DS_GetLayerByName(dataset, cLayerName)
+	} else if C.OGR_DS_GetLayerCount(dataset) > 0 {
+		layer = C.OGR_DS_GetLayer(dataset, C.int(0))
+	}
+
+	if layer == nil {
+		C.OGR_DS_Destroy(dataset)
+		return nil, fmt.Errorf("无法获取图层")
+	}
+
+	gdalLayer := &GDALLayer{
+		layer:   layer,
+		dataset: dataset,
+		driver:  driver,
+	}
+
+	manageLayer(gdalLayer)
+	return gdalLayer, nil
+}
+
+// ReadGPKGLayers 读取GeoPackage中的全部图层，返回图层名到图层的映射
+// 每个图层独立打开数据源，可分别关闭。
+func (r *FileGeoReader) ReadGPKGLayers() (map[string]*GDALLayer, error) {
+	names, err := r.ListLayers()
+	if err != nil {
+		return nil, err
+	}
+
+	layers := make(map[string]*GDALLayer, len(names))
+	for _, name := range names {
+		layer, err := r.ReadGPKGFile(name)
+		if err != nil {
+			for _, opened := range layers {
+				opened.Close()
+			}
+			return nil, fmt.Errorf("读取图层 %s 失败: %v", name, err)
+		}
+		layers[name] = layer
+	}
+	return layers, nil
+}
+
+// WriteGPKGFile 写入GeoPackage图层
+// Overwrite为true时覆盖同名图层，否则同名图层已存在时报错；文件中的其他图层均保留。
+func (w *FileGeoWriter) WriteGPKGFile(sourceLayer *GDALLayer, layerName string) error {
+	mode := GPKGCreateLayer
+	if w.Overwrite {
+		mode = GPKGOverwriteLayer
+	}
+	return w.WriteGPKGFileWithOptions(sourceLayer, layerName, &GPKGWriteOptions{Mode: mode})
+}
+
+// WriteGPKGFileWithOptions 按选项写入GeoPackage图层，layerName为空时使用源图层名
+func (w *FileGeoWriter) WriteGPKGFileWithOptions(sourceLayer *GDALLayer, layerName string, options *GPKGWriteOptions) error {
+	if w.FileType != "gpkg" {
+		return fmt.Errorf("文件类型不是GeoPackage: %s", w.FileType)
+	}
+	if sourceLayer == nil || sourceLayer.layer == nil {
+		return fmt.Errorf("源图层为空")
+	}
+	if options == nil {
+		options = &GPKGWriteOptions{}
+	}
+	if layerName == "" {
+		layerName = sourceLayer.GetLayerName()
+	}
+
+	if options.Mode == GPKGOverwriteFile {
+		if _, err := os.Stat(w.FilePath); err == nil {
+			if err := os.Remove(w.FilePath); err != nil {
+				return fmt.Errorf("无法删除已存在的文件: %v", err)
+			}
+		}
+	}
+
+	dataset, err := w.openGPKGDataset()
+	if err != nil {
+		return err
+	}
+	defer C.OGR_DS_Destroy(dataset)
+
+	targetLayer, existing, err := prepareGPKGLayer(dataset, layerName, options.Mode)
+	if err != nil {
+		return err
+	}
+	sourceDefn := sourceLayer.GetLayerDefn()
+
+	if existing {
+		if err := w.addMissingFields(sourceDefn, targetLayer); err != nil {
+			return err
+		}
+	} else {
+		targetLayer, err = createGPKGLayer(dataset, sourceLayer, layerName, options)
+		if err != nil {
+			return err
+		}
+		if err := w.copyFieldDefinitions(sourceDefn, targetLayer, nil); err != nil {
+			return err
+		}
+	}
+
+	// 单事务批量写入，显著提升GeoPackage写入速度
+	C.OGR_L_StartTransaction(targetLayer)
+	if err := w.copyFeatures(sourceLayer, targetLayer); err != nil {
+		C.OGR_L_RollbackTransaction(targetLayer)
+		return err
+	}
+	if C.OGR_L_CommitTransaction(targetLayer) != C.OGRERR_NONE {
+		return fmt.Errorf("提交事务失败: %s", layerName)
+	}
+
+	return nil
+}
+
+// WriteGPKGLayers 将多个图层写入同一个GeoPackage文件（按图层名排序写入）
+func (w *FileGeoWriter) WriteGPKGLayers(layers map[string]*GDALLayer, options *GPKGWriteOptions) error {
+	if options == nil {
+		options = &GPKGWriteOptions{}
+		if w.Overwrite {
+			options.Mode = GPKGOverwriteLayer
+		}
+	}
+
+	names := make([]string, 0, len(layers))
+	for name := range layers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	for i, name := range names {
+		layerOptions := *options
+		// 覆盖整个文件只在写第一个图层时执行
+		if layerOptions.Mode == GPKGOverwriteFile && i > 0 {
+			layerOptions.Mode = GPKGOverwriteLayer
+		}
+		if err := w.WriteGPKGFileWithOptions(layers[name], name, &layerOptions); err != nil {
+			return fmt.Errorf("写入图层 %s 失败: %v", name, err)
+		}
+	}
+	return nil
+}
+
+// openGPKGDataset 以可写方式打开GeoPackage，不存在时创建
+func (w *FileGeoWriter) openGPKGDataset() (C.OGRDataSourceH, error) {
+	cFilePath := C.CString(w.FilePath)
+	defer C.free(unsafe.Pointer(cFilePath))
+
+	if _, err := os.Stat(w.FilePath); err == nil {
+		dataset := C.OGROpen(cFilePath, C.int(1), nil) // 1表示可写
+		if dataset == nil {
+			return nil, fmt.Errorf("无法以写入模式打开GeoPackage文件: %s", w.FilePath)
+		}
+		return dataset, nil
+	}
+
+	cDriverName := C.CString("GPKG")
+	defer C.free(unsafe.Pointer(cDriverName))
+	driver := C.OGRGetDriverByName(cDriverName)
+	if driver == nil {
+		return nil, fmt.Errorf("无法获取GPKG驱动")
+	}
+
+	dataset := C.OGR_Dr_CreateDataSource(driver, cFilePath, nil)
+	if dataset == nil {
+		return nil, fmt.Errorf("无法创建GeoPackage文件: %s", w.FilePath)
+	}
+	return dataset, nil
+}
+
+// prepareGPKGLayer 按写入模式处理同名图层，返回可追加的已有图层
+func prepareGPKGLayer(dataset C.OGRDataSourceH, layerName string, mode GPKGWriteMode) (C.OGRLayerH, bool, error) {
+	for i := 0; i < int(C.OGR_DS_GetLayerCount(dataset)); i++ {
+		layer := C.OGR_DS_GetLayer(dataset, C.int(i))
+		if C.GoString(C.OGR_L_GetName(layer)) != layerName {
+			continue
+		}
+
+		switch mode {
+		case GPKGAppend:
+			return layer, true, nil
+		case GPKGOverwriteLayer, GPKGOverwriteFile:
+			if C.OGR_DS_DeleteLayer(dataset, C.int(i)) != C.OGRERR_NONE {
+				return nil, false, fmt.Errorf("无法删除已存在的图层: %s", layerName)
+			}
+			return nil, false, nil
+		default:
+			return nil, false, fmt.Errorf("图层已存在: %s", layerName)
+		}
+	}
+	return nil, false, nil
+}
+
+// createGPKGLayer 创建GeoPackage图层
+func createGPKGLayer(dataset C.OGRDataSourceH, sourceLayer *GDALLayer, layerName string, options *GPKGWriteOptions) (C.OGRLayerH, error) {
+	geomType := C.OGR_FD_GetGeomType(sourceLayer.GetLayerDefn())
+	srs := sourceLayer.GetSpatialRef()
+	if options.AttributeOnly {
+		geomType = C.wkbNone
+		srs = nil
+	}
+
+	layerOptions := []string{}
+	if geomType != C.wkbNone {
+		if options.DisableSpatialIndex {
+			layerOptions = append(layerOptions, "SPATIAL_INDEX=NO")
+		} else {
+			layerOptions = append(layerOptions, "SPATIAL_INDEX=YES")
+		}
+		if options.GeometryColumn != "" {
+			layerOptions = append(layerOptions, "GEOMETRY_NAME="+options.GeometryColumn)
+		}
+	}
+	if options.FIDColumn != "" {
+		layerOptions = append(layerOptions, "FID="+options.FIDColumn)
+	}
+	if options.Description != "" {
+		layerOptions = append(layerOptions, "DESCRIPTION="+options.Description)
+	}
+
+	var cOptions **C.char
+	for _, option := range layerOptions {
+		cOption := C.CString(option)
+		cOptions = C.CSLAddString(cOptions, cOption)
+		C.free(unsafe.Pointer(cOption))
+	}
+	defer C.CSLDestroy(cOptions)
+
+	cLayerName := C.CString(layerName)
+	defer C.free(unsafe.Pointer(cLayerName))
+
+	layer := C.OGR_DS_CreateLayer(dataset, cLayerName, srs, geomType, cOptions)
+	if layer == nil {
+		return nil, fmt.Errorf("无法创建图层: %s", layerName)
+	}
+	return layer, nil
+}
+
+// addMissingFields 追加模式下为已有图层补充缺少的字段
+func (w *FileGeoWriter) addMissingFields(sourceDefn C.OGRFeatureDefnH, targetLayer C.OGRLayerH) error {
+	targetDefn := C.OGR_L_GetLayerDefn(targetLayer)
+	for i := 0; i < int(C.OGR_FD_GetFieldCount(sourceDefn)); i++ {
+		sourceFieldDefn := C.OGR_FD_GetFieldDefn(sourceDefn, C.int(i))
+		name := w.sanitizeFieldName(C.GoString(C.OGR_Fld_GetNameRef(sourceFieldDefn)), nil)
+		if layerFieldIndex(targetDefn, name) >= 0 {
+			continue
+		}
+
+		cName := C.CString(name)
+		fieldDefn := C.OGR_Fld_Create(cName, w.mapFieldTypeForGDB(C.OGR_Fld_GetType(sourceFieldDefn), w.FileType))
+		C.free(unsafe.Pointer(cName))
+		C.OGR_Fld_SetWidth(fieldDefn, C.OGR_Fld_GetWidth(sourceFieldDefn))
+		C.OGR_Fld_SetPrecision(fieldDefn, C.OGR_Fld_GetPrecision(sourceFieldDefn))
+		result := C.OGR_L_CreateField(targetLayer, fieldDefn, C.int(1))
+		C.OGR_Fld_Destroy(fieldDefn)
+		if result != C.OGRERR_NONE {
+			return fmt.Errorf("无法添加字段 %s (错误代码: %d)", name, int(result))
+		}
+	}
+	return nil
+}
+
+// ReadGPKGLayer 直接读取GeoPackage图层
+func ReadGPKGLayer(filePath string, layerName ...string) (*GDALLayer, error) {
+	reader, err := NewFileGeoReader(filePath)
+	if err != nil {
+		return nil, err
+	}
+	return reader.ReadGPKGFile(layerName...)
+}
+
+// WriteGPKGLayer 直接写入GeoPackage图层
+func WriteGPKGLayer(sourceLayer *GDALLayer, filePath string, layerName string, overwrite bool) error {
+	writer, err := NewFileGeoWriter(filePath, overwrite)
+	if err != nil {
+		return err
+	}
+	return writer.WriteGPKGFile(sourceLayer, layerName)
+}
@@ -10,7 +10,6 @@ import (
 	"fmt"
 	"github.com/paulmach/orb"
 	"math"
-	"runtime"
 	"unsafe"
 )
 
@@ -108,7 +107,7 @@ func BufferLayer(sourceLayer *GDALLayer, distance float64, quadSegs int) (*GDALL
 		driver:  memDriver,
 	}
 
-	runtime.SetFinalizer(gdalLayer, (*GDALLayer).cleanup)
+	manageLayer(gdalLayer)
 	return gdalLayer, nil
 }
 
@@ -251,7 +250,7 @@ func BufferLayerAuto(sourceLayer *GDALLayer, targetRatio float64, quadSegs int)
 		driver:  memDriver,
 	}
 
-	runtime.SetFinalizer(gdalLayer, (*GDALLayer).cleanup)
+	manageLayer(gdalLayer)
 	return gdalLayer, nil
 }
 
@@ -427,6 +426,8 @@ func calculateOptimizedBufferDistance(area, perimeter, targetRatio float64) floa
 
 // BufferFeature 对单个要素进行缓冲区分析
 // 返回缓冲后的几何体
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 GDALFeature.GetGeometry().Buffer。
 func BufferFeature(feature C.OGRFeatureH, distance float64, quadSegs int) C.OGRGeometryH {
 	if feature == nil {
 		return nil
@@ -445,6 +446,8 @@ func BufferFeature(feature C.OGRFeatureH, distance float64, quadSegs int) C.OGRG
 }
 
 // BufferGeometry 对几何体进行缓冲区分析
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).Buffer。
 func BufferGeometry(geometry C.OGRGeometryH, distance float64, quadSegs int) C.OGRGeometryH {
 	if geometry == nil {
 		return nil
@@ -549,11 +552,13 @@ func SimplifyLayer(sourceLayer *GDALLayer, tolerance float64, preserveTopology b
 		driver:  memDriver,
 	}
 
-	runtime.SetFinalizer(gdalLayer, (*GDALLayer).cleanup)
+	manageLayer(gdalLayer)
 	return gdalLayer, nil
 }
 
 // SimplifyFeature 对单个要素进行简化
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 GDALFeature.GetGeometry().Simplify。
 func SimplifyFeature(feature C.OGRFeatureH, tolerance float64, preserveTopology bool) C.OGRGeometryH {
 	if feature == nil {
 		return nil
@@ -571,6 +576,8 @@ func SimplifyFeature(feature C.OGRFeatureH, tolerance float64, preserveTopology
 }
 
 // SimplifyGeometry 对几何体进行简化
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).Simplify。
 func SimplifyGeometry(geometry C.OGRGeometryH, tolerance float64, preserveTopology bool) C.OGRGeometryH {
 	if geometry == nil {
 		return nil
@@ -689,11 +696,13 @@ func MakeValidLayer(sourceLayer *GDALLayer) (*GDALLayer, error) {
 		driver:  memDriver,
 	}
 
-	runtime.SetFinalizer(gdalLayer, (*GDALLayer).cleanup)
+	manageLayer(gdalLayer)
 	return gdalLayer, nil
 }
 
 // MakeValidFeature 对单个要素进行几何修复
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 GDALFeature.GetGeometry().MakeValid。
 func MakeValidFeature(feature C.OGRFeatureH) C.OGRGeometryH {
 	if feature == nil {
 		return nil
@@ -714,6 +723,8 @@ func MakeValidFeature(feature C.OGRFeatureH) C.OGRGeometryH {
 }
 
 // MakeValidGeometry 对几何体进行修复
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).MakeValid。
 func MakeValidGeometry(geometry C.OGRGeometryH) C.OGRGeometryH {
 	if geometry == nil {
 		return nil
@@ -803,6 +814,61 @@ func copyFeatureAttributes(sourceFeature, targetFeature C.OGRFeatureH, sourceDef
 	}
 }
 
+// newMemoryResultLayer 创建用于存放分析结果的内存图层
+func newMemoryResultLayer(datasetName, layerName string, srs C.OGRSpatialReferenceH, geomType C.OGRwkbGeometryType) (*GDALLayer, error) {
+	cDriverName := C.CString("Memory")
+	defer C.free(unsafe.Pointer(cDriverName))
+
+	memDriver := C.OGRGetDriverByName(cDriverName)
+	if memDriver == nil {
+		return nil, fmt.Errorf("无法获取Memory驱动")
+	}
+
+	cDatasetName := C.CString(datasetName)
+	defer C.free(unsafe.Pointer(cDatasetName))
+
+	memDataset := C.OGR_Dr_CreateDataSource(memDriver, cDatasetName, nil)
+	if memDataset == nil {
+		return nil, fmt.Errorf("无法创建内存数据源")
+	}
+
+	cLayerName := C.CString(layerName)
+	defer C.free(unsafe.Pointer(cLayerName))
+
+	resultLayer := C.OGR_DS_CreateLayer(memDataset, cLayerName, srs, geomType, nil)
+	if resultLayer == nil {
+		C.OGR_DS_Destroy(memDataset)
+		return nil, fmt.Errorf("无法创建结果图层")
+	}
+
+	gdalLayer := &GDALLayer{
+		layer:   resultLayer,
+		dataset: memDataset,
+		driver:  memDriver,
+	}
+
+	manageLayer(gdalLayer)
+	return gdalLayer, nil
+}
+
+// copyLayerFieldDefns 将源图层定义中的字段复制到目标图层
+func copyLayerFieldDefns(sourceDefn C.OGRFeatureDefnH, targetLayer C.OGRLayerH) {
+	fieldCount := int(C.OGR_FD_GetFieldCount(sourceDefn))
+	for i := 0; i < fieldCount; i++ {
+		fieldDefn := C.OGR_FD_GetFieldDefn(sourceDefn, C.int(i))
+		if fieldDefn != nil {
+			C.OGR_L_CreateField(targetLayer, fieldDefn, C.int(1))
+		}
+	}
+}
+
+// addLayerField 向图层添加指定名称和类型的字段
+func addLayerField(layer *GDALLayer, name string, fieldType C.OGRFieldType) {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	C.addFieldToLayer(layer.layer, cName, fieldType)
+}
+
 // ============================================================================
 // 额外的几何处理函数
 // ============================================================================
@@ -880,11 +946,13 @@ func ConvexHullLayer(sourceLayer *GDALLayer) (*GDALLayer, error) {
 		driver:  memDriver,
 	}
 
-	runtime.SetFinalizer(gdalLayer, (*GDALLayer).cleanup)
+	manageLayer(gdalLayer)
 	return gdalLayer, nil
 }
 
 // ConvexHullGeometry 对几何体计算凸包
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).ConvexHull。
 func ConvexHullGeometry(geometry C.OGRGeometryH) C.OGRGeometryH {
 	if geometry == nil {
 		return nil
@@ -968,11 +1036,13 @@ func CentroidLayer(sourceLayer *GDALLayer) (*GDALLayer, error) {
 		driver:  memDriver,
 	}
 
-	runtime.SetFinalizer(gdalLayer, (*GDALLayer).cleanup)
+	manageLayer(gdalLayer)
 	return gdalLayer, nil
 }
 
 // CentroidGeometry 计算几何体的质心
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).Centroid。
 func CentroidGeometry(geometry C.OGRGeometryH) C.OGRGeometryH {
 	if geometry == nil {
 		return nil
@@ -1066,11 +1136,13 @@ func BoundaryLayer(sourceLayer *GDALLayer) (*GDALLayer, error) {
 		driver:  memDriver,
 	}
 
-	runtime.SetFinalizer(gdalLayer, (*GDALLayer).cleanup)
+	manageLayer(gdalLayer)
 	return gdalLayer, nil
 }
 
 // BoundaryGeometry 计算几何体的边界
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).Boundary。
 func BoundaryGeometry(geometry C.OGRGeometryH) C.OGRGeometryH {
 	if geometry == nil {
 		return nil
@@ -1151,11 +1223,13 @@ func UnionAllLayer(sourceLayer *GDALLayer) (*GDALLayer, error) {
 		driver:  memDriver,
 	}
 
-	runtime.SetFinalizer(gdalLayer, (*GDALLayer).cleanup)
+	manageLayer(gdalLayer)
 	return gdalLayer, nil
 }
 
 // UnionGeometry 合并两个几何体
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).Union。
 func UnionGeometry(geom1, geom2 C.OGRGeometryH) C.OGRGeometryH {
 	if geom1 == nil || geom2 == nil {
 		return nil
@@ -1253,11 +1327,13 @@ func IntersectionLayer(layer1, layer2 *GDALLayer) (*GDALLayer, error) {
 		driver:  memDriver,
 	}
 
-	runtime.SetFinalizer(gdalLayer, (*GDALLayer).cleanup)
+	manageLayer(gdalLayer)
 	return gdalLayer, nil
 }
 
 // IntersectionGeometry 计算两个几何体的交集
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).Intersection。
 func IntersectionGeometry(geom1, geom2 C.OGRGeometryH) C.OGRGeometryH {
 	if geom1 == nil || geom2 == nil {
 		return nil
@@ -1362,11 +1438,13 @@ func DifferenceLayer(layer1, layer2 *GDALLayer) (*GDALLayer, error) {
 		driver:  memDriver,
 	}
 
-	runtime.SetFinalizer(gdalLayer, (*GDALLayer).cleanup)
+	manageLayer(gdalLayer)
 	return gdalLayer, nil
 }
 
 // DifferenceGeometry 计算两个几何体的差集
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).Difference。
 func DifferenceGeometry(geom1, geom2 C.OGRGeometryH) C.OGRGeometryH {
 	if geom1 == nil || geom2 == nil {
 		return nil
@@ -1375,6 +1453,8 @@ func DifferenceGeometry(geom1, geom2 C.OGRGeometryH) C.OGRGeometryH {
 }
 
 // SymDifferenceGeometry 计算两个几何体的对称差集
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).SymDifference。
 func SymDifferenceGeometry(geom1, geom2 C.OGRGeometryH) C.OGRGeometryH {
 	if geom1 == nil || geom2 == nil {
 		return nil
@@ -1462,11 +1542,13 @@ func FilterByExtent(sourceLayer *GDALLayer, minX, minY, maxX, maxY float64) (*GD
 		driver:  memDriver,
 	}
 
-	runtime.SetFinalizer(gdalLayer, (*GDALLayer).cleanup)
+	manageLayer(gdalLayer)
 	return gdalLayer, nil
 }
 
 // FilterByGeometry 按几何体过滤图层
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*GDALLayer).FilterByGeometry。
 func FilterByGeometry(sourceLayer *GDALLayer, filterGeom C.OGRGeometryH) (*GDALLayer, error) {
 	if sourceLayer == nil || sourceLayer.layer == nil {
 		return nil, fmt.Errorf("源图层为空")
@@ -1546,7 +1628,7 @@ func FilterByGeometry(sourceLayer *GDALLayer, filterGeom C.OGRGeometryH) (*GDALL
 		driver:  memDriver,
 	}
 
-	runtime.SetFinalizer(gdalLayer, (*GDALLayer).cleanup)
+	manageLayer(gdalLayer)
 	return gdalLayer, nil
 }
 
@@ -1555,6 +1637,8 @@ func FilterByGeometry(sourceLayer *GDALLayer, filterGeom C.OGRGeometryH) (*GDALL
 // ============================================================================
 
 // GetArea 计算几何体面积
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).Area。
 func GetArea(geometry C.OGRGeometryH) float64 {
 	if geometry == nil {
 		return 0
@@ -1563,6 +1647,8 @@ func GetArea(geometry C.OGRGeometryH) float64 {
 }
 
 // GetLength 计算几何体长度
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).Length。
 func GetLength(geometry C.OGRGeometryH) float64 {
 	if geometry == nil {
 		return 0
@@ -1571,6 +1657,8 @@ func GetLength(geometry C.OGRGeometryH) float64 {
 }
 
 // GetPointCount 获取几何体点数
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).PointCount。
 func GetPointCount(geometry C.OGRGeometryH) int {
 	if geometry == nil {
 		return 0
@@ -1579,6 +1667,8 @@ func GetPointCount(geometry C.OGRGeometryH) int {
 }
 
 // IsValid 检查几何体是否有效
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).IsValid。
 func IsValid(geometry C.OGRGeometryH) bool {
 	if geometry == nil {
 		return false
@@ -1587,6 +1677,8 @@ func IsValid(geometry C.OGRGeometryH) bool {
 }
 
 // IsEmpty 检查几何体是否为空
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).IsEmpty。
 func IsEmpty(geometry C.OGRGeometryH) bool {
 	if geometry == nil {
 		return true
@@ -1595,6 +1687,8 @@ func IsEmpty(geometry C.OGRGeometryH) bool {
 }
 
 // IsSimple 检查几何体是否简单
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).IsSimple。
 func IsSimple(geometry C.OGRGeometryH) bool {
 	if geometry == nil {
 		return false
@@ -1603,6 +1697,8 @@ func IsSimple(geometry C.OGRGeometryH) bool {
 }
 
 // IsRing 检查几何体是否为环
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).IsRing。
 func IsRing(geometry C.OGRGeometryH) bool {
 	if geometry == nil {
 		return false
@@ -1615,6 +1711,8 @@ func IsRing(geometry C.OGRGeometryH) bool {
 // ============================================================================
 
 // Intersects 判断两个几何体是否相交
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).Intersects。
 func Intersects(geom1, geom2 C.OGRGeometryH) bool {
 	if geom1 == nil || geom2 == nil {
 		return false
@@ -1623,6 +1721,8 @@ func Intersects(geom1, geom2 C.OGRGeometryH) bool {
 }
 
 // Contains 判断geom1是否包含geom2
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).Contains。
 func Contains(geom1, geom2 C.OGRGeometryH) bool {
 	if geom1 == nil || geom2 == nil {
 		return false
@@ -1631,6 +1731,8 @@ func Contains(geom1, geom2 C.OGRGeometryH) bool {
 }
 
 // Within 判断geom1是否在geom2内
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).Within。
 func Within(geom1, geom2 C.OGRGeometryH) bool {
 	if geom1 == nil || geom2 == nil {
 		return false
@@ -1639,6 +1741,8 @@ func Within(geom1, geom2 C.OGRGeometryH) bool {
 }
 
 // Touches 判断两个几何体是否接触
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).Touches。
 func Touches(geom1, geom2 C.OGRGeometryH) bool {
 	if geom1 == nil || geom2 == nil {
 		return false
@@ -1647,6 +1751,8 @@ func Touches(geom1, geom2 C.OGRGeometryH) bool {
 }
 
 // Crosses 判断两个几何体是否交叉
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).Crosses。
 func Crosses(geom1, geom2 C.OGRGeometryH) bool {
 	if geom1 == nil || geom2 == nil {
 		return false
@@ -1655,6 +1761,8 @@ func Crosses(geom1, geom2 C.OGRGeometryH) bool {
 }
 
 // Overlaps 判断两个几何体是否重叠
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).Overlaps。
 func Overlaps(geom1, geom2 C.OGRGeometryH) bool {
 	if geom1 == nil || geom2 == nil {
 		return false
@@ -1663,6 +1771,8 @@ func Overlaps(geom1, geom2 C.OGRGeometryH) bool {
 }
 
 // Disjoint 判断两个几何体是否不相交
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).Disjoint。
 func Disjoint(geom1, geom2 C.OGRGeometryH) bool {
 	if geom1 == nil || geom2 == nil {
 		return true
@@ -1671,6 +1781,8 @@ func Disjoint(geom1, geom2 C.OGRGeometryH) bool {
 }
 
 // Equals 判断两个几何体是否相等
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).Equals。
 func Equals(geom1, geom2 C.OGRGeometryH) bool {
 	if geom1 == nil || geom2 == nil {
 		return false
@@ -1679,6 +1791,8 @@ func Equals(geom1, geom2 C.OGRGeometryH) bool {
 }
 
 // Distance 计算两个几何体之间的距离
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).Distance。
 func Distance(geom1, geom2 C.OGRGeometryH) float64 {
 	if geom1 == nil || geom2 == nil {
 		return -1
@@ -1691,6 +1805,8 @@ func Distance(geom1, geom2 C.OGRGeometryH) float64 {
 // ============================================================================
 
 // CreatePointGeometry 创建点几何体
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 NewPointGeometry。
 func CreatePointGeometry(x, y float64) C.OGRGeometryH {
 	point := C.OGR_G_CreateGeometry(C.wkbPoint)
 	if point != nil {
@@ -1700,6 +1816,8 @@ func CreatePointGeometry(x, y float64) C.OGRGeometryH {
 }
 
 // CreatePoint3DGeometry 创建3D点几何体
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 NewPoint3DGeometry。
 func CreatePoint3DGeometry(x, y, z float64) C.OGRGeometryH {
 	point := C.OGR_G_CreateGeometry(C.wkbPoint25D)
 	if point != nil {
@@ -1709,6 +1827,8 @@ func CreatePoint3DGeometry(x, y, z float64) C.OGRGeometryH {
 }
 
 // CreateLineStringGeometry 创建线几何体
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 NewLineStringGeometry。
 func CreateLineStringGeometry(points [][2]float64) C.OGRGeometryH {
 	line := C.OGR_G_CreateGeometry(C.wkbLineString)
 	if line != nil {
@@ -1720,6 +1840,8 @@ func CreateLineStringGeometry(points [][2]float64) C.OGRGeometryH {
 }
 
 // CreatePolygonGeometry 创建多边形几何体
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 NewPolygonGeometry。
 func CreatePolygonGeometry(rings [][][2]float64) C.OGRGeometryH {
 	polygon := C.OGR_G_CreateGeometry(C.wkbPolygon)
 	if polygon == nil {
@@ -1740,6 +1862,8 @@ func CreatePolygonGeometry(rings [][][2]float64) C.OGRGeometryH {
 }
 
 // GeometryToWKT 将几何体转换为WKT格式
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).WKT。
 func GeometryToWKT(geometry C.OGRGeometryH) string {
 	if geometry == nil {
 		return ""
@@ -1757,6 +1881,8 @@ func GeometryToWKT(geometry C.OGRGeometryH) string {
 }
 
 // GeometryFromWKT 从WKT格式创建几何体
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 NewGeometryFromWKT。
 func GeometryFromWKT(wkt string) C.OGRGeometryH {
 	cWkt := C.CString(wkt)
 	defer C.free(unsafe.Pointer(cWkt))
@@ -1771,6 +1897,8 @@ func GeometryFromWKT(wkt string) C.OGRGeometryH {
 }
 
 // GeometryToGeoJSON 将几何体转换为GeoJSON格式
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).GeoJSON。
 func GeometryToGeoJSON(geometry C.OGRGeometryH) string {
 	if geometry == nil {
 		return ""
@@ -1787,6 +1915,8 @@ func GeometryToGeoJSON(geometry C.OGRGeometryH) string {
 }
 
 // CloneGeometry 克隆几何体
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).Clone。
 func CloneGeometry(geometry C.OGRGeometryH) C.OGRGeometryH {
 	if geometry == nil {
 		return nil
@@ -1795,6 +1925,8 @@ func CloneGeometry(geometry C.OGRGeometryH) C.OGRGeometryH {
 }
 
 // DestroyGeometry 销毁几何体
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).Close。
 func DestroyGeometry(geometry C.OGRGeometryH) {
 	if geometry != nil {
 		C.OGR_G_DestroyGeometry(geometry)
@@ -1806,6 +1938,8 @@ func DestroyGeometry(geometry C.OGRGeometryH) {
 // ============================================================================
 
 // TransformLayer 对图层进行坐标转换
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*GDALLayer).ReprojectLayer 或 (*GDALLayer).ReprojectLayerToSRS。
 func TransformLayer(sourceLayer *GDALLayer, targetSRS C.OGRSpatialReferenceH) (*GDALLayer, error) {
 	if sourceLayer == nil || sourceLayer.layer == nil {
 		return nil, fmt.Errorf("源图层为空")
@@ -1895,11 +2029,13 @@ func TransformLayer(sourceLayer *GDALLayer, targetSRS C.OGRSpatialReferenceH) (*
 		driver:  memDriver,
 	}
 
-	runtime.SetFinalizer(gdalLayer, (*GDALLayer).cleanup)
+	manageLayer(gdalLayer)
 	return gdalLayer, nil
 }
 
 // TransformGeometry 对几何体进行坐标转换
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).Transform。
 func TransformGeometry(geometry C.OGRGeometryH, sourceSRS, targetSRS C.OGRSpatialReferenceH) C.OGRGeometryH {
 	if geometry == nil || sourceSRS == nil || targetSRS == nil {
 		return nil
@@ -1926,6 +2062,8 @@ func TransformGeometry(geometry C.OGRGeometryH, sourceSRS, targetSRS C.OGRSpatia
 }
 
 // CreateSpatialReferenceFromEPSG 从EPSG代码创建空间参考
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 NewSRSFromEPSG。
 func CreateSpatialReferenceFromEPSG(epsgCode int) C.OGRSpatialReferenceH {
 	srs := C.OSRNewSpatialReference(nil)
 	if srs == nil {
@@ -1942,6 +2080,8 @@ func CreateSpatialReferenceFromEPSG(epsgCode int) C.OGRSpatialReferenceH {
 }
 
 // CreateSpatialReferenceFromWKT 从WKT创建空间参考
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 NewSRSFromWKT。
 func CreateSpatialReferenceFromWKT(wkt string) C.OGRSpatialReferenceH {
 	srs := C.OSRNewSpatialReference(nil)
 	if srs == nil {
@@ -1961,6 +2101,8 @@ func CreateSpatialReferenceFromWKT(wkt string) C.OGRSpatialReferenceH {
 }
 
 // CreateSpatialReferenceFromProj4 从Proj4字符串创建空间参考
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 NewSRSFromProj4。
 func CreateSpatialReferenceFromProj4(proj4 string) C.OGRSpatialReferenceH {
 	srs := C.OSRNewSpatialReference(nil)
 	if srs == nil {
@@ -1980,6 +2122,8 @@ func CreateSpatialReferenceFromProj4(proj4 string) C.OGRSpatialReferenceH {
 }
 
 // DestroySpatialReference 销毁空间参考
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*SpatialReference).Destroy。
 func DestroySpatialReference(srs C.OGRSpatialReferenceH) {
 	if srs != nil {
 		C.OSRDestroySpatialReference(srs)
@@ -1991,6 +2135,8 @@ func DestroySpatialReference(srs C.OGRSpatialReferenceH) {
 // ============================================================================
 
 // RemoveDuplicatePoints 移除重复点
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).Clone（当前实现仅复制几何）。
 func RemoveDuplicatePoints(geometry C.OGRGeometryH, tolerance float64) C.OGRGeometryH {
 	if geometry == nil {
 		return nil
@@ -2010,6 +2156,8 @@ func RemoveDuplicatePoints(geometry C.OGRGeometryH, tolerance float64) C.OGRGeom
 }
 
 // CloseRings 闭合环
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).CloseRings。
 func CloseRings(geometry C.OGRGeometryH) C.OGRGeometryH {
 	if geometry == nil {
 		return nil
@@ -2025,6 +2173,8 @@ func CloseRings(geometry C.OGRGeometryH) C.OGRGeometryH {
 }
 
 // SegmentizeGeometry 将几何体分段
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).Segmentize。
 func SegmentizeGeometry(geometry C.OGRGeometryH, maxLength float64) C.OGRGeometryH {
 	if geometry == nil {
 		return nil
@@ -2136,7 +2286,7 @@ func BatchBuffer(sourceLayer *GDALLayer, distanceField string, quadSegs int) (*G
 		driver:  memDriver,
 	}
 
-	runtime.SetFinalizer(gdalLayer, (*GDALLayer).cleanup)
+	manageLayer(gdalLayer)
 	return gdalLayer, nil
 }
 
@@ -2252,7 +2402,7 @@ func DissolveLayer(sourceLayer *GDALLayer, dissolveField string) (*GDALLayer, er
 		driver:  memDriver,
 	}
 
-	runtime.SetFinalizer(gdalLayer, (*GDALLayer).cleanup)
+	manageLayer(gdalLayer)
 	return gdalLayer, nil
 }
 
@@ -2261,6 +2411,8 @@ func DissolveLayer(sourceLayer *GDALLayer, dissolveField string) (*GDALLayer, er
 // ============================================================================
 
 // GetEnvelope 获取几何体的外接矩形
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).Envelope。
 func GetEnvelope(geometry C.OGRGeometryH) (minX, minY, maxX, maxY float64, err error) {
 	if geometry == nil {
 		return 0, 0, 0, 0, fmt.Errorf("几何体为空")
@@ -2274,6 +2426,8 @@ func GetEnvelope(geometry C.OGRGeometryH) (minX, minY, maxX, maxY float64, err e
 }
 
 // GetGeometryType 获取几何体类型名称
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).GeometryType。
 func GetGeometryType(geometry C.OGRGeometryH) string {
 	if geometry == nil {
 		return ""
@@ -2284,6 +2438,8 @@ func GetGeometryType(geometry C.OGRGeometryH) string {
 }
 
 // GetGeometryName 获取几何体名称
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).Name。
 func GetGeometryName(geometry C.OGRGeometryH) string {
 	if geometry == nil {
 		return ""
@@ -2293,6 +2449,8 @@ func GetGeometryName(geometry C.OGRGeometryH) string {
 }
 
 // GetDimension 获取几何体维度
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).Dimension。
 func GetDimension(geometry C.OGRGeometryH) int {
 	if geometry == nil {
 		return 0
@@ -2302,6 +2460,8 @@ func GetDimension(geometry C.OGRGeometryH) int {
 }
 
 // GetCoordinateDimension 获取坐标维度
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).CoordinateDimension。
 func GetCoordinateDimension(geometry C.OGRGeometryH) int {
 	if geometry == nil {
 		return 0
@@ -2315,6 +2475,8 @@ func GetCoordinateDimension(geometry C.OGRGeometryH) int {
 // ============================================================================
 
 // MergeFeaturesToLayer 将多个要素合并到一个新图层
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 MergeGDALFeaturesToLayer。
 func MergeFeaturesToLayer(features []C.OGRFeatureH, layerName string, srs C.OGRSpatialReferenceH) (*GDALLayer, error) {
 	if len(features) == 0 {
 		return nil, fmt.Errorf("要素列表为空")
@@ -2390,7 +2552,7 @@ func MergeFeaturesToLayer(features []C.OGRFeatureH, layerName string, srs C.OGRS
 		driver:  memDriver,
 	}
 
-	runtime.SetFinalizer(gdalLayer, (*GDALLayer).cleanup)
+	manageLayer(gdalLayer)
 	return gdalLayer, nil
 }
 
@@ -2504,7 +2666,7 @@ func DonutBuilder(sourceLayer *GDALLayer) (*GDALLayer, error) {
 			dataset: memDataset,
 			driver:  memDriver,
 		}
-		runtime.SetFinalizer(gdalLayer, (*GDALLayer).cleanup)
+		manageLayer(gdalLayer)
 		return gdalLayer, nil
 	}
 
@@ -2524,7 +2686,7 @@ func DonutBuilder(sourceLayer *GDALLayer) (*GDALLayer, error) {
 		driver:  memDriver,
 	}
 
-	runtime.SetFinalizer(gdalLayer, (*GDALLayer).cleanup)
+	manageLayer(gdalLayer)
 	return gdalLayer, nil
 }
 
@@ -2968,6 +3130,8 @@ func DonutBuilderWithOptions(sourceLayer *GDALLayer, options *DonutBuilderOption
 // lineFeature: 输入的线要素
 // polygonFeature: 输入的面要素
 // 返回：处理后最长的线几何体
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).RemovePolygonBoundaryOverlap。
 func RemoveLinePolygonBoundaryOverlapAndReturnLongest(lineFeature C.OGRFeatureH, polygonFeature C.OGRFeatureH, tolerance float64) C.OGRGeometryH {
 	if lineFeature == nil || polygonFeature == nil {
 		return nil
@@ -2987,6 +3151,8 @@ func RemoveLinePolygonBoundaryOverlapAndReturnLongest(lineFeature C.OGRFeatureH,
 // lineGeom: 输入的线几何体
 // polygonGeom: 输入的面几何体
 // 返回：处理后最长的线几何体
+//
+// Deprecated: 参数或返回值为C类型，包外无法调用，请使用 (*Geometry).RemovePolygonBoundaryOverlap。
 func RemoveLinePolygonBoundaryOverlapGeometryAndReturnLongest(lineGeom C.OGRGeometryH, polygonGeom C.OGRGeometryH, tolerance float64) C.OGRGeometryH {
 	if lineGeom == nil || polygonGeom == nil {
 		return nil
@@ -3326,7 +3492,7 @@ func AreaOnAreaAnalysis(layer *GDALLayer, tolerance float64) (*GDALLayer, error)
 		driver:  memDriver,
 	}
 
-	runtime.SetFinalizer(gdalLayer, (*GDALLayer).cleanup)
+	manageLayer(gdalLayer)
 	return gdalLayer, nil
 }
 
@@ -0,0 +1,677 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+/*
+#include "osgeo_utils.h"
+*/
+import "C"
+import (
+	"errors"
+	"fmt"
+	"runtime"
+	"unsafe"
+
+	"github.com/paulmach/orb"
+)
+
+// ============================================================================
+// Geometry 公共API
+// 包外代码无法使用 C.OGRGeometryH，因此对外统一以 *Geometry 传递几何体。
+// 由本包创建的 *Geometry 持有C资源并设置终结器，也可调用 Close 立即释放；
+// 由要素借出的 *Geometry（GDALFeature.GetGeometry）引用并保持要素存活，要素被Destroy或几何被替换后失效，Close 不会释放资源。
+// ============================================================================
+
+// ErrGeometryClosed 几何对象为空或已释放
+var ErrGeometryClosed = errors.New("几何对象为空或已释放")
+
+// wrapGeometry 包装C几何对象并接管所有权
+func wrapGeometry(handle C.OGRGeometryH) *Geometry {
+	if handle == nil {
+		return nil
+	}
+	geom := &Geometry{cPtr: handle}
+	runtime.SetFinalizer(geom, (*Geometry).destroy)
+	return geom
+}
+
+// borrowGeometry 包装由owner持有的C几何对象（不接管所有权），
+// 几何对象引用owner，保证其存活期间owner不会被终结器释放
+func borrowGeometry(handle C.OGRGeometryH, owner interface{}) *Geometry {
+	if handle == nil {
+		return nil
+	}
+	return &Geometry{cPtr: handle, borrowed: true, owner: owner}
+}
+
+// handle 返回底层C句柄，nil或已释放时返回nil
+func (geom *Geometry) handle() C.OGRGeometryH {
+	if geom == nil {
+		return nil
+	}
+	return geom.cPtr
+}
+
+// release 放弃所有权并返回C句柄（所有权已转移给owner）
+func (geom *Geometry) release(owner interface{}) C.OGRGeometryH {
+	handle := geom.handle()
+	if handle == nil {
+		return nil
+	}
+	runtime.SetFinalizer(geom, nil)
+	geom.borrowed = true
+	geom.owner = owner
+	return handle
+}
+
+// Close 释放几何对象，重复调用安全
+func (geom *Geometry) Close() {
+	if geom == nil {
+		return
+	}
+	geom.Destroy()
+}
+
+// IsNil 几何对象是否为空或已释放
+func (geom *Geometry) IsNil() bool {
+	return geom.handle() == nil
+}
+
+// IsBorrowed 是否为借用引用（由要素持有）
+func (geom *Geometry) IsBorrowed() bool {
+	return geom != nil && geom.borrowed
+}
+
+// ============================================================================
+// 创建与格式转换
+// ============================================================================
+
+// NewGeometryFromWKT 从WKT创建几何对象
+func NewGeometryFromWKT(wkt string) (*Geometry, error) {
+	handle := GeometryFromWKT(wkt)
+	if handle == nil {
+		return nil, fmt.Errorf("解析WKT失败")
+	}
+	return wrapGeometry(handle), nil
+}
+
+// NewGeometryFromWKB 从WKB创建几何对象（EWKB请使用 CreateGeometryFromWKBHex）
+func NewGeometryFromWKB(wkb []byte) (*Geometry, error) {
+	if len(wkb) == 0 {
+		return nil, fmt.Errorf("WKB数据为空")
+	}
+	data := C.CBytes(wkb)
+	defer C.free(data)
+
+	var handle C.OGRGeometryH
+	if C.OGR_G_CreateFromWkb(data, nil, &handle, C.int(len(wkb))) != C.OGRERR_NONE || handle == nil {
+		return nil, fmt.Errorf("解析WKB失败")
+	}
+	return wrapGeometry(handle), nil
+}
+
+// NewGeometryFromGeoJSON 从GeoJSON几何对象字符串创建几何对象
+func NewGeometryFromGeoJSON(geojson string) (*Geometry, error) {
+	cJSON := C.CString(geojson)
+	defer C.free(unsafe.Pointer(cJSON))
+
+	handle := C.OGR_G_CreateGeometryFromJson(cJSON)
+	if handle == nil {
+		return nil, fmt.Errorf("解析GeoJSON失败")
+	}
+	return wrapGeometry(handle), nil
+}
+
+// NewGeometryFromOrb 从orb几何对象创建几何对象
+func NewGeometryFromOrb(geometry orb.Geometry) (*Geometry, error) {
+	handle, err := orbGeometryToOGRGeometry(geometry)
+	if err != nil {
+		return nil, err
+	}
+	return wrapGeometry(handle), nil
+}
+
+// NewPointGeometry 创建点
+func NewPointGeometry(x, y float64) *Geometry {
+	return wrapGeometry(CreatePointGeometry(x, y))
+}
+
+// NewPoint3DGeometry 创建三维点
+func NewPoint3DGeometry(x, y, z float64) *Geometry {
+	return wrapGeometry(CreatePoint3DGeometry(x, y, z))
+}
+
+// NewLineStringGeometry 创建线
+func NewLineStringGeometry(points [][2]float64) *Geometry {
+	return wrapGeometry(CreateLineStringGeometry(points))
+}
+
+// NewPolygonGeometry 创建面，第一个环为外环
+func NewPolygonGeometry(rings [][][2]float64) *Geometry {
+	return wrapGeometry(CreatePolygonGeometry(rings))
+}
+
+// WKT 导出为WKT
+func (geom *Geometry) WKT() string {
+	defer runtime.KeepAlive(geom)
+	return GeometryToWKT(geom.handle())
+}
+
+// WKB 导出为WKB（小端序）
+func (geom *Geometry) WKB() ([]byte, error) {
+	handle := geom.handle()
+	if handle == nil {
+		return nil, ErrGeometryClosed
+	}
+	defer runtime.KeepAlive(geom)
+
+	size := C.OGR_G_WkbSize(handle)
+	if size <= 0 {
+		return nil, fmt.Errorf("无效的几何对象")
+	}
+	buffer := make([]byte, int(size))
+	if C.OGR_G_ExportToWkb(handle, C.wkbNDR, (*C.uchar)(unsafe.Pointer(&buffer[0]))) != C.OGRERR_NONE {
+		return nil, fmt.Errorf("导出WKB失败")
+	}
+	return buffer, nil
+}
+
+// WKBHex 导出为十六进制WKB字符串
+func (geom *Geometry) WKBHex() (string, error) {
+	handle := geom.handle()
+	if handle == nil {
+		return "", ErrGeometryClosed
+	}
+	defer runtime.KeepAlive(geom)
+	return geometryToWKBHex(handle)
+}
+
+// GeoJSON 导出为GeoJSON几何对象字符串
+func (geom *Geometry) GeoJSON() string {
+	defer runtime.KeepAlive(geom)
+	return GeometryToGeoJSON(geom.handle())
+}
+
+// Orb 转换为orb几何对象
+func (geom *Geometry) Orb() (orb.Geometry, error) {
+	handle := geom.handle()
+	if handle == nil {
+		return nil, ErrGeometryClosed
+	}
+	defer runtime.KeepAlive(geom)
+	return ogrGeometryToOrbGeometry(handle)
+}
+
+// String 实现fmt.Stringer，输出WKT
+func (geom *Geometry) String() string {
+	return geom.WKT()
+}
+
+// ============================================================================
+// 几何运算
+// ============================================================================
+
+// derive 执行一元运算并包装结果
+func (geom *Geometry) derive(operation string, fn func(C.OGRGeometryH) C.OGRGeometryH) (*Geometry, error) {
+	handle := geom.handle()
+	if handle == nil {
+		return nil, ErrGeometryClosed
+	}
+	result := fn(handle)
+	runtime.KeepAlive(geom)
+	if result == nil {
+		return nil, fmt.Errorf("%s失败", operation)
+	}
+	return wrapGeometry(result), nil
+}
+
+// deriveChecked 执行可能返回错误的一元运算并包装结果
+func (geom *Geometry) deriveChecked(fn func(C.OGRGeometryH) (C.OGRGeometryH, error)) (*Geometry, error) {
+	handle := geom.handle()
+	if handle == nil {
+		return nil, ErrGeometryClosed
+	}
+	result, err := fn(handle)
+	runtime.KeepAlive(geom)
+	if err != nil {
+		return nil, err
+	}
+	return wrapGeometry(result), nil
+}
+
+// combine 执行二元运算并包装结果
+func (geom *Geometry) combine(other *Geometry, operation string, fn func(C.OGRGeometryH, C.OGRGeometryH) C.OGRGeometryH) (*Geometry, error) {
+	handle, otherHandle := geom.handle(), other.handle()
+	if handle == nil || otherHandle == nil {
+		return nil, ErrGeometryClosed
+	}
+	result := fn(handle, otherHandle)
+	runtime.KeepAlive(geom)
+	runtime.KeepAlive(other)
+	if result == nil {
+		return nil, fmt.Errorf("%s失败", operation)
+	}
+	return wrapGeometry(result), nil
+}
+
+// predicate 执行二元判断
+func (geom *Geometry) predicate(other *Geometry, fn func(C.OGRGeometryH, C.OGRGeometryH) bool) bool {
+	handle, otherHandle := geom.handle(), other.handle()
+	if handle == nil || otherHandle == nil {
+		return false
+	}
+	result := fn(handle, otherHandle)
+	runtime.KeepAlive(geom)
+	runtime.KeepAlive(other)
+	return result
+}
+
+// Clone 克隆几何对象
+func (geom *Geometry) Clone() (*Geometry, error) {
+	return geom.derive("克隆几何", CloneGeometry)
+}
+
+// Buffer 缓冲区分析，quadSegs<=0时使用默认值30
+func (geom *Geometry) Buffer(distance float64, quadSegs int) (*Geometry, error) {
+	return geom.derive("缓冲区分析", func(h C.OGRGeometryH) C.OGRGeometryH {
+		return BufferGeometry(h, distance, quadSegs)
+	})
+}
+
+// Simplify 简化几何
+func (geom *Geometry) Simplify(tolerance float64, preserveTopology bool) (*Geometry, error) {
+	return geom.derive("简化几何", func(h C.OGRGeometryH) C.OGRGeometryH {
+		return SimplifyGeometry(h, tolerance, preserveTopology)
+	})
+}
+
+// MakeValid 修复几何
+func (geom *Geometry) MakeValid() (*Geometry, error) {
+	return geom.derive("修复几何", MakeValidGeometry)
+}
+
+// ConvexHull 计算凸包
+func (geom *Geometry) ConvexHull() (*Geometry, error) {
+	return geom.derive("计算凸包", ConvexHullGeometry)
+}
+
+// Centroid 计算质心
+func (geom *Geometry) Centroid() (*Geometry, error) {
+	return geom.derive("计算质心", CentroidGeometry)
+}
+
+// Boundary 计算边界
+func (geom *Geometry) Boundary() (*Geometry, error) {
+	return geom.derive("计算边界", BoundaryGeometry)
+}
+
+// Union 合并
+func (geom *Geometry) Union(other *Geometry) (*Geometry, error) {
+	return geom.combine(other, "合并几何", UnionGeometry)
+}
+
+// Intersection 求交
+func (geom *Geometry) Intersection(other *Geometry) (*Geometry, error) {
+	return geom.combine(other, "求交", IntersectionGeometry)
+}
+
+// Difference 求差
+func (geom *Geometry) Difference(other *Geometry) (*Geometry, error) {
+	return geom.combine(other, "求差", DifferenceGeometry)
+}
+
+// SymDifference 对称差
+func (geom *Geometry) SymDifference(other *Geometry) (*Geometry, error) {
+	return geom.combine(other, "对称差", SymDifferenceGeometry)
+}
+
+// Transform 坐标转换，返回新几何对象
+func (geom *Geometry) Transform(sourceSRS, targetSRS *SpatialReference) (*Geometry, error) {
+	if sourceSRS == nil || targetSRS == nil || sourceSRS.cPtr == nil || targetSRS.cPtr == nil {
+		return nil, fmt.Errorf("空间参考为空")
+	}
+	defer runtime.KeepAlive(sourceSRS)
+	defer runtime.KeepAlive(targetSRS)
+	return geom.derive("坐标转换", func(h C.OGRGeometryH) C.OGRGeometryH {
+		return TransformGeometry(h, sourceSRS.cPtr, targetSRS.cPtr)
+	})
+}
+
+// TransformEPSG 按EPSG代码进行坐标转换
+func (geom *Geometry) TransformEPSG(sourceEPSG, targetEPSG int) (*Geometry, error) {
+	sourceSRS := CreateSpatialReferenceFromEPSG(sourceEPSG)
+	if sourceSRS == nil {
+		return nil, fmt.Errorf("无效的EPSG代码: %d", sourceEPSG)
+	}
+	defer DestroySpatialReference(sourceSRS)
+	targetSRS := CreateSpatialReferenceFromEPSG(targetEPSG)
+	if targetSRS == nil {
+		return nil, fmt.Errorf("无效的EPSG代码: %d", targetEPSG)
+	}
+	defer DestroySpatialReference(targetSRS)
+
+	return geom.derive("坐标转换", func(h C.OGRGeometryH) C.OGRGeometryH {
+		return TransformGeometry(h, sourceSRS, targetSRS)
+	})
+}
+
+// CloseRings 闭合环，返回新几何对象
+func (geom *Geometry) CloseRings() (*Geometry, error) {
+	return geom.derive("闭合环", CloseRings)
+}
+
+// Segmentize 按最大长度加密节点，返回新几何对象
+func (geom *Geometry) Segmentize(maxLength float64) (*Geometry, error) {
+	return geom.derive("几何分段", func(h C.OGRGeometryH) C.OGRGeometryH {
+		return SegmentizeGeometry(h, maxLength)
+	})
+}
+
+// ============================================================================
+// 几何分析（图层级版本见各功能所在文件）
+// ============================================================================
+
+// EllipsoidalArea 椭球面积（平方米），srs为几何对象所在的坐标系
+func (geom *Geometry) EllipsoidalArea(srs *SpatialReference) (float64, error) {
+	handle := geom.handle()
+	if handle == nil {
+		return 0, ErrGeometryClosed
+	}
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(srs)
+	return ellipsoidalArea(handle, srs.handle())
+}
+
+// TransformOffset 转换经纬度几何的偏移坐标体系（如WGS84与GCJ-02之间），返回新几何对象
+func (geom *Geometry) TransformOffset(from, to OffsetCoordSystem) (*Geometry, error) {
+	return geom.deriveChecked(func(h C.OGRGeometryH) (C.OGRGeometryH, error) {
+		return transformGeometryOffset(h, from, to)
+	})
+}
+
+// Smooth 平滑线或面，返回新几何对象
+func (geom *Geometry) Smooth(options *SmoothOptions) (*Geometry, error) {
+	return geom.deriveChecked(func(h C.OGRGeometryH) (C.OGRGeometryH, error) {
+		return smoothGeometry(h, options)
+	})
+}
+
+// SimplifyVW 使用Visvalingam-Whyatt算法简化，minArea为有效三角形面积阈值
+func (geom *Geometry) SimplifyVW(minArea float64) (*Geometry, error) {
+	return geom.derive("VW简化", func(h C.OGRGeometryH) C.OGRGeometryH {
+		return simplifyGeometryVW(h, minArea)
+	})
+}
+
+// Centerline 提取面的中心线，返回多线几何对象
+func (geom *Geometry) Centerline(options *CenterlineOptions) (*Geometry, error) {
+	return geom.derive("提取中心线", func(h C.OGRGeometryH) C.OGRGeometryH {
+		return centerlineGeometry(h, options)
+	})
+}
+
+// MinimumAreaRectangle 最小面积外接矩形
+func (geom *Geometry) MinimumAreaRectangle() (*Geometry, *OrientedRectangle, error) {
+	var rect *OrientedRectangle
+	result, err := geom.deriveChecked(func(h C.OGRGeometryH) (C.OGRGeometryH, error) {
+		polygon, r, err := minimumAreaRectangle(h)
+		rect = r
+		return polygon, err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, rect, nil
+}
+
+// MinimumBoundingCircle 最小外接圆，quadSegs为每四分之一圆的段数
+func (geom *Geometry) MinimumBoundingCircle(quadSegs int) (*Geometry, *BoundingCircle, error) {
+	var circle *BoundingCircle
+	result, err := geom.deriveChecked(func(h C.OGRGeometryH) (C.OGRGeometryH, error) {
+		polygon, c, err := minimumBoundingCircle(h, quadSegs)
+		circle = c
+		return polygon, err
+	})
+	if err != nil {
+		return nil, nil, err
+	}
+	return result, circle, nil
+}
+
+// ConcaveHull 计算顶点的凹包，ratio为凹度比例（0-1，1等价于凸包）
+func (geom *Geometry) ConcaveHull(ratio float64, allowHoles bool) (*Geometry, error) {
+	return geom.derive("计算凹包", func(h C.OGRGeometryH) C.OGRGeometryH {
+		return concaveHullGeometry(h, ratio, allowHoles)
+	})
+}
+
+// ForceType 转换为目标几何类型，目标为单部件类型时每个部件单独返回；无可用部件时返回空切片
+func (geom *Geometry) ForceType(options *ForceGeometryTypeOptions) []*Geometry {
+	handle := geom.handle()
+	if handle == nil {
+		return nil
+	}
+	parts := forceGeometryType(handle, options)
+	runtime.KeepAlive(geom)
+
+	result := make([]*Geometry, 0, len(parts))
+	for _, part := range parts {
+		result = append(result, wrapGeometry(part))
+	}
+	return result
+}
+
+// ============================================================================
+// 度量与判断
+// ============================================================================
+
+// Area 面积
+func (geom *Geometry) Area() float64 {
+	defer runtime.KeepAlive(geom)
+	return GetArea(geom.handle())
+}
+
+// Length 长度
+func (geom *Geometry) Length() float64 {
+	defer runtime.KeepAlive(geom)
+	return GetLength(geom.handle())
+}
+
+// PointCount 点数
+func (geom *Geometry) PointCount() int {
+	defer runtime.KeepAlive(geom)
+	return GetPointCount(geom.handle())
+}
+
+// Envelope 外接矩形
+func (geom *Geometry) Envelope() (minX, minY, maxX, maxY float64, err error) {
+	defer runtime.KeepAlive(geom)
+	return GetEnvelope(geom.handle())
+}
+
+// GeometryType 几何类型名称（如 "Polygon"、"3D Multi Line String"）
+func (geom *Geometry) GeometryType() string {
+	defer runtime.KeepAlive(geom)
+	return GetGeometryType(geom.handle())
+}
+
+// Name 几何名称（如 "POLYGON"）
+func (geom *Geometry) Name() string {
+	defer runtime.KeepAlive(geom)
+	return GetGeometryName(geom.handle())
+}
+
+// Dimension 拓扑维度：点0、线1、面2
+func (geom *Geometry) Dimension() int {
+	defer runtime.KeepAlive(geom)
+	return GetDimension(geom.handle())
+}
+
+// CoordinateDimension 坐标维度
+func (geom *Geometry) CoordinateDimension() int {
+	defer runtime.KeepAlive(geom)
+	return GetCoordinateDimension(geom.handle())
+}
+
+// IsValid 是否有效
+func (geom *Geometry) IsValid() bool {
+	defer runtime.KeepAlive(geom)
+	return IsValid(geom.handle())
+}
+
+// IsEmpty 是否为空几何，已释放的几何对象也视为空
+func (geom *Geometry) IsEmpty() bool {
+	defer runtime.KeepAlive(geom)
+	return geom.handle() == nil || IsEmpty(geom.handle())
+}
+
+// IsSimple 是否为简单几何
+func (geom *Geometry) IsSimple() bool {
+	defer runtime.KeepAlive(geom)
+	return IsSimple(geom.handle())
+}
+
+// IsRing 是否为环
+func (geom *Geometry) IsRing() bool {
+	defer runtime.KeepAlive(geom)
+	return IsRing(geom.handle())
+}
+
+// Intersects 是否相交
+func (geom *Geometry) Intersects(other *Geometry) bool {
+	return geom.predicate(other, Intersects)
+}
+
+// Contains 是否包含other
+func (geom *Geometry) Contains(other *Geometry) bool {
+	return geom.predicate(other, Contains)
+}
+
+// Within 是否位于other内
+func (geom *Geometry) Within(other *Geometry) bool {
+	return geom.predicate(other, Within)
+}
+
+// Touches 是否接触
+func (geom *Geometry) Touches(other *Geometry) bool {
+	return geom.predicate(other, Touches)
+}
+
+// Crosses 是否穿越
+func (geom *Geometry) Crosses(other *Geometry) bool {
+	return geom.predicate(other, Crosses)
+}
+
+// Overlaps 是否重叠
+func (geom *Geometry) Overlaps(other *Geometry) bool {
+	return geom.predicate(other, Overlaps)
+}
+
+// Disjoint 是否相离，任一几何为空时返回false
+func (geom *Geometry) Disjoint(other *Geometry) bool {
+	return geom.predicate(other, Disjoint)
+}
+
+// Equals 是否空间相等
+func (geom *Geometry) Equals(other *Geometry) bool {
+	return geom.predicate(other, Equals)
+}
+
+// Distance 两几何间的最短距离，任一几何为空时返回-1
+func (geom *Geometry) Distance(other *Geometry) float64 {
+	handle, otherHandle := geom.handle(), other.handle()
+	if handle == nil || otherHandle == nil {
+		return -1
+	}
+	defer runtime.KeepAlive(geom)
+	defer runtime.KeepAlive(other)
+	return Distance(handle, otherHandle)
+}
+
+// ============================================================================
+// 空间参考
+// ============================================================================
+
+// wrapSpatialReference 包装C空间参考并接管所有权
+func wrapSpatialReference(handle C.OGRSpatialReferenceH) *SpatialReference {
+	srs := &SpatialReference{cPtr: handle}
+	runtime.SetFinalizer(srs, (*SpatialReference).destroy)
+	return srs
+}
+
+// handle 返回底层C句柄，nil或已释放时返回nil
+func (srs *SpatialReference) handle() C.OGRSpatialReferenceH {
+	if srs == nil {
+		return nil
+	}
+	return srs.cPtr
+}
+
+// NewSRSFromEPSG 从EPSG代码创建空间参考
+func NewSRSFromEPSG(epsgCode int) (*SpatialReference, error) {
+	handle := CreateSpatialReferenceFromEPSG(epsgCode)
+	if handle == nil {
+		return nil, fmt.Errorf("无效的EPSG代码: %d", epsgCode)
+	}
+	return wrapSpatialReference(handle), nil
+}
+
+// NewSRSFromWKT 从WKT创建空间参考
+func NewSRSFromWKT(wkt string) (*SpatialReference, error) {
+	handle := CreateSpatialReferenceFromWKT(wkt)
+	if handle == nil {
+		return nil, fmt.Errorf("解析空间参考WKT失败")
+	}
+	return wrapSpatialReference(handle), nil
+}
+
+// NewSRSFromProj4 从Proj4字符串创建空间参考
+func NewSRSFromProj4(proj4 string) (*SpatialReference, error) {
+	handle := CreateSpatialReferenceFromProj4(proj4)
+	if handle == nil {
+		return nil, fmt.Errorf("解析Proj4字符串失败: %s", proj4)
+	}
+	return wrapSpatialReference(handle), nil
+}
+
+// ============================================================================
+// 其他工具
+// ============================================================================
+
+// RemovePolygonBoundaryOverlap 移除线与面边界重叠的部分，返回剩余部分中最长的线
+func (geom *Geometry) RemovePolygonBoundaryOverlap(polygon *Geometry, tolerance float64) (*Geometry, error) {
+	return geom.combine(polygon, "移除边界重叠", func(line, polygon C.OGRGeometryH) C.OGRGeometryH {
+		return RemoveLinePolygonBoundaryOverlapGeometryAndReturnLongest(line, polygon, tolerance)
+	})
+}
+
+// MergeGDALFeaturesToLayer 将多个要素合并到一个新的内存图层，srs为nil时不设置空间参考
+func MergeGDALFeaturesToLayer(features []*GDALFeature, layerName string, srs *SpatialReference) (*GDALLayer, error) {
+	handles := make([]C.OGRFeatureH, 0, len(features))
+	for _, feature := range features {
+		if feature != nil && feature.Feature != nil {
+			handles = append(handles, feature.Feature)
+		}
+	}
+	var srsHandle C.OGRSpatialReferenceH
+	if srs != nil {
+		srsHandle = srs.cPtr
+	}
+	defer runtime.KeepAlive(features)
+	defer runtime.KeepAlive(srs)
+	return MergeFeaturesToLayer(handles, layerName, srsHandle)
+}
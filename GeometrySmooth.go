@@ -0,0 +1,757 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+/*
+#include "osgeo_utils.h"
+*/
+import "C"
+import (
+	"container/heap"
+	"fmt"
+	"math"
+)
+
+// ============================================================================
+// 几何平滑与扩展简化算法（制图综合）
+// ============================================================================
+
+// SmoothMethod 平滑算法
+type SmoothMethod int
+
+const (
+	SmoothChaikin SmoothMethod = iota // Chaikin切角
+	SmoothBezier                      // 贝塞尔插值（曲线经过原顶点）
+	SmoothPAEK                        // 指数核多项式逼近（PAEK）
+)
+
+// SmoothOptions 平滑选项
+type SmoothOptions struct {
+	Method     SmoothMethod
+	Iterations int     // Chaikin迭代次数，默认3
+	Segments   int     // Bezier每段插值点数，默认8
+	Tolerance  float64 // PAEK平滑核长度（图层单位），必须大于0
+}
+
+// SimplifyMethod 简化算法
+type SimplifyMethod int
+
+const (
+	SimplifyDouglasPeucker SimplifyMethod = iota // 道格拉斯-普克，容差为距离
+	SimplifyVisvalingam                          // Visvalingam-Whyatt，容差为有效三角形面积
+)
+
+// curveTransformer 处理单条线或环的坐标，closed环的坐标不含闭合点
+type curveTransformer func(coords [][2]float64, closed bool) [][2]float64
+
+// readCurveCoordinates 读取线或环的二维坐标，环去除闭合点
+func readCurveCoordinates(curve C.OGRGeometryH, closed bool) [][2]float64 {
+	count := int(C.OGR_G_GetPointCount(curve))
+	coords := make([][2]float64, count)
+	for i := 0; i < count; i++ {
+		coords[i] = [2]float64{float64(C.OGR_G_GetX(curve, C.int(i))), float64(C.OGR_G_GetY(curve, C.int(i)))}
+	}
+	if closed && count > 1 && coords[0] == coords[count-1] {
+		coords = coords[:count-1]
+	}
+	return coords
+}
+
+// writeCurveCoordinates 写回线或环的坐标，环自动闭合
+func writeCurveCoordinates(curve C.OGRGeometryH, coords [][2]float64, closed bool) {
+	n := len(coords)
+	if closed {
+		n++
+	}
+	C.OGR_G_SetPointCount(curve, C.int(n))
+	for i, c := range coords {
+		C.OGR_G_SetPoint_2D(curve, C.int(i), C.double(c[0]), C.double(c[1]))
+	}
+	if closed {
+		C.OGR_G_SetPoint_2D(curve, C.int(len(coords)), C.double(coords[0][0]), C.double(coords[0][1]))
+	}
+}
+
+// rewriteGeometryCurves 对几何体中的每条线和环应用变换（原地修改）
+// 变换后点数不足（线少于2点、环少于3个不同点）时保留原坐标
+func rewriteGeometryCurves(geometry C.OGRGeometryH, fn curveTransformer) {
+	if geometry == nil {
+		return
+	}
+
+	flatType := C.OGR_GT_Flatten(C.OGR_G_GetGeometryType(geometry))
+	switch flatType {
+	case C.wkbLineString:
+		rewriteCurve(geometry, false, fn)
+	case C.wkbPolygon:
+		for i := 0; i < int(C.OGR_G_GetGeometryCount(geometry)); i++ {
+			rewriteCurve(C.OGR_G_GetGeometryRef(geometry, C.int(i)), true, fn)
+		}
+	case C.wkbMultiLineString, C.wkbMultiPolygon, C.wkbGeometryCollection:
+		for i := 0; i < int(C.OGR_G_GetGeometryCount(geometry)); i++ {
+			rewriteGeometryCurves(C.OGR_G_GetGeometryRef(geometry, C.int(i)), fn)
+		}
+	}
+}
+
+// visitGeometryCurves 只读遍历几何体中的每条线和环（环坐标不含闭合点）
+func visitGeometryCurves(geometry C.OGRGeometryH, fn func(coords [][2]float64, closed bool)) {
+	if geometry == nil {
+		return
+	}
+
+	switch C.OGR_GT_Flatten(C.OGR_G_GetGeometryType(geometry)) {
+	case C.wkbLineString:
+		if coords := readCurveCoordinates(geometry, false); len(coords) >= 2 {
+			fn(coords, false)
+		}
+	case C.wkbPolygon:
+		for i := 0; i < int(C.OGR_G_GetGeometryCount(geometry)); i++ {
+			if coords := readCurveCoordinates(C.OGR_G_GetGeometryRef(geometry, C.int(i)), true); len(coords) >= 3 {
+				fn(coords, true)
+			}
+		}
+	case C.wkbMultiLineString, C.wkbMultiPolygon, C.wkbGeometryCollection:
+		for i := 0; i < int(C.OGR_G_GetGeometryCount(geometry)); i++ {
+			visitGeometryCurves(C.OGR_G_GetGeometryRef(geometry, C.int(i)), fn)
+		}
+	}
+}
+
+func rewriteCurve(curve C.OGRGeometryH, closed bool, fn curveTransformer) {
+	coords := readCurveCoordinates(curve, closed)
+	if (closed && len(coords) < 3) || len(coords) < 2 {
+		return
+	}
+	result := fn(coords, closed)
+	if (closed && len(result) < 3) || len(result) < 2 {
+		return
+	}
+	writeCurveCoordinates(curve, result, closed)
+}
+
+// ============================================================================
+// 平滑
+// ============================================================================
+
+// smoothGeometry 平滑线或面几何体，返回新几何体（调用方负责释放；对外接口为 (*Geometry).Smooth）
+func smoothGeometry(geometry C.OGRGeometryH, options *SmoothOptions) (C.OGRGeometryH, error) {
+	if geometry == nil {
+		return nil, fmt.Errorf("几何体为空")
+	}
+	fn, err := smoothTransformer(options)
+	if err != nil {
+		return nil, err
+	}
+	result := C.OGR_G_Clone(geometry)
+	rewriteGeometryCurves(result, fn)
+	return result, nil
+}
+
+// SmoothLayer 平滑图层中的线和面要素
+func SmoothLayer(sourceLayer *GDALLayer, options *SmoothOptions) (*GDALLayer, error) {
+	if sourceLayer == nil || sourceLayer.layer == nil {
+		return nil, fmt.Errorf("源图层为空")
+	}
+	fn, err := smoothTransformer(options)
+	if err != nil {
+		return nil, err
+	}
+	return transformLayerGeometries(sourceLayer, sourceLayer.GetSpatialRef(), "smooth_result", func(geometry C.OGRGeometryH) error {
+		rewriteGeometryCurves(geometry, fn)
+		return nil
+	})
+}
+
+func smoothTransformer(options *SmoothOptions) (curveTransformer, error) {
+	if options == nil {
+		options = &SmoothOptions{}
+	}
+	switch options.Method {
+	case SmoothChaikin:
+		iterations := options.Iterations
+		if iterations <= 0 {
+			iterations = 3
+		}
+		return func(coords [][2]float64, closed bool) [][2]float64 {
+			return chaikinSmooth(coords, closed, iterations)
+		}, nil
+	case SmoothBezier:
+		segments := options.Segments
+		if segments <= 0 {
+			segments = 8
+		}
+		return func(coords [][2]float64, closed bool) [][2]float64 {
+			return bezierSmooth(coords, closed, segments)
+		}, nil
+	case SmoothPAEK:
+		if options.Tolerance <= 0 {
+			return nil, fmt.Errorf("PAEK平滑容差必须大于0")
+		}
+		return func(coords [][2]float64, closed bool) [][2]float64 {
+			return paekSmooth(coords, closed, options.Tolerance)
+		}, nil
+	default:
+		return nil, fmt.Errorf("不支持的平滑算法: %d", options.Method)
+	}
+}
+
+// chaikinSmooth Chaikin切角，开放线保留首尾点
+func chaikinSmooth(coords [][2]float64, closed bool, iterations int) [][2]float64 {
+	if !closed && len(coords) < 3 {
+		return coords
+	}
+	for iter := 0; iter < iterations; iter++ {
+		n := len(coords)
+		segments := n - 1
+		if closed {
+			segments = n
+		}
+		result := make([][2]float64, 0, 2*n)
+		if !closed {
+			result = append(result, coords[0])
+		}
+		for i := 0; i < segments; i++ {
+			p0, p1 := coords[i], coords[(i+1)%n]
+			q := [2]float64{0.75*p0[0] + 0.25*p1[0], 0.75*p0[1] + 0.25*p1[1]}
+			r := [2]float64{0.25*p0[0] + 0.75*p1[0], 0.25*p0[1] + 0.75*p1[1]}
+			if !closed && i == 0 {
+				result = append(result, r)
+				continue
+			}
+			if !closed && i == segments-1 {
+				result = append(result, q)
+				continue
+			}
+			result = append(result, q, r)
+		}
+		if !closed {
+			result = append(result, coords[n-1])
+		}
+		coords = result
+	}
+	return coords
+}
+
+// bezierSmooth 分段三次贝塞尔插值，控制点由相邻顶点切线确定（Catmull-Rom），曲线经过全部原顶点
+func bezierSmooth(coords [][2]float64, closed bool, segments int) [][2]float64 {
+	n := len(coords)
+	at := func(i int) [2]float64 {
+		if closed {
+			return coords[((i%n)+n)%n]
+		}
+		if i < 0 {
+			return coords[0]
+		}
+		if i >= n {
+			return coords[n-1]
+		}
+		return coords[i]
+	}
+
+	spans := n - 1
+	if closed {
+		spans = n
+	}
+	result := make([][2]float64, 0, spans*segments+1)
+	for i := 0; i < spans; i++ {
+		p0, p1, p2, p3 := at(i-1), at(i), at(i+1), at(i+2)
+		c1 := [2]float64{p1[0] + (p2[0]-p0[0])/6, p1[1] + (p2[1]-p0[1])/6}
+		c2 := [2]float64{p2[0] - (p3[0]-p1[0])/6, p2[1] - (p3[1]-p1[1])/6}
+		for s := 0; s < segments; s++ {
+			t := float64(s) / float64(segments)
+			mt := 1 - t
+			a, b, c, d := mt*mt*mt, 3*mt*mt*t, 3*mt*t*t, t*t*t
+			result = append(result, [2]float64{
+				a*p1[0] + b*c1[0] + c*c2[0] + d*p2[0],
+				a*p1[1] + b*c1[1] + c*c2[1] + d*p2[1],
+			})
+		}
+	}
+	if !closed {
+		result = append(result, coords[n-1])
+	}
+	return result
+}
+
+// paekSmooth PAEK平滑：沿线按固定步长重采样，再以高斯核对弧长邻域加权平均
+// tolerance为核长度，越大越平滑；开放线首尾点保持不变
+func paekSmooth(coords [][2]float64, closed bool, tolerance float64) [][2]float64 {
+	ring := coords
+	if closed {
+		ring = append(append([][2]float64{}, coords...), coords[0])
+	}
+
+	// 累计弧长
+	lengths := make([]float64, len(ring))
+	for i := 1; i < len(ring); i++ {
+		lengths[i] = lengths[i-1] + math.Hypot(ring[i][0]-ring[i-1][0], ring[i][1]-ring[i-1][1])
+	}
+	total := lengths[len(lengths)-1]
+	if total == 0 {
+		return coords
+	}
+
+	// 重采样
+	step := tolerance / 10
+	count := int(math.Ceil(total / step))
+	if count > 100000 {
+		count = 100000
+	}
+	if count < 4 {
+		count = 4
+	}
+	step = total / float64(count)
+	samples := make([][2]float64, 0, count+1)
+	seg := 1
+	for k := 0; k <= count; k++ {
+		s := float64(k) * step
+		for seg < len(lengths)-1 && lengths[seg] < s {
+			seg++
+		}
+		segLen := lengths[seg] - lengths[seg-1]
+		t := 0.0
+		if segLen > 0 {
+			t = (s - lengths[seg-1]) / segLen
+		}
+		samples = append(samples, [2]float64{
+			ring[seg-1][0] + t*(ring[seg][0]-ring[seg-1][0]),
+			ring[seg-1][1] + t*(ring[seg][1]-ring[seg-1][1]),
+		})
+	}
+	if closed {
+		samples = samples[:len(samples)-1]
+	}
+
+	// 高斯核加权
+	sigma := tolerance / 6
+	half := int(math.Ceil(tolerance / 2 / step))
+	m := len(samples)
+	result := make([][2]float64, m)
+	for i := 0; i < m; i++ {
+		if !closed && (i == 0 || i == m-1) {
+			result[i] = samples[i]
+			continue
+		}
+		var sx, sy, sw float64
+		for j := -half; j <= half; j++ {
+			idx := i + j
+			if closed {
+				idx = ((idx % m) + m) % m
+			} else if idx < 0 || idx >= m {
+				continue
+			}
+			d := float64(j) * step
+			w := math.Exp(-d * d / (2 * sigma * sigma))
+			sx += w * samples[idx][0]
+			sy += w * samples[idx][1]
+			sw += w
+		}
+		result[i] = [2]float64{sx / sw, sy / sw}
+	}
+	return result
+}
+
+// ============================================================================
+// 简化
+// ============================================================================
+
+// simplifyGeometryVW 使用Visvalingam-Whyatt算法简化几何体，返回新几何体（对外接口为 (*Geometry).SimplifyVW）
+// minArea: 有效三角形面积阈值（图层单位的平方），小于阈值的顶点被移除
+func simplifyGeometryVW(geometry C.OGRGeometryH, minArea float64) C.OGRGeometryH {
+	if geometry == nil {
+		return nil
+	}
+	result := C.OGR_G_Clone(geometry)
+	rewriteGeometryCurves(result, func(coords [][2]float64, closed bool) [][2]float64 {
+		return simplifyCoords(coords, closed, minArea, SimplifyVisvalingam)
+	})
+	return result
+}
+
+// SimplifyLayerVW 使用Visvalingam-Whyatt算法简化图层
+func SimplifyLayerVW(sourceLayer *GDALLayer, minArea float64) (*GDALLayer, error) {
+	if sourceLayer == nil || sourceLayer.layer == nil {
+		return nil, fmt.Errorf("源图层为空")
+	}
+	return transformLayerGeometries(sourceLayer, sourceLayer.GetSpatialRef(), "simplify_result", func(geometry C.OGRGeometryH) error {
+		rewriteGeometryCurves(geometry, func(coords [][2]float64, closed bool) [][2]float64 {
+			return simplifyCoords(coords, closed, minArea, SimplifyVisvalingam)
+		})
+		return nil
+	})
+}
+
+// simplifyCoords 简化坐标序列，环以首点为固定点展开处理
+func simplifyCoords(coords [][2]float64, closed bool, tolerance float64, method SimplifyMethod) [][2]float64 {
+	line := coords
+	if closed {
+		line = append(append([][2]float64{}, coords...), coords[0])
+	}
+
+	var keep []bool
+	if method == SimplifyVisvalingam {
+		keep = visvalingamKeep(line, tolerance)
+	} else {
+		keep = make([]bool, len(line))
+		keep[0], keep[len(line)-1] = true, true
+		douglasPeuckerKeep(line, 0, len(line)-1, tolerance, keep)
+	}
+
+	result := make([][2]float64, 0, len(line))
+	for i, k := range keep {
+		if k {
+			result = append(result, line[i])
+		}
+	}
+	if closed {
+		result = result[:len(result)-1]
+		if len(result) < 3 {
+			return coords
+		}
+	}
+	return result
+}
+
+// douglasPeuckerKeep 道格拉斯-普克算法，标记需保留的顶点
+func douglasPeuckerKeep(line [][2]float64, first, last int, tolerance float64, keep []bool) {
+	if last-first < 2 {
+		return
+	}
+	maxDist, index := -1.0, first
+	for i := first + 1; i < last; i++ {
+		d := pointSegmentDistance(line[i], line[first], line[last])
+		if d > maxDist {
+			maxDist, index = d, i
+		}
+	}
+	if maxDist > tolerance {
+		keep[index] = true
+		douglasPeuckerKeep(line, first, index, tolerance, keep)
+		douglasPeuckerKeep(line, index, last, tolerance, keep)
+	}
+}
+
+func pointSegmentDistance(p, a, b [2]float64) float64 {
+	dx, dy := b[0]-a[0], b[1]-a[1]
+	lenSq := dx*dx + dy*dy
+	if lenSq == 0 {
+		return math.Hypot(p[0]-a[0], p[1]-a[1])
+	}
+	t := ((p[0]-a[0])*dx + (p[1]-a[1])*dy) / lenSq
+	t = math.Max(0, math.Min(1, t))
+	return math.Hypot(p[0]-(a[0]+t*dx), p[1]-(a[1]+t*dy))
+}
+
+// vwVertex Visvalingam-Whyatt算法中的顶点
+type vwVertex struct {
+	index     int
+	area      float64
+	prev      int
+	next      int
+	heapIndex int
+}
+
+type vwHeap []*vwVertex
+
+func (h vwHeap) Len() int           { return len(h) }
+func (h vwHeap) Less(i, j int) bool { return h[i].area < h[j].area }
+func (h vwHeap) Swap(i, j int) {
+	h[i], h[j] = h[j], h[i]
+	h[i].heapIndex = i
+	h[j].heapIndex = j
+}
+func (h *vwHeap) Push(x interface{}) {
+	v := x.(*vwVertex)
+	v.heapIndex = len(*h)
+	*h = append(*h, v)
+}
+func (h *vwHeap) Pop() interface{} {
+	old := *h
+	v := old[len(old)-1]
+	*h = old[:len(old)-1]
+	v.heapIndex = -1
+	return v
+}
+
+// visvalingamKeep Visvalingam-Whyatt算法，首尾点固定，标记需保留的顶点
+func visvalingamKeep(line [][2]float64, minArea float64) []bool {
+	n := len(line)
+	keep := make([]bool, n)
+	for i := range keep {
+		keep[i] = true
+	}
+	if n < 3 {
+		return keep
+	}
+
+	triangleArea := func(a, b, c int) float64 {
+		return math.Abs((line[b][0]-line[a][0])*(line[c][1]-line[a][1])-(line[c][0]-line[a][0])*(line[b][1]-line[a][1])) / 2
+	}
+
+	vertices := make([]*vwVertex, n)
+	h := &vwHeap{}
+	for i := 0; i < n; i++ {
+		vertices[i] = &vwVertex{index: i, prev: i - 1, next: i + 1, heapIndex: -1}
+		if i > 0 && i < n-1 {
+			vertices[i].area = triangleArea(i-1, i, i+1)
+			heap.Push(h, vertices[i])
+		}
+	}
+
+	// 被移除顶点的面积作为下限，保证有效面积单调不减
+	maxRemoved := 0.0
+	for h.Len() > 0 {
+		v := heap.Pop(h).(*vwVertex)
+		if v.area >= minArea {
+			break
+		}
+		keep[v.index] = false
+		maxRemoved = math.Max(maxRemoved, v.area)
+
+		prev, next := vertices[v.prev], vertices[v.next]
+		prev.next = next.index
+		next.prev = prev.index
+		for _, u := range []*vwVertex{prev, next} {
+			if u.heapIndex < 0 {
+				continue
+			}
+			u.area = math.Max(triangleArea(u.prev, u.index, u.next), maxRemoved)
+			heap.Fix(h, u.heapIndex)
+		}
+	}
+	return keep
+}
+
+// ============================================================================
+// 保持公共边一致的覆盖简化
+// ============================================================================
+
+// CoverageSimplifyOptions 覆盖简化选项
+type CoverageSimplifyOptions struct {
+	Tolerance     float64        // 简化容差（道格拉斯-普克为距离，Visvalingam为面积）
+	Method        SimplifyMethod // 简化算法
+	SnapTolerance float64        // 判定顶点重合的容差，默认1e-9
+}
+
+// SimplifyCoverageLayer 对面覆盖图层（相邻面共享边界顶点）进行简化
+// 先将所有环在公共边界的端点处打断为弧段，每条弧段只简化一次并被相邻面共用，
+// 因此简化后相邻面之间不会产生缝隙或重叠；会退化的环保持原弧段不简化。
+func SimplifyCoverageLayer(sourceLayer *GDALLayer, options *CoverageSimplifyOptions) (*GDALLayer, error) {
+	if sourceLayer == nil || sourceLayer.layer == nil {
+		return nil, fmt.Errorf("源图层为空")
+	}
+	if options == nil || options.Tolerance <= 0 {
+		return nil, fmt.Errorf("简化容差必须大于0")
+	}
+	snap := options.SnapTolerance
+	if snap <= 0 {
+		snap = 1e-9
+	}
+
+	flatType := C.OGR_GT_Flatten(C.OGR_FD_GetGeomType(sourceLayer.GetLayerDefn()))
+	if flatType != C.wkbPolygon && flatType != C.wkbMultiPolygon && flatType != C.wkbUnknown {
+		return nil, fmt.Errorf("覆盖简化只支持面图层")
+	}
+
+	// 第一遍：收集所有环，构建拓扑
+	topo := newCoverageTopology(snap)
+	sourceLayer.ResetReading()
+	for {
+		feature := sourceLayer.GetNextFeatureRow()
+		if feature == nil {
+			break
+		}
+		if geometry := C.OGR_F_GetGeometryRef(feature); geometry != nil {
+			visitGeometryCurves(geometry, func(coords [][2]float64, closed bool) {
+				if closed {
+					topo.addRing(coords)
+				}
+			})
+		}
+		C.OGR_F_Destroy(feature)
+	}
+	sourceLayer.ResetReading()
+
+	topo.simplifyArcs(options.Tolerance, options.Method)
+
+	// 第二遍：用简化后的弧段重建各环
+	return transformLayerGeometries(sourceLayer, sourceLayer.GetSpatialRef(), "coverage_simplify_result", func(geometry C.OGRGeometryH) error {
+		rewriteGeometryCurves(geometry, func(coords [][2]float64, closed bool) [][2]float64 {
+			if !closed {
+				return coords
+			}
+			return topo.rebuildRing(coords)
+		})
+		return nil
+	})
+}
+
+// coverageTopology 覆盖图层的环-弧段拓扑
+type coverageTopology struct {
+	snap       float64
+	neighbours map[[2]int64]map[[2]int64]bool // 顶点 -> 相邻顶点集合
+	arcs       map[string][][2]float64        // 规范化弧段 -> 简化结果
+	rings      [][][2]float64
+}
+
+func newCoverageTopology(snap float64) *coverageTopology {
+	return &coverageTopology{
+		snap:       snap,
+		neighbours: make(map[[2]int64]map[[2]int64]bool),
+		arcs:       make(map[string][][2]float64),
+	}
+}
+
+func (t *coverageTopology) key(c [2]float64) [2]int64 {
+	return [2]int64{int64(math.Round(c[0] / t.snap)), int64(math.Round(c[1] / t.snap))}
+}
+
+func (t *coverageTopology) addRing(coords [][2]float64) {
+	t.rings = append(t.rings, coords)
+	n := len(coords)
+	for i := 0; i < n; i++ {
+		a, b := t.key(coords[i]), t.key(coords[(i+1)%n])
+		if a == b {
+			continue
+		}
+		if t.neighbours[a] == nil {
+			t.neighbours[a] = make(map[[2]int64]bool)
+		}
+		if t.neighbours[b] == nil {
+			t.neighbours[b] = make(map[[2]int64]bool)
+		}
+		t.neighbours[a][b] = true
+		t.neighbours[b][a] = true
+	}
+}
+
+// isNode 相邻顶点数不为2的顶点为弧段端点
+func (t *coverageTopology) isNode(c [2]float64) bool {
+	return len(t.neighbours[t.key(c)]) != 2
+}
+
+// splitRing 将环在节点处打断为弧段（首尾均为节点），无节点的环以首点为节点
+func (t *coverageTopology) splitRing(coords [][2]float64) [][][2]float64 {
+	n := len(coords)
+	start := -1
+	for i := 0; i < n; i++ {
+		if t.isNode(coords[i]) {
+			start = i
+			break
+		}
+	}
+	if start < 0 {
+		// 无节点的环（如岛与洞）以坐标最小的顶点为起点，保证共用该环的面得到相同弧段
+		start = 0
+		for i := 1; i < n; i++ {
+			a, b := t.key(coords[i]), t.key(coords[start])
+			if a[0] < b[0] || (a[0] == b[0] && a[1] < b[1]) {
+				start = i
+			}
+		}
+	}
+
+	var arcs [][][2]float64
+	current := [][2]float64{coords[start]}
+	for k := 1; k <= n; k++ {
+		c := coords[(start+k)%n]
+		current = append(current, c)
+		if k == n || t.isNode(c) {
+			arcs = append(arcs, current)
+			current = [][2]float64{c}
+		}
+	}
+	return arcs
+}
+
+// arcKey 弧段的方向无关标识，reversed表示规范方向与给定方向相反
+func (t *coverageTopology) arcKey(arc [][2]float64) (string, bool) {
+	forward := fmt.Sprint(t.keys(arc, false))
+	backward := fmt.Sprint(t.keys(arc, true))
+	if backward < forward {
+		return backward, true
+	}
+	return forward, false
+}
+
+func (t *coverageTopology) keys(arc [][2]float64, reverse bool) [][2]int64 {
+	keys := make([][2]int64, len(arc))
+	for i := range arc {
+		j := i
+		if reverse {
+			j = len(arc) - 1 - i
+		}
+		keys[i] = t.key(arc[j])
+	}
+	return keys
+}
+
+func reverseCoords(coords [][2]float64) [][2]float64 {
+	result := make([][2]float64, len(coords))
+	for i, c := range coords {
+		result[len(coords)-1-i] = c
+	}
+	return result
+}
+
+// simplifyArcs 简化全部弧段，对会导致环退化的弧段恢复原状
+func (t *coverageTopology) simplifyArcs(tolerance float64, method SimplifyMethod) {
+	for _, ring := range t.rings {
+		for _, arc := range t.splitRing(ring) {
+			key, reversed := t.arcKey(arc)
+			if _, ok := t.arcs[key]; ok {
+				continue
+			}
+			canonical := arc
+			if reversed {
+				canonical = reverseCoords(arc)
+			}
+			t.arcs[key] = simplifyCoords(canonical, false, tolerance, method)
+		}
+	}
+
+	for _, ring := range t.rings {
+		if len(t.rebuildRing(ring)) >= 3 {
+			continue
+		}
+		for _, arc := range t.splitRing(ring) {
+			key, reversed := t.arcKey(arc)
+			if reversed {
+				arc = reverseCoords(arc)
+			}
+			t.arcs[key] = arc
+		}
+	}
+}
+
+// rebuildRing 使用简化后的弧段重建环（不含闭合点）
+func (t *coverageTopology) rebuildRing(coords [][2]float64) [][2]float64 {
+	var result [][2]float64
+	for _, arc := range t.splitRing(coords) {
+		key, reversed := t.arcKey(arc)
+		simplified, ok := t.arcs[key]
+		if !ok {
+			simplified = arc
+		} else if reversed {
+			simplified = reverseCoords(simplified)
+		}
+		// 弧段首点与上一弧段末点重合
+		result = append(result, simplified[:len(simplified)-1]...)
+	}
+	return result
+}
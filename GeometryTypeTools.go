@@ -0,0 +1,431 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+/*
+#include "osgeo_utils.h"
+*/
+import "C"
+import (
+	"fmt"
+	"strings"
+)
+
+// ============================================================================
+// 多部件/单部件转换与几何类型强制转换
+// ============================================================================
+
+// GeometryBaseType 目标几何类型（取值与OGR类型代码一致）
+type GeometryBaseType int
+
+const (
+	GeometryPoint           GeometryBaseType = 1
+	GeometryLineString      GeometryBaseType = 2
+	GeometryPolygon         GeometryBaseType = 3
+	GeometryMultiPoint      GeometryBaseType = 4
+	GeometryMultiLineString GeometryBaseType = 5
+	GeometryMultiPolygon    GeometryBaseType = 6
+)
+
+// CoordinateDimension 坐标维度处理方式
+type CoordinateDimension int
+
+const (
+	DimensionKeep CoordinateDimension = iota // 保持源数据维度
+	DimensionXY                              // 去除Z和M
+	DimensionXYZ                             // 仅保留Z（缺失时Z为0）
+	DimensionXYM                             // 仅保留M（缺失时M为0）
+	DimensionXYZM                            // 同时保留Z和M
+)
+
+// ============================================================================
+// 多部件拆分
+// ============================================================================
+
+// ExplodeLayer 将多部件要素拆分为单部件要素
+// 结果保留源字段，并附加ORIG_FID（源要素FID）与partField（部件序号，从1开始，默认PART_IDX）字段；
+// 几何集合及嵌套的多部件几何体会被递归拆分。
+func ExplodeLayer(sourceLayer *GDALLayer, partField string) (*GDALLayer, error) {
+	if sourceLayer == nil || sourceLayer.layer == nil {
+		return nil, fmt.Errorf("源图层为空")
+	}
+	if partField == "" {
+		partField = "PART_IDX"
+	}
+
+	sourceDefn := sourceLayer.GetLayerDefn()
+	geomType := C.OGR_FD_GetGeomType(sourceDefn)
+	resultType := C.OGR_GT_GetSingle(geomType)
+	if C.OGR_GT_Flatten(geomType) == C.wkbGeometryCollection {
+		resultType = C.wkbUnknown
+	}
+
+	resultLayer, err := newMemoryResultLayer("explode_result", sourceLayer.GetLayerName(), sourceLayer.GetSpatialRef(), resultType)
+	if err != nil {
+		return nil, err
+	}
+	copyLayerFieldDefns(sourceDefn, resultLayer.layer)
+	addLayerField(resultLayer, "ORIG_FID", C.OFTInteger64)
+	addLayerField(resultLayer, partField, C.OFTInteger)
+	resultDefn := resultLayer.GetLayerDefn()
+	fidIndex := C.int(layerFieldIndex(resultDefn, "ORIG_FID"))
+	partIndex := C.int(layerFieldIndex(resultDefn, partField))
+
+	sourceLayer.ResetReading()
+	defer sourceLayer.ResetReading()
+	for {
+		feature := sourceLayer.GetNextFeatureRow()
+		if feature == nil {
+			break
+		}
+		geometry := C.OGR_F_GetGeometryRef(feature)
+		if geometry == nil {
+			C.OGR_F_Destroy(feature)
+			continue
+		}
+
+		for i, part := range explodeGeometry(geometry) {
+			newFeature := C.OGR_F_Create(resultDefn)
+			C.OGR_F_SetGeometryDirectly(newFeature, part)
+			copyFeatureAttributes(feature, newFeature, sourceDefn, resultDefn)
+			C.OGR_F_SetFieldInteger64(newFeature, fidIndex, C.OGR_F_GetFID(feature))
+			C.OGR_F_SetFieldInteger(newFeature, partIndex, C.int(i+1))
+			C.OGR_L_CreateFeature(resultLayer.layer, newFeature)
+			C.OGR_F_Destroy(newFeature)
+		}
+		C.OGR_F_Destroy(feature)
+	}
+
+	return resultLayer, nil
+}
+
+// explodeGeometry 递归拆分几何体，返回各单部件的克隆（调用方负责释放）
+func explodeGeometry(geometry C.OGRGeometryH) []C.OGRGeometryH {
+	if geometry == nil || C.OGR_G_IsEmpty(geometry) != 0 {
+		return nil
+	}
+	switch C.OGR_GT_Flatten(C.OGR_G_GetGeometryType(geometry)) {
+	case C.wkbMultiPoint, C.wkbMultiLineString, C.wkbMultiPolygon, C.wkbGeometryCollection,
+		C.wkbMultiCurve, C.wkbMultiSurface:
+		var parts []C.OGRGeometryH
+		for i := 0; i < int(C.OGR_G_GetGeometryCount(geometry)); i++ {
+			parts = append(parts, explodeGeometry(C.OGR_G_GetGeometryRef(geometry, C.int(i)))...)
+		}
+		return parts
+	default:
+		return []C.OGRGeometryH{C.OGR_G_Clone(geometry)}
+	}
+}
+
+// ============================================================================
+// 单部件合并
+// ============================================================================
+
+// CombineLayer 按字段将单部件要素合并为多部件要素（不做融合，部件保持原样）
+// groupFields为空时全部要素合并为一个要素；结果包含分组字段和COUNT字段。
+func CombineLayer(sourceLayer *GDALLayer, groupFields []string) (*GDALLayer, error) {
+	if sourceLayer == nil || sourceLayer.layer == nil {
+		return nil, fmt.Errorf("源图层为空")
+	}
+
+	sourceDefn := sourceLayer.GetLayerDefn()
+	groupIndexes := make([]int, len(groupFields))
+	for i, name := range groupFields {
+		groupIndexes[i] = layerFieldIndex(sourceDefn, name)
+		if groupIndexes[i] < 0 {
+			return nil, fmt.Errorf("分组字段不存在: %s", name)
+		}
+	}
+
+	geomType := C.OGR_FD_GetGeomType(sourceDefn)
+	resultType := combineCollectionType(geomType)
+
+	resultLayer, err := newMemoryResultLayer("combine_result", sourceLayer.GetLayerName(), sourceLayer.GetSpatialRef(), resultType)
+	if err != nil {
+		return nil, err
+	}
+	for _, index := range groupIndexes {
+		C.OGR_L_CreateField(resultLayer.layer, C.OGR_FD_GetFieldDefn(sourceDefn, C.int(index)), C.int(1))
+	}
+	addLayerField(resultLayer, "COUNT", C.OFTInteger)
+	resultDefn := resultLayer.GetLayerDefn()
+
+	type combineGroup struct {
+		geometry C.OGRGeometryH
+		sample   C.OGRFeatureH
+		count    int
+	}
+	groups := make(map[string]*combineGroup)
+	var order []string
+	defer func() {
+		for _, g := range groups {
+			if g.geometry != nil {
+				C.OGR_G_DestroyGeometry(g.geometry)
+			}
+			C.OGR_F_Destroy(g.sample)
+		}
+	}()
+
+	sourceLayer.ResetReading()
+	defer sourceLayer.ResetReading()
+	for {
+		feature := sourceLayer.GetNextFeatureRow()
+		if feature == nil {
+			break
+		}
+		geometry := C.OGR_F_GetGeometryRef(feature)
+		if geometry == nil {
+			C.OGR_F_Destroy(feature)
+			continue
+		}
+
+		values := make([]string, len(groupIndexes))
+		for i, index := range groupIndexes {
+			values[i] = C.GoString(C.OGR_F_GetFieldAsString(feature, C.int(index)))
+		}
+		key := strings.Join(values, "\x1f")
+
+		group, ok := groups[key]
+		if !ok {
+			group = &combineGroup{
+				geometry: C.OGR_G_CreateGeometry(resultType),
+				sample:   C.OGR_F_Clone(feature),
+			}
+			groups[key] = group
+			order = append(order, key)
+		}
+		for _, part := range explodeGeometry(geometry) {
+			// 类型不符的部件（如混入面图层的线）被跳过
+			if C.OGR_G_AddGeometryDirectly(group.geometry, part) != C.OGRERR_NONE {
+				C.OGR_G_DestroyGeometry(part)
+			}
+		}
+		group.count++
+		C.OGR_F_Destroy(feature)
+	}
+
+	countIndex := C.int(layerFieldIndex(resultDefn, "COUNT"))
+	for _, key := range order {
+		group := groups[key]
+		feature := C.OGR_F_Create(resultDefn)
+		C.OGR_F_SetGeometryDirectly(feature, group.geometry)
+		group.geometry = nil
+		copyFeatureAttributes(group.sample, feature, sourceDefn, resultDefn)
+		C.OGR_F_SetFieldInteger(feature, countIndex, C.int(group.count))
+		C.OGR_L_CreateFeature(resultLayer.layer, feature)
+		C.OGR_F_Destroy(feature)
+	}
+
+	return resultLayer, nil
+}
+
+// combineCollectionType 合并结果的集合类型：已是多部件/集合类型时保持不变，
+// 无对应集合类型（如未知类型）时使用GeometryCollection，保留Z/M
+func combineCollectionType(geomType C.OGRwkbGeometryType) C.OGRwkbGeometryType {
+	if C.OGR_GT_IsSubClassOf(geomType, C.wkbGeometryCollection) != 0 {
+		return geomType
+	}
+	resultType := C.OGR_GT_GetCollection(geomType)
+	if resultType == C.wkbUnknown {
+		resultType = C.OGR_GT_SetModifier(C.wkbGeometryCollection, C.OGR_GT_HasZ(geomType), C.OGR_GT_HasM(geomType))
+	}
+	return resultType
+}
+
+// ============================================================================
+// 几何类型强制转换
+// ============================================================================
+
+// ForceGeometryTypeOptions 几何类型强制转换选项
+type ForceGeometryTypeOptions struct {
+	TargetType GeometryBaseType    // 目标类型
+	Dimension  CoordinateDimension // 坐标维度处理
+	// DeriveLower 为true时，若几何体中没有目标维度的部件，则由高维部件降维得到
+	// （面→边界线，线/面→顶点）；否则该要素被丢弃
+	DeriveLower bool
+}
+
+// forceGeometryType 将几何体转换为目标类型，返回新几何体列表（调用方负责释放；对外接口为 (*Geometry).ForceType）
+// 从几何集合中提取与目标维度相同的部件，丢弃低维碎片（如叠加分析产生的线、点）；
+// 目标为单部件类型而结果有多个部件时，每个部件单独返回。
+func forceGeometryType(geometry C.OGRGeometryH, options *ForceGeometryTypeOptions) []C.OGRGeometryH {
+	if geometry == nil || options == nil {
+		return nil
+	}
+
+	dimension := geometryTypeDimension(options.TargetType)
+	parts := extractPartsByDimension(geometry, dimension)
+	if len(parts) == 0 && options.DeriveLower {
+		parts = deriveLowerDimensionParts(geometry, dimension)
+	}
+	if len(parts) == 0 {
+		return nil
+	}
+
+	hasZ, hasM := targetDimensionFlags(C.OGR_G_GetGeometryType(geometry), options.Dimension)
+	var results []C.OGRGeometryH
+	if options.TargetType >= GeometryMultiPoint {
+		multi := C.OGR_G_CreateGeometry(C.OGRwkbGeometryType(options.TargetType))
+		for _, part := range parts {
+			C.OGR_G_AddGeometryDirectly(multi, part)
+		}
+		results = append(results, multi)
+	} else {
+		results = parts
+	}
+
+	for _, g := range results {
+		setGeometryDimension(g, hasZ, hasM)
+	}
+	return results
+}
+
+// ForceGeometryTypeLayer 将图层几何体统一转换为目标类型，结果图层保留源字段
+func ForceGeometryTypeLayer(sourceLayer *GDALLayer, options *ForceGeometryTypeOptions) (*GDALLayer, error) {
+	if sourceLayer == nil || sourceLayer.layer == nil {
+		return nil, fmt.Errorf("源图层为空")
+	}
+	if options == nil || options.TargetType < GeometryPoint || options.TargetType > GeometryMultiPolygon {
+		return nil, fmt.Errorf("无效的目标几何类型")
+	}
+
+	sourceDefn := sourceLayer.GetLayerDefn()
+	hasZ, hasM := targetDimensionFlags(C.OGR_FD_GetGeomType(sourceDefn), options.Dimension)
+	resultType := C.OGR_GT_SetModifier(C.OGRwkbGeometryType(options.TargetType), boolToCInt(hasZ), boolToCInt(hasM))
+
+	resultLayer, err := newMemoryResultLayer("force_type_result", sourceLayer.GetLayerName(), sourceLayer.GetSpatialRef(), resultType)
+	if err != nil {
+		return nil, err
+	}
+	copyLayerFieldDefns(sourceDefn, resultLayer.layer)
+	resultDefn := resultLayer.GetLayerDefn()
+
+	sourceLayer.ResetReading()
+	defer sourceLayer.ResetReading()
+	for {
+		feature := sourceLayer.GetNextFeatureRow()
+		if feature == nil {
+			break
+		}
+
+		for _, g := range forceGeometryType(C.OGR_F_GetGeometryRef(feature), options) {
+			// 以图层维度为准（DimensionKeep时按图层定义统一）
+			setGeometryDimension(g, hasZ, hasM)
+			newFeature := C.OGR_F_Create(resultDefn)
+			C.OGR_F_SetGeometryDirectly(newFeature, g)
+			copyFeatureAttributes(feature, newFeature, sourceDefn, resultDefn)
+			C.OGR_L_CreateFeature(resultLayer.layer, newFeature)
+			C.OGR_F_Destroy(newFeature)
+		}
+		C.OGR_F_Destroy(feature)
+	}
+
+	return resultLayer, nil
+}
+
+// geometryTypeDimension 目标类型的拓扑维度：点0、线1、面2
+func geometryTypeDimension(t GeometryBaseType) int {
+	switch t {
+	case GeometryPoint, GeometryMultiPoint:
+		return 0
+	case GeometryLineString, GeometryMultiLineString:
+		return 1
+	default:
+		return 2
+	}
+}
+
+// extractPartsByDimension 递归提取指定维度的单部件（克隆）
+func extractPartsByDimension(geometry C.OGRGeometryH, dimension int) []C.OGRGeometryH {
+	var result []C.OGRGeometryH
+	for _, part := range explodeGeometry(geometry) {
+		partType := C.OGR_GT_Flatten(C.OGR_G_GetGeometryType(part))
+		if int(C.OGR_G_GetDimension(part)) != dimension {
+			C.OGR_G_DestroyGeometry(part)
+			continue
+		}
+		// 曲线类型线性化
+		if partType != C.wkbPoint && partType != C.wkbLineString && partType != C.wkbPolygon {
+			var linear C.OGRGeometryH
+			if dimension == 1 {
+				linear = C.OGR_G_ForceToLineString(part)
+			} else {
+				linear = C.OGR_G_ForceToPolygon(part)
+			}
+			if linear == nil {
+				continue
+			}
+			part = linear
+		}
+		result = append(result, part)
+	}
+	return result
+}
+
+// deriveLowerDimensionParts 由高维部件降维：面取边界环为线，线和面取顶点为点
+func deriveLowerDimensionParts(geometry C.OGRGeometryH, dimension int) []C.OGRGeometryH {
+	var result []C.OGRGeometryH
+	switch dimension {
+	case 1:
+		for _, polygon := range extractPartsByDimension(geometry, 2) {
+			for i := 0; i < int(C.OGR_G_GetGeometryCount(polygon)); i++ {
+				ring := C.OGR_G_GetGeometryRef(polygon, C.int(i))
+				line := C.OGR_G_ForceToLineString(C.OGR_G_Clone(ring))
+				if line != nil {
+					result = append(result, line)
+				}
+			}
+			C.OGR_G_DestroyGeometry(polygon)
+		}
+	case 0:
+		for _, p := range collectGeometryPoints(geometry) {
+			point := C.OGR_G_CreateGeometry(C.wkbPoint)
+			C.OGR_G_SetPoint_2D(point, 0, C.double(p[0]), C.double(p[1]))
+			result = append(result, point)
+		}
+	}
+	return result
+}
+
+// targetDimensionFlags 根据源类型和维度选项确定结果是否带Z/M
+func targetDimensionFlags(sourceType C.OGRwkbGeometryType, dim CoordinateDimension) (bool, bool) {
+	switch dim {
+	case DimensionXY:
+		return false, false
+	case DimensionXYZ:
+		return true, false
+	case DimensionXYM:
+		return false, true
+	case DimensionXYZM:
+		return true, true
+	default:
+		return C.OGR_GT_HasZ(sourceType) != 0, C.OGR_GT_HasM(sourceType) != 0
+	}
+}
+
+// setGeometryDimension 设置几何体的Z/M维度
+func setGeometryDimension(geometry C.OGRGeometryH, hasZ, hasM bool) {
+	C.OGR_G_Set3D(geometry, boolToCInt(hasZ))
+	C.OGR_G_SetMeasured(geometry, boolToCInt(hasM))
+}
+
+func boolToCInt(v bool) C.int {
+	if v {
+		return 1
+	}
+	return 0
+}
@@ -0,0 +1,80 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+import (
+	"math"
+	"testing"
+)
+
+// 多部件源图层：合并结果应保持MultiPolygon类型并包含全部部件
+const multiPolygonFeatures = `{"type": "FeatureCollection", "features": [
+{"type": "Feature", "properties": {"name": "a"}, "geometry": {"type": "MultiPolygon", "coordinates": [
+  [[[0, 0], [1, 0], [1, 1], [0, 1], [0, 0]]],
+  [[[2, 0], [3, 0], [3, 1], [2, 1], [2, 0]]]]}},
+{"type": "Feature", "properties": {"name": "a"}, "geometry": {"type": "MultiPolygon", "coordinates": [
+  [[[4, 0], [5, 0], [5, 1], [4, 1], [4, 0]]]]}},
+{"type": "Feature", "properties": {"name": "b"}, "geometry": {"type": "MultiPolygon", "coordinates": [
+  [[[0, 2], [2, 2], [2, 4], [0, 4], [0, 2]]]]}}
+]}`
+
+func TestCombineLayerMultiPolygon(t *testing.T) {
+	layer := testGeoJSONLayer(t, multiPolygonFeatures)
+
+	result, err := CombineLayer(layer, []string{"name"})
+	if err != nil {
+		t.Fatalf("CombineLayer失败: %v", err)
+	}
+	defer result.Close()
+
+	if geomType := result.GetGeometryType(); geomType != "Multi Polygon" {
+		t.Fatalf("结果图层类型应为Multi Polygon，实际 %s", geomType)
+	}
+	if count := result.GetFeatureCount(); count != 2 {
+		t.Fatalf("结果要素数应为2，实际 %d", count)
+	}
+
+	expected := map[string]struct {
+		count int
+		area  float64
+	}{
+		"a": {count: 2, area: 3},
+		"b": {count: 1, area: 4},
+	}
+	for {
+		feature := result.GetNextFeature()
+		if feature == nil {
+			break
+		}
+		name := feature.GetFieldAsString("name")
+		want, ok := expected[name]
+		if !ok {
+			t.Fatalf("意外的分组: %q", name)
+		}
+		geometry := feature.GetGeometry()
+		if geometry.IsNil() {
+			t.Fatalf("分组 %s 的几何为空", name)
+		}
+		if got := feature.GetFieldAsInteger("COUNT"); got != want.count {
+			t.Fatalf("分组 %s 的COUNT应为%d，实际 %d", name, want.count, got)
+		}
+		if area := geometry.Area(); math.Abs(area-want.area) > 1e-9 {
+			t.Fatalf("分组 %s 的面积应为%v，实际 %v", name, want.area, area)
+		}
+		feature.Destroy()
+	}
+}
@@ -216,6 +216,7 @@ func executeConcurrentIdentityAnalysis(tileGroups []GroupTileFiles, resultLayer
 				if err != nil {
 					processingError = fmt.Errorf("合并分块 %d 结果失败: %v", result.index, err)
 					log.Printf("错误: %v", processingError)
+					result.layer.Close()
 					return
 				}
 
@@ -255,6 +256,7 @@ func executeConcurrentIdentityAnalysis(tileGroups []GroupTileFiles, resultLayer
 
 	// 等待结果收集完成
 	resultWg.Wait()
+	releaseTaskResults(results)
 
 	if processingError != nil {
 		return processingError
@@ -305,6 +307,7 @@ func processTileGroupforIdentity(tileGroup GroupTileFiles, config *ParallelGeosC
 	// 加载layer2的bin文件
 	methodTileLayer, err := DeserializeLayerFromFile(tileGroup.GPBin.Layer2)
 	if err != nil {
+		inputTileLayer.Close()
 		return nil, fmt.Errorf("加载擦除分块文件失败: %v", err)
 	}
 	defer func() {
@@ -424,7 +427,7 @@ func createIdentityAnalysisResultLayer(inputLayer, methodLayer *GDALLayer, strat
 	}
 
 	resultLayer := &GDALLayer{layer: resultLayerPtr}
-	runtime.SetFinalizer(resultLayer, (*GDALLayer).cleanup)
+	manageLayer(resultLayer)
 
 	// 添加字段定义
 	err := addIdentityFields(resultLayer, inputLayer, methodLayer, strategy)
@@ -492,7 +495,7 @@ func createIdentityTileResultLayer(inputLayer, methodLayer *GDALLayer, layerName
 	}
 
 	resultLayer := &GDALLayer{layer: resultLayerPtr}
-	runtime.SetFinalizer(resultLayer, (*GDALLayer).cleanup)
+	manageLayer(resultLayer)
 
 	// 添加字段定义
 	err := addIdentityFields(resultLayer, inputLayer, methodLayer, strategy)
@@ -729,6 +732,7 @@ func ExecuteConcurrentIdentityAnalysisPG(tileGroups []GroupTileFiles, resultLaye
 				if err != nil {
 					processingError = fmt.Errorf("合并分块 %d 结果失败: %v", result.index, err)
 					log.Printf("错误: %v", processingError)
+					result.layer.Close()
 					return
 				}
 				// 释放临时图层资源
@@ -759,6 +763,7 @@ func ExecuteConcurrentIdentityAnalysisPG(tileGroups []GroupTileFiles, resultLaye
 	close(results)
 	// 等待结果收集完成
 	resultWg.Wait()
+	releaseTaskResults(results)
 	if processingError != nil {
 		return processingError
 	}
@@ -114,6 +114,7 @@ func (p *ImageProcessor) AddTileFromBuffer(data []byte, format string, dstX, dst
 	}
 	C.VSIFCloseL(fp)
 	p.vsimemPaths = append(p.vsimemPaths, vsimemPath)
+	trackVSIMem(vsimemPath)
 	// 打开数据集
 	hDS := C.GDALOpen(cVsimemPath, C.GA_ReadOnly)
 	if hDS == nil {
@@ -155,9 +156,9 @@ func (p *ImageProcessor) AddTileFromBufferWithSize(data []byte, format string,
 	vsimemPath := fmt.Sprintf("/vsimem/tile_%d.%s", tileID, format)
 	cVsimemPath := C.CString(vsimemPath)
 
-	// 将数据写入vsimem
+	// 将数据写入vsimem（缓冲区在数据集关闭前必须保持有效，由Close统一释放）
 	cData := C.CBytes(data)
-	defer C.free(cData)
+	p.memBuffers = append(p.memBuffers, cData)
 
 	fp := C.VSIFileFromMemBuffer(cVsimemPath, (*C.GByte)(cData), C.vsi_l_offset(len(data)), C.FALSE)
 	if fp == nil {
@@ -167,6 +168,7 @@ func (p *ImageProcessor) AddTileFromBufferWithSize(data []byte, format string,
 	C.VSIFCloseL(fp)
 
 	p.vsimemPaths = append(p.vsimemPaths, vsimemPath)
+	trackVSIMem(vsimemPath)
 
 	// 打开数据集
 	hDS := C.GDALOpen(cVsimemPath, C.GA_ReadOnly)
@@ -337,6 +339,7 @@ func (p *ImageProcessor) Close() {
 		cPath := C.CString(path)
 		C.VSIUnlink(cPath)
 		C.free(unsafe.Pointer(cPath))
+		untrackVSIMem(path)
 	}
 	p.vsimemPaths = nil
 	// 3. 释放 C 内存
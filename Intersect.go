@@ -216,6 +216,7 @@ func executeConcurrentIntersectionAnalysis(tileGroups []GroupTileFiles, resultLa
 				if err != nil {
 					processingError = fmt.Errorf("合并分块 %d 结果失败: %v", result.index, err)
 					log.Printf("错误: %v", processingError)
+					result.layer.Close()
 					return
 				}
 
@@ -255,6 +256,7 @@ func executeConcurrentIntersectionAnalysis(tileGroups []GroupTileFiles, resultLa
 
 	// 等待结果收集完成
 	resultWg.Wait()
+	releaseTaskResults(results)
 
 	if processingError != nil {
 		return processingError
@@ -303,6 +305,7 @@ func processTileGroupforIntersection(tileGroup GroupTileFiles, config *ParallelG
 	// 加载layer2的bin文件
 	methodTileLayer, err := DeserializeLayerFromFile(tileGroup.GPBin.Layer2)
 	if err != nil {
+		inputTileLayer.Close()
 		return nil, fmt.Errorf("加载擦除分块文件失败: %v", err)
 	}
 	defer func() {
@@ -342,7 +345,7 @@ func createIntersectionTileResultLayer(inputLayer, methodLayer *GDALLayer, layer
 	}
 
 	resultLayer := &GDALLayer{layer: resultLayerPtr}
-	runtime.SetFinalizer(resultLayer, (*GDALLayer).cleanup)
+	manageLayer(resultLayer)
 
 	// 根据策略添加字段定义
 	err := addFieldsBasedOnStrategy(resultLayer, inputLayer, methodLayer, strategy)
@@ -474,7 +477,7 @@ func CreateIntersectionResultLayer(layer1, layer2 *GDALLayer, strategy FieldMerg
 	}
 
 	resultLayer := &GDALLayer{layer: resultLayerPtr}
-	runtime.SetFinalizer(resultLayer, (*GDALLayer).cleanup)
+	manageLayer(resultLayer)
 
 	// 根据策略添加字段定义
 	err := addFieldsBasedOnStrategy(resultLayer, layer1, layer2, strategy)
@@ -756,6 +759,7 @@ func ExecuteConcurrentIntersectionAnalysisPG(tileGroups []GroupTileFiles, result
 				if err != nil {
 					processingError = fmt.Errorf("合并分块 %d 结果失败: %v", result.index, err)
 					log.Printf("错误: %v", processingError)
+					result.layer.Close()
 					return
 				}
 				// 释放临时图层资源
@@ -786,6 +790,7 @@ func ExecuteConcurrentIntersectionAnalysisPG(tileGroups []GroupTileFiles, result
 	close(results)
 	// 等待结果收集完成
 	resultWg.Wait()
+	releaseTaskResults(results)
 	if processingError != nil {
 		return processingError
 	}
@@ -24,7 +24,6 @@ import (
 	"fmt"
 	"math"
 	"os"
-	"runtime"
 	"strings"
 	"sync"
 	"time"
@@ -447,10 +446,10 @@ func createLayerWithIdentifierField(sourceLayer *GDALLayer, identifierFieldName
 	})
 
 	// 创建新的GDALLayer包装器
-	result := &GDALLayer{
+	result := manageLayer(&GDALLayer{
 		layer:   newLayer,
 		dataset: dataSource,
-	}
+	})
 
 	fmt.Printf("成功创建带标识字段的图层，共处理 %d 个要素\n", featureID-1)
 	return result, nil
@@ -504,7 +503,7 @@ func createMemoryLayerCopy(sourceLayer *GDALLayer, layerName string) (*GDALLayer
 		layer:   memLayerPtr,
 		dataset: dataSource,
 	}
-	runtime.SetFinalizer(memLayer, (*GDALLayer).cleanup)
+	manageLayer(memLayer)
 
 	// 复制字段定义
 
@@ -874,6 +873,15 @@ type taskResult struct {
 	index    int
 }
 
+// releaseTaskResults 关闭结果队列中未合并的分块图层（结果收集因错误提前退出时使用）
+func releaseTaskResults(results <-chan taskResult) {
+	for result := range results {
+		if result.layer != nil {
+			result.layer.Close()
+		}
+	}
+}
+
 // createTileResultLayer 为分块创建结果图层
 func createTileResultLayer(inputLayer *GDALLayer, layerName string) (*GDALLayer, error) {
 	layerNameC := C.CString(layerName)
@@ -889,7 +897,7 @@ func createTileResultLayer(inputLayer *GDALLayer, layerName string) (*GDALLayer,
 	}
 
 	resultLayer := &GDALLayer{layer: resultLayerPtr}
-	runtime.SetFinalizer(resultLayer, (*GDALLayer).cleanup)
+	manageLayer(resultLayer)
 
 	// 添加字段定义
 	err := addLayerFields(resultLayer, inputLayer, "")
@@ -0,0 +1,618 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+/*
+#include "osgeo_utils.h"
+*/
+import "C"
+import (
+	"fmt"
+	"math"
+	"sort"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// ============================================================================
+// 图层版本变化检测
+// ============================================================================
+
+// ChangeType 要素变化类型
+type ChangeType string
+
+const (
+	ChangeAdded             ChangeType = "ADDED"              // 新增
+	ChangeDeleted           ChangeType = "DELETED"            // 删除
+	ChangeGeometry          ChangeType = "GEOMETRY"           // 仅图形变化
+	ChangeAttribute         ChangeType = "ATTRIBUTE"          // 仅属性变化
+	ChangeGeometryAttribute ChangeType = "GEOMETRY_ATTRIBUTE" // 图形和属性均变化
+	ChangeUnchanged         ChangeType = "UNCHANGED"          // 未变化
+)
+
+// LayerDiffOptions 变化检测选项
+type LayerDiffOptions struct {
+	KeyField          string   // 关键字段；为空时按图形匹配
+	Tolerance         float64  // 图形相等容差（图层单位），两图形相互距离均不超过容差视为相同
+	CompareFields     []string // 参与比较的字段，为空时比较两图层的全部同名字段
+	IgnoreFields      []string // 不参与比较的字段
+	MatchOverlapRatio float64  // 无关键字段时，面要素重叠比例达到该值视为同一要素的图形变化，默认0.8
+	IncludeUnchanged  bool     // 结果图层是否包含未变化的要素
+}
+
+// FeatureChange 单个要素的变化
+type FeatureChange struct {
+	Key           string
+	Type          ChangeType
+	OldFID        int64 // 删除或变化要素在旧图层中的FID，新增时为-1
+	NewFID        int64 // 新增或变化要素在新图层中的FID，删除时为-1
+	ChangedFields []string
+}
+
+// LayerDiffSummary 变化统计
+type LayerDiffSummary struct {
+	Added            int
+	Deleted          int
+	GeometryChanged  int // 含图形和属性均变化的要素
+	AttributeChanged int // 含图形和属性均变化的要素
+	Unchanged        int
+}
+
+// LayerDiffResult 变化检测结果
+type LayerDiffResult struct {
+	Changes []FeatureChange
+	Summary LayerDiffSummary
+	Layer   *GDALLayer          // 变化图层：变化信息字段及新图层的全部字段
+	Fields  LayerDiffFieldNames // 变化图层中变化信息字段的实际名称
+}
+
+// LayerDiffFieldNames 变化信息字段名，默认为CHANGE、CHG_KEY、OLD_FID、NEW_FID、CHG_FIELDS，
+// 与新旧图层字段重名（不区分大小写）时加DIFF_前缀，仍重名时再加数字后缀
+type LayerDiffFieldNames struct {
+	Change        string
+	Key           string
+	OldFID        string
+	NewFID        string
+	ChangedFields string
+}
+
+// diffFieldIndices 变化信息字段在变化图层中的索引
+type diffFieldIndices struct {
+	change, key, oldFID, newFID, changedFields C.int
+}
+
+// String 返回统计信息
+func (s LayerDiffSummary) String() string {
+	return fmt.Sprintf("新增: %d, 删除: %d, 图形变化: %d, 属性变化: %d, 未变化: %d",
+		s.Added, s.Deleted, s.GeometryChanged, s.AttributeChanged, s.Unchanged)
+}
+
+// diffFeature 缓存的旧图层要素
+type diffFeature struct {
+	fid      int64
+	key      string
+	feature  C.OGRFeatureH
+	matched  bool
+	geometry C.OGRGeometryH // 引用feature内的几何体
+	envelope C.OGREnvelope
+}
+
+// LayerDiff 比较两个版本的图层，识别新增、删除、图形变化和属性变化的要素
+func LayerDiff(oldLayer, newLayer *GDALLayer, options *LayerDiffOptions) (*LayerDiffResult, error) {
+	if oldLayer == nil || oldLayer.layer == nil || newLayer == nil || newLayer.layer == nil {
+		return nil, fmt.Errorf("比较图层为空")
+	}
+	if options == nil {
+		options = &LayerDiffOptions{}
+	}
+	opts := *options
+	if opts.MatchOverlapRatio <= 0 {
+		opts.MatchOverlapRatio = 0.8
+	}
+
+	oldDefn := oldLayer.GetLayerDefn()
+	newDefn := newLayer.GetLayerDefn()
+
+	oldKeyIndex, newKeyIndex := -1, -1
+	if opts.KeyField != "" {
+		oldKeyIndex = layerFieldIndex(oldDefn, opts.KeyField)
+		newKeyIndex = layerFieldIndex(newDefn, opts.KeyField)
+		if oldKeyIndex < 0 || newKeyIndex < 0 {
+			return nil, fmt.Errorf("关键字段在两个图层中必须都存在: %s", opts.KeyField)
+		}
+	}
+
+	fieldPairs, err := diffFieldPairs(oldDefn, newDefn, &opts)
+	if err != nil {
+		return nil, err
+	}
+
+	// 读取旧图层
+	var oldFeatures []*diffFeature
+	oldByKey := make(map[string]*diffFeature)
+	defer func() {
+		for _, f := range oldFeatures {
+			C.OGR_F_Destroy(f.feature)
+		}
+	}()
+	oldLayer.ResetReading()
+	for {
+		feature := oldLayer.GetNextFeatureRow()
+		if feature == nil {
+			break
+		}
+		f := &diffFeature{
+			fid:      int64(C.OGR_F_GetFID(feature)),
+			feature:  feature,
+			geometry: C.OGR_F_GetGeometryRef(feature),
+		}
+		if f.geometry != nil {
+			C.OGR_G_GetEnvelope(f.geometry, &f.envelope)
+		}
+		if oldKeyIndex >= 0 {
+			f.key = C.GoString(C.OGR_F_GetFieldAsString(feature, C.int(oldKeyIndex)))
+			if _, exists := oldByKey[f.key]; exists {
+				C.OGR_F_Destroy(feature)
+				oldLayer.ResetReading()
+				return nil, fmt.Errorf("旧图层关键字段值重复: %s", f.key)
+			}
+			oldByKey[f.key] = f
+		}
+		oldFeatures = append(oldFeatures, f)
+	}
+	oldLayer.ResetReading()
+
+	resultLayer, fieldNames, fieldIndices, err := newDiffResultLayer(newDefn, oldDefn, newLayer.GetSpatialRef())
+	if err != nil {
+		return nil, err
+	}
+	result := &LayerDiffResult{Layer: resultLayer, Fields: fieldNames}
+
+	var index *diffEnvelopeIndex
+	if newKeyIndex < 0 {
+		index = newDiffEnvelopeIndex(oldFeatures)
+	}
+
+	newKeys := make(map[string]bool)
+	newLayer.ResetReading()
+	defer newLayer.ResetReading()
+	for {
+		feature := newLayer.GetNextFeatureRow()
+		if feature == nil {
+			break
+		}
+		geometry := C.OGR_F_GetGeometryRef(feature)
+		change := FeatureChange{OldFID: -1, NewFID: int64(C.OGR_F_GetFID(feature))}
+
+		var old *diffFeature
+		if newKeyIndex >= 0 {
+			change.Key = C.GoString(C.OGR_F_GetFieldAsString(feature, C.int(newKeyIndex)))
+			if newKeys[change.Key] {
+				C.OGR_F_Destroy(feature)
+				resultLayer.Close()
+				return nil, fmt.Errorf("新图层关键字段值重复: %s", change.Key)
+			}
+			newKeys[change.Key] = true
+			old = oldByKey[change.Key]
+		} else {
+			old = matchByGeometry(geometry, oldFeatures, index, &opts)
+			change.Key = strconv.FormatInt(change.NewFID, 10)
+		}
+
+		if old == nil {
+			change.Type = ChangeAdded
+		} else {
+			old.matched = true
+			change.OldFID = old.fid
+			geomChanged := !geometriesEqual(old.geometry, geometry, opts.Tolerance)
+			change.ChangedFields = diffFeatureFields(old.feature, feature, fieldPairs)
+			switch {
+			case geomChanged && len(change.ChangedFields) > 0:
+				change.Type = ChangeGeometryAttribute
+			case geomChanged:
+				change.Type = ChangeGeometry
+			case len(change.ChangedFields) > 0:
+				change.Type = ChangeAttribute
+			default:
+				change.Type = ChangeUnchanged
+			}
+		}
+
+		result.record(change)
+		if change.Type != ChangeUnchanged || opts.IncludeUnchanged {
+			writeDiffFeature(resultLayer, fieldIndices, change, feature, newDefn)
+		}
+		C.OGR_F_Destroy(feature)
+	}
+
+	for _, old := range oldFeatures {
+		if old.matched {
+			continue
+		}
+		change := FeatureChange{Key: old.key, Type: ChangeDeleted, OldFID: old.fid, NewFID: -1}
+		if change.Key == "" {
+			change.Key = strconv.FormatInt(old.fid, 10)
+		}
+		result.record(change)
+		writeDiffFeature(resultLayer, fieldIndices, change, old.feature, oldDefn)
+	}
+
+	return result, nil
+}
+
+func (r *LayerDiffResult) record(change FeatureChange) {
+	r.Changes = append(r.Changes, change)
+	switch change.Type {
+	case ChangeAdded:
+		r.Summary.Added++
+	case ChangeDeleted:
+		r.Summary.Deleted++
+	case ChangeGeometry:
+		r.Summary.GeometryChanged++
+	case ChangeAttribute:
+		r.Summary.AttributeChanged++
+	case ChangeGeometryAttribute:
+		r.Summary.GeometryChanged++
+		r.Summary.AttributeChanged++
+	case ChangeUnchanged:
+		r.Summary.Unchanged++
+	}
+}
+
+// diffFieldPair 两图层中同名字段的索引
+type diffFieldPair struct {
+	name      string
+	oldIndex  C.int
+	newIndex  C.int
+	fieldType C.OGRFieldType
+}
+
+// diffFieldPairs 确定参与比较的字段
+func diffFieldPairs(oldDefn, newDefn C.OGRFeatureDefnH, opts *LayerDiffOptions) ([]diffFieldPair, error) {
+	ignore := make(map[string]bool)
+	for _, name := range opts.IgnoreFields {
+		ignore[strings.ToUpper(name)] = true
+	}
+	if opts.KeyField != "" {
+		ignore[strings.ToUpper(opts.KeyField)] = true
+	}
+
+	names := opts.CompareFields
+	if len(names) == 0 {
+		for i := 0; i < int(C.OGR_FD_GetFieldCount(newDefn)); i++ {
+			names = append(names, C.GoString(C.OGR_Fld_GetNameRef(C.OGR_FD_GetFieldDefn(newDefn, C.int(i)))))
+		}
+	}
+
+	var pairs []diffFieldPair
+	for _, name := range names {
+		if ignore[strings.ToUpper(name)] {
+			continue
+		}
+		oldIndex := layerFieldIndex(oldDefn, name)
+		newIndex := layerFieldIndex(newDefn, name)
+		if newIndex < 0 || oldIndex < 0 {
+			if len(opts.CompareFields) > 0 {
+				return nil, fmt.Errorf("比较字段在两个图层中必须都存在: %s", name)
+			}
+			continue
+		}
+		pairs = append(pairs, diffFieldPair{
+			name:      name,
+			oldIndex:  C.int(oldIndex),
+			newIndex:  C.int(newIndex),
+			fieldType: C.OGR_Fld_GetType(C.OGR_FD_GetFieldDefn(newDefn, C.int(newIndex))),
+		})
+	}
+	return pairs, nil
+}
+
+// diffFeatureFields 返回取值不同的字段名
+func diffFeatureFields(oldFeature, newFeature C.OGRFeatureH, pairs []diffFieldPair) []string {
+	var changed []string
+	for _, p := range pairs {
+		oldSet := C.OGR_F_IsFieldSetAndNotNull(oldFeature, p.oldIndex) != 0
+		newSet := C.OGR_F_IsFieldSetAndNotNull(newFeature, p.newIndex) != 0
+		if oldSet != newSet {
+			changed = append(changed, p.name)
+			continue
+		}
+		if !oldSet {
+			continue
+		}
+
+		if p.fieldType == C.OFTReal {
+			a := float64(C.OGR_F_GetFieldAsDouble(oldFeature, p.oldIndex))
+			b := float64(C.OGR_F_GetFieldAsDouble(newFeature, p.newIndex))
+			if math.Abs(a-b) > 1e-9*math.Max(1, math.Max(math.Abs(a), math.Abs(b))) {
+				changed = append(changed, p.name)
+			}
+			continue
+		}
+		a := C.GoString(C.OGR_F_GetFieldAsString(oldFeature, p.oldIndex))
+		b := C.GoString(C.OGR_F_GetFieldAsString(newFeature, p.newIndex))
+		if a != b {
+			changed = append(changed, p.name)
+		}
+	}
+	return changed
+}
+
+// geometriesEqual 判断两个几何体在容差范围内是否相同
+// 先逐顶点比较，顶点结构不同时再检查两者是否互相位于对方的容差缓冲区内
+func geometriesEqual(a, b C.OGRGeometryH, tolerance float64) bool {
+	if a == nil || b == nil {
+		return a == nil && b == nil
+	}
+	if tolerance <= 0 {
+		return C.OGR_G_Equals(a, b) != 0
+	}
+
+	pa, pb := collectGeometryPoints(a), collectGeometryPoints(b)
+	if len(pa) == len(pb) &&
+		C.OGR_GT_Flatten(C.OGR_G_GetGeometryType(a)) == C.OGR_GT_Flatten(C.OGR_G_GetGeometryType(b)) {
+		same := true
+		for i := range pa {
+			if math.Hypot(pa[i][0]-pb[i][0], pa[i][1]-pb[i][1]) > tolerance {
+				same = false
+				break
+			}
+		}
+		if same {
+			return true
+		}
+	}
+
+	bufferA := C.OGR_G_Buffer(a, C.double(tolerance), 8)
+	bufferB := C.OGR_G_Buffer(b, C.double(tolerance), 8)
+	defer C.OGR_G_DestroyGeometry(bufferA)
+	defer C.OGR_G_DestroyGeometry(bufferB)
+	return C.OGR_G_Within(b, bufferA) != 0 && C.OGR_G_Within(a, bufferB) != 0
+}
+
+// matchByGeometry 无关键字段时按图形为新要素寻找对应的旧要素：
+// 优先选择容差内相同的要素，其次对面要素选择重叠比例最大且达到阈值的要素。
+// 先用外接矩形网格索引筛选候选要素，只对外接矩形相交的要素做精确比较
+func matchByGeometry(geometry C.OGRGeometryH, oldFeatures []*diffFeature, index *diffEnvelopeIndex, opts *LayerDiffOptions) *diffFeature {
+	if geometry == nil {
+		return nil
+	}
+
+	var env C.OGREnvelope
+	C.OGR_G_GetEnvelope(geometry, &env)
+	tol := opts.Tolerance
+	isArea := C.OGR_G_Area(geometry) > 0
+
+	var best *diffFeature
+	bestRatio := 0.0
+	for _, position := range index.candidates(env, tol) {
+		old := oldFeatures[position]
+		if old.matched || old.geometry == nil {
+			continue
+		}
+		oldEnv := old.envelope
+		if float64(oldEnv.MinX) > float64(env.MaxX)+tol || float64(oldEnv.MaxX) < float64(env.MinX)-tol ||
+			float64(oldEnv.MinY) > float64(env.MaxY)+tol || float64(oldEnv.MaxY) < float64(env.MinY)-tol {
+			continue
+		}
+		if geometriesEqual(old.geometry, geometry, tol) {
+			return old
+		}
+		if !isArea {
+			continue
+		}
+
+		intersection := C.OGR_G_Intersection(old.geometry, geometry)
+		if intersection == nil {
+			continue
+		}
+		overlap := float64(C.OGR_G_Area(intersection))
+		C.OGR_G_DestroyGeometry(intersection)
+		ratio := overlap / math.Max(float64(C.OGR_G_Area(old.geometry)), float64(C.OGR_G_Area(geometry)))
+		if ratio >= opts.MatchOverlapRatio && ratio > bestRatio {
+			best, bestRatio = old, ratio
+		}
+	}
+	return best
+}
+
+// diffIndexMaxCells 单个要素最多登记的网格数，超过时作为大要素单独存放
+const diffIndexMaxCells = 256
+
+// diffEnvelopeIndex 旧要素外接矩形的均匀网格索引
+// 网格边长取外接矩形的平均边长，大要素每次查询都作为候选
+type diffEnvelopeIndex struct {
+	minX, minY float64
+	cellSize   float64
+	cells      map[[2]int][]int // 网格 -> 旧要素在oldFeatures中的位置
+	oversized  []int
+}
+
+func newDiffEnvelopeIndex(features []*diffFeature) *diffEnvelopeIndex {
+	index := &diffEnvelopeIndex{cells: make(map[[2]int][]int)}
+
+	count := 0
+	sumSize := 0.0
+	index.minX, index.minY = math.Inf(1), math.Inf(1)
+	for _, f := range features {
+		if f.geometry == nil {
+			continue
+		}
+		count++
+		sumSize += float64(f.envelope.MaxX-f.envelope.MinX) + float64(f.envelope.MaxY-f.envelope.MinY)
+		index.minX = math.Min(index.minX, float64(f.envelope.MinX))
+		index.minY = math.Min(index.minY, float64(f.envelope.MinY))
+	}
+	if count == 0 {
+		return index
+	}
+	index.cellSize = sumSize / float64(2*count)
+	if index.cellSize <= 0 {
+		index.cellSize = 1 // 全部为点要素时的网格边长
+	}
+
+	for position, f := range features {
+		if f.geometry == nil {
+			continue
+		}
+		x0, y0, x1, y1 := index.cellRange(f.envelope, 0)
+		if (x1-x0+1)*(y1-y0+1) > diffIndexMaxCells {
+			index.oversized = append(index.oversized, position)
+			continue
+		}
+		for x := x0; x <= x1; x++ {
+			for y := y0; y <= y1; y++ {
+				cell := [2]int{x, y}
+				index.cells[cell] = append(index.cells[cell], position)
+			}
+		}
+	}
+	return index
+}
+
+// cellRange 外接矩形外扩tol后覆盖的网格范围
+func (index *diffEnvelopeIndex) cellRange(env C.OGREnvelope, tol float64) (x0, y0, x1, y1 int) {
+	cell := func(value, origin float64) int {
+		return int(math.Floor((value - origin) / index.cellSize))
+	}
+	return cell(float64(env.MinX)-tol, index.minX), cell(float64(env.MinY)-tol, index.minY),
+		cell(float64(env.MaxX)+tol, index.minX), cell(float64(env.MaxY)+tol, index.minY)
+}
+
+// candidates 返回外接矩形可能相交的旧要素位置，按旧图层读取顺序排列
+func (index *diffEnvelopeIndex) candidates(env C.OGREnvelope, tol float64) []int {
+	if index.cellSize <= 0 {
+		return nil
+	}
+	seen := make(map[int]bool)
+	result := append([]int(nil), index.oversized...)
+	add := func(positions []int) {
+		for _, position := range positions {
+			if !seen[position] {
+				seen[position] = true
+				result = append(result, position)
+			}
+		}
+	}
+
+	x0, y0, x1, y1 := index.cellRange(env, tol)
+	if (x1-x0+1)*(y1-y0+1) > len(index.cells) {
+		// 查询范围覆盖的网格多于已登记的网格时直接遍历已登记的网格
+		for cell, positions := range index.cells {
+			if cell[0] >= x0 && cell[0] <= x1 && cell[1] >= y0 && cell[1] <= y1 {
+				add(positions)
+			}
+		}
+	} else {
+		for x := x0; x <= x1; x++ {
+			for y := y0; y <= y1; y++ {
+				add(index.cells[[2]int{x, y}])
+			}
+		}
+	}
+	sort.Ints(result)
+	return result
+}
+
+// newDiffResultLayer 创建变化图层，变化信息字段名避开新旧图层已有的字段名
+func newDiffResultLayer(newDefn, oldDefn C.OGRFeatureDefnH, srs C.OGRSpatialReferenceH) (*GDALLayer, LayerDiffFieldNames, diffFieldIndices, error) {
+	existing := make(map[string]bool)
+	for _, defn := range []C.OGRFeatureDefnH{newDefn, oldDefn} {
+		for i := 0; i < int(C.OGR_FD_GetFieldCount(defn)); i++ {
+			existing[strings.ToUpper(C.GoString(C.OGR_Fld_GetNameRef(C.OGR_FD_GetFieldDefn(defn, C.int(i)))))] = true
+		}
+	}
+	uniqueName := func(base string) string {
+		name := base
+		if existing[name] {
+			name = "DIFF_" + base
+		}
+		for n := 2; existing[name]; n++ {
+			name = fmt.Sprintf("DIFF_%s_%d", base, n)
+		}
+		existing[name] = true
+		return name
+	}
+	names := LayerDiffFieldNames{
+		Change:        uniqueName("CHANGE"),
+		Key:           uniqueName("CHG_KEY"),
+		OldFID:        uniqueName("OLD_FID"),
+		NewFID:        uniqueName("NEW_FID"),
+		ChangedFields: uniqueName("CHG_FIELDS"),
+	}
+
+	resultLayer, err := newMemoryResultLayer("diff_result", "changes", srs, C.OGR_FD_GetGeomType(newDefn))
+	if err != nil {
+		return nil, names, diffFieldIndices{}, err
+	}
+	addLayerField(resultLayer, names.Change, C.OFTString)
+	addLayerField(resultLayer, names.Key, C.OFTString)
+	addLayerField(resultLayer, names.OldFID, C.OFTInteger64)
+	addLayerField(resultLayer, names.NewFID, C.OFTInteger64)
+	addLayerField(resultLayer, names.ChangedFields, C.OFTString)
+	copyLayerFieldDefns(newDefn, resultLayer.layer)
+
+	resultDefn := resultLayer.GetLayerDefn()
+	indices := diffFieldIndices{
+		change:        C.int(layerFieldIndex(resultDefn, names.Change)),
+		key:           C.int(layerFieldIndex(resultDefn, names.Key)),
+		oldFID:        C.int(layerFieldIndex(resultDefn, names.OldFID)),
+		newFID:        C.int(layerFieldIndex(resultDefn, names.NewFID)),
+		changedFields: C.int(layerFieldIndex(resultDefn, names.ChangedFields)),
+	}
+	if indices.change < 0 || indices.key < 0 || indices.oldFID < 0 || indices.newFID < 0 || indices.changedFields < 0 {
+		resultLayer.Close()
+		return nil, names, indices, fmt.Errorf("创建变化信息字段失败")
+	}
+	return resultLayer, names, indices, nil
+}
+
+// writeDiffFeature 写入一条变化记录，source为新要素（删除时为旧要素）
+func writeDiffFeature(resultLayer *GDALLayer, fields diffFieldIndices, change FeatureChange, source C.OGRFeatureH, sourceDefn C.OGRFeatureDefnH) {
+	resultDefn := resultLayer.GetLayerDefn()
+	feature := C.OGR_F_Create(resultDefn)
+	defer C.OGR_F_Destroy(feature)
+
+	if geometry := C.OGR_F_GetGeometryRef(source); geometry != nil {
+		C.OGR_F_SetGeometry(feature, geometry)
+	}
+	copyFeatureAttributes(source, feature, sourceDefn, resultDefn)
+
+	for _, field := range []struct {
+		index C.int
+		value string
+	}{
+		{fields.change, string(change.Type)},
+		{fields.key, change.Key},
+		{fields.changedFields, strings.Join(change.ChangedFields, ",")},
+	} {
+		if field.value == "" {
+			continue
+		}
+		cValue := C.CString(field.value)
+		C.OGR_F_SetFieldString(feature, field.index, cValue)
+		C.free(unsafe.Pointer(cValue))
+	}
+	if change.OldFID >= 0 {
+		C.OGR_F_SetFieldInteger64(feature, fields.oldFID, C.GIntBig(change.OldFID))
+	}
+	if change.NewFID >= 0 {
+		C.OGR_F_SetFieldInteger64(feature, fields.newFID, C.GIntBig(change.NewFID))
+	}
+	C.OGR_L_CreateFeature(resultLayer.layer, feature)
+}
@@ -0,0 +1,802 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+/*
+#include "osgeo_utils.h"
+*/
+import "C"
+import (
+	"fmt"
+	"math"
+	"sort"
+	"unsafe"
+)
+
+// ============================================================================
+// 线性参考与动态分段
+// ============================================================================
+
+// MeasurePoint 带M值的二维点
+type MeasurePoint struct {
+	X float64
+	Y float64
+	M float64
+}
+
+// Route 路径：由一个或多个带M值的部件组成
+type Route struct {
+	ID    string
+	Parts [][]MeasurePoint
+}
+
+// 事件定位结果
+const (
+	LocErrorNone          = "NO ERROR"
+	LocErrorRouteNotFound = "ROUTE NOT FOUND"
+	LocErrorMeasure       = "ROUTE MEASURE NOT FOUND"
+	LocErrorPartialMatch  = "PARTIAL MATCH"
+)
+
+// LoadRoutes 从路径图层读取路径，几何体不带M值时按长度从0开始赋值
+// 同一路径的多个部件（多部件几何或多个要素）按读取顺序累计M值，各部件的M值不重复。
+func LoadRoutes(routeLayer *GDALLayer, routeIDField string) (map[string]*Route, error) {
+	if routeLayer == nil || routeLayer.layer == nil {
+		return nil, fmt.Errorf("路径图层为空")
+	}
+	idIndex := layerFieldIndex(routeLayer.GetLayerDefn(), routeIDField)
+	if idIndex < 0 {
+		return nil, fmt.Errorf("路径标识字段不存在: %s", routeIDField)
+	}
+
+	routes := make(map[string]*Route)
+	routeLayer.ResetReading()
+	defer routeLayer.ResetReading()
+	for {
+		feature := routeLayer.GetNextFeatureRow()
+		if feature == nil {
+			break
+		}
+		geometry := C.OGR_F_GetGeometryRef(feature)
+		if geometry != nil {
+			id := C.GoString(C.OGR_F_GetFieldAsString(feature, C.int(idIndex)))
+			route, ok := routes[id]
+			if !ok {
+				route = &Route{ID: id}
+				routes[id] = route
+			}
+			startM := 0.0
+			if n := len(route.Parts); n > 0 {
+				last := route.Parts[n-1]
+				startM = last[len(last)-1].M
+			}
+			route.Parts = append(route.Parts, readMeasureParts(geometry, startM)...)
+		}
+		C.OGR_F_Destroy(feature)
+	}
+	return routes, nil
+}
+
+// readMeasureParts 读取线几何体的各部件
+// 带M值的几何体使用原M值；否则按长度赋值，从startM开始并在部件间累计。
+func readMeasureParts(geometry C.OGRGeometryH, startM float64) [][]MeasurePoint {
+	var parts [][]MeasurePoint
+	switch C.OGR_GT_Flatten(C.OGR_G_GetGeometryType(geometry)) {
+	case C.wkbLineString:
+		measured := C.OGR_G_IsMeasured(geometry) != 0
+		count := int(C.OGR_G_GetPointCount(geometry))
+		part := make([]MeasurePoint, count)
+		length := 0.0
+		for i := 0; i < count; i++ {
+			var x, y, z, m C.double
+			C.OGR_G_GetPointZM(geometry, C.int(i), &x, &y, &z, &m)
+			part[i] = MeasurePoint{X: float64(x), Y: float64(y), M: float64(m)}
+			if i > 0 {
+				length += math.Hypot(part[i].X-part[i-1].X, part[i].Y-part[i-1].Y)
+			}
+			if !measured {
+				part[i].M = startM + length
+			}
+		}
+		if count >= 2 {
+			parts = append(parts, part)
+		}
+	case C.wkbMultiLineString, C.wkbGeometryCollection:
+		for i := 0; i < int(C.OGR_G_GetGeometryCount(geometry)); i++ {
+			childParts := readMeasureParts(C.OGR_G_GetGeometryRef(geometry, C.int(i)), startM)
+			if n := len(childParts); n > 0 {
+				last := childParts[n-1]
+				startM = last[len(last)-1].M
+			}
+			parts = append(parts, childParts...)
+		}
+	}
+	return parts
+}
+
+// MeasureRange 路径的M值范围
+func (r *Route) MeasureRange() (float64, float64) {
+	minM, maxM := math.Inf(1), math.Inf(-1)
+	for _, part := range r.Parts {
+		for _, p := range part {
+			minM, maxM = math.Min(minM, p.M), math.Max(maxM, p.M)
+		}
+	}
+	return minM, maxM
+}
+
+// PointAtMeasure 返回路径上M值所在的位置及该处的前进方向（单位向量）
+func (r *Route) PointAtMeasure(measure float64) (x, y, dirX, dirY float64, ok bool) {
+	for _, part := range r.Parts {
+		for i := 1; i < len(part); i++ {
+			a, b := part[i-1], part[i]
+			lo, hi := math.Min(a.M, b.M), math.Max(a.M, b.M)
+			if measure < lo || measure > hi {
+				continue
+			}
+			t := 0.0
+			if b.M != a.M {
+				t = (measure - a.M) / (b.M - a.M)
+			}
+			length := math.Hypot(b.X-a.X, b.Y-a.Y)
+			if length > 0 {
+				dirX, dirY = (b.X-a.X)/length, (b.Y-a.Y)/length
+			}
+			return a.X + t*(b.X-a.X), a.Y + t*(b.Y-a.Y), dirX, dirY, true
+		}
+	}
+	return 0, 0, 0, 0, false
+}
+
+// SubRoute 提取两个M值之间的路段，每个连续片段为一条线
+func (r *Route) SubRoute(fromMeasure, toMeasure float64) [][]MeasurePoint {
+	lo, hi := math.Min(fromMeasure, toMeasure), math.Max(fromMeasure, toMeasure)
+	interpolate := func(a, b MeasurePoint, m float64) MeasurePoint {
+		t := (m - a.M) / (b.M - a.M)
+		return MeasurePoint{X: a.X + t*(b.X-a.X), Y: a.Y + t*(b.Y-a.Y), M: m}
+	}
+
+	var pieces [][]MeasurePoint
+	for _, part := range r.Parts {
+		var current []MeasurePoint
+		for i := 1; i < len(part); i++ {
+			a, b := part[i-1], part[i]
+			segLo, segHi := math.Min(a.M, b.M), math.Max(a.M, b.M)
+			if segHi < lo || segLo > hi || (segLo == segHi && (segLo < lo || segLo > hi)) {
+				if len(current) >= 2 {
+					pieces = append(pieces, current)
+				}
+				current = nil
+				continue
+			}
+
+			start, end := a, b
+			if a.M != b.M {
+				if a.M < lo || a.M > hi {
+					start = interpolate(a, b, math.Max(lo, math.Min(hi, a.M)))
+				}
+				if b.M < lo || b.M > hi {
+					end = interpolate(a, b, math.Max(lo, math.Min(hi, b.M)))
+				}
+			}
+			if len(current) == 0 {
+				current = append(current, start)
+			}
+			current = append(current, end)
+			// 终点被截断说明路段在此结束
+			if end != b {
+				if len(current) >= 2 {
+					pieces = append(pieces, current)
+				}
+				current = nil
+			}
+		}
+		if len(current) >= 2 {
+			pieces = append(pieces, current)
+		}
+	}
+	return pieces
+}
+
+// Locate 将点投影到路径上，返回M值与带符号的偏移距离（右侧为正）
+func (r *Route) Locate(x, y float64) (measure, offset float64, ok bool) {
+	_, _, measure, offset, ok = r.Project(x, y)
+	return measure, offset, ok
+}
+
+// Project 将点投影到路径上，返回投影点坐标、M值与带符号的偏移距离（右侧为正）
+func (r *Route) Project(x, y float64) (px, py, measure, offset float64, ok bool) {
+	best := math.Inf(1)
+	for _, part := range r.Parts {
+		for i := 1; i < len(part); i++ {
+			a, b := part[i-1], part[i]
+			dx, dy := b.X-a.X, b.Y-a.Y
+			lenSq := dx*dx + dy*dy
+			t := 0.0
+			if lenSq > 0 {
+				t = math.Max(0, math.Min(1, ((x-a.X)*dx+(y-a.Y)*dy)/lenSq))
+			}
+			projX, projY := a.X+t*dx, a.Y+t*dy
+			d := math.Hypot(x-projX, y-projY)
+			if d < best {
+				best = d
+				px, py = projX, projY
+				measure = a.M + t*(b.M-a.M)
+				offset = d
+				if dx*(y-a.Y)-dy*(x-a.X) > 0 {
+					offset = -d
+				}
+				ok = true
+			}
+		}
+	}
+	return px, py, measure, offset, ok
+}
+
+// ============================================================================
+// 路径M值校准
+// ============================================================================
+
+// RouteCalibrationOptions 路径M值校准选项
+type RouteCalibrationOptions struct {
+	RouteIDField     string  // 路径标识字段（必填），同一标识的线合并为一条路径
+	FromMeasureField string  // 起始M值字段（可选），与ToMeasureField同时设置时按要素内长度线性内插
+	ToMeasureField   string  // 终止M值字段（可选）
+	StartMeasure     float64 // 按长度赋值时的起始M值
+	MeasureFactor    float64 // 长度到M值的换算系数，默认1（如0.001表示按公里）
+
+	ControlPoints       *GDALLayer // 控制点图层（可选），用于按已知里程校准
+	ControlRouteField   string     // 控制点的路径标识字段，默认与RouteIDField相同
+	ControlMeasureField string     // 控制点的M值字段
+	SearchRadius        float64    // 控制点到路径的最大距离，0表示不限制
+}
+
+// CalibrateRoutes 根据线图层生成带M值的路径图层（MultiLineStringM）
+// M值来源优先级：控制点 > 起止M值字段 > 累计长度。控制点校准时，
+// 控制点之间按长度线性内插，首尾控制点之外按相邻两个控制点的比例外推。
+func CalibrateRoutes(lineLayer *GDALLayer, options *RouteCalibrationOptions) (*GDALLayer, error) {
+	if lineLayer == nil || lineLayer.layer == nil {
+		return nil, fmt.Errorf("线图层为空")
+	}
+	if options == nil || options.RouteIDField == "" {
+		return nil, fmt.Errorf("必须指定路径标识字段")
+	}
+	factor := options.MeasureFactor
+	if factor == 0 {
+		factor = 1
+	}
+
+	defn := lineLayer.GetLayerDefn()
+	idIndex := layerFieldIndex(defn, options.RouteIDField)
+	if idIndex < 0 {
+		return nil, fmt.Errorf("路径标识字段不存在: %s", options.RouteIDField)
+	}
+	fromIndex, toIndex := -1, -1
+	if options.FromMeasureField != "" && options.ToMeasureField != "" {
+		fromIndex = layerFieldIndex(defn, options.FromMeasureField)
+		toIndex = layerFieldIndex(defn, options.ToMeasureField)
+		if fromIndex < 0 || toIndex < 0 {
+			return nil, fmt.Errorf("M值字段不存在: %s/%s", options.FromMeasureField, options.ToMeasureField)
+		}
+	}
+
+	routes := make(map[string]*Route)
+	cumulative := make(map[string]float64)
+	var order []string
+
+	lineLayer.ResetReading()
+	for {
+		feature := lineLayer.GetNextFeatureRow()
+		if feature == nil {
+			break
+		}
+		geometry := C.OGR_F_GetGeometryRef(feature)
+		if geometry == nil {
+			C.OGR_F_Destroy(feature)
+			continue
+		}
+		id := C.GoString(C.OGR_F_GetFieldAsString(feature, C.int(idIndex)))
+		route, ok := routes[id]
+		if !ok {
+			route = &Route{ID: id}
+			routes[id] = route
+			order = append(order, id)
+		}
+
+		// readMeasureParts对无M值的几何体按部件长度赋值，这里统一按长度重新计算
+		parts := readMeasureParts(geometry, 0)
+		featureLength := 0.0
+		for _, part := range parts {
+			for i := range part {
+				if i > 0 {
+					featureLength += math.Hypot(part[i].X-part[i-1].X, part[i].Y-part[i-1].Y)
+				}
+			}
+		}
+
+		startM := options.StartMeasure + cumulative[id]*factor
+		scale := factor
+		if fromIndex >= 0 {
+			fromM := float64(C.OGR_F_GetFieldAsDouble(feature, C.int(fromIndex)))
+			toM := float64(C.OGR_F_GetFieldAsDouble(feature, C.int(toIndex)))
+			startM = fromM
+			scale = 0
+			if featureLength > 0 {
+				scale = (toM - fromM) / featureLength
+			}
+		}
+
+		length := 0.0
+		for _, part := range parts {
+			for i := range part {
+				if i > 0 {
+					length += math.Hypot(part[i].X-part[i-1].X, part[i].Y-part[i-1].Y)
+				}
+				part[i].M = startM + length*scale
+			}
+			route.Parts = append(route.Parts, part)
+		}
+		cumulative[id] += featureLength
+		C.OGR_F_Destroy(feature)
+	}
+	lineLayer.ResetReading()
+
+	if options.ControlPoints != nil {
+		if err := calibrateRoutesByPoints(routes, options); err != nil {
+			return nil, err
+		}
+	}
+
+	return createRouteLayer(routes, order, lineLayer.GetSpatialRef(), C.OGR_FD_GetFieldDefn(defn, C.int(idIndex)))
+}
+
+// calibrateRoutesByPoints 使用控制点重新计算各路径的M值
+func calibrateRoutesByPoints(routes map[string]*Route, options *RouteCalibrationOptions) error {
+	routeField := options.ControlRouteField
+	if routeField == "" {
+		routeField = options.RouteIDField
+	}
+	pointDefn := options.ControlPoints.GetLayerDefn()
+	routeIndex := layerFieldIndex(pointDefn, routeField)
+	measureIndex := layerFieldIndex(pointDefn, options.ControlMeasureField)
+	if routeIndex < 0 || measureIndex < 0 {
+		return fmt.Errorf("控制点字段不存在: %s/%s", routeField, options.ControlMeasureField)
+	}
+
+	type control struct{ distance, measure float64 }
+	controls := make(map[string][]control)
+
+	// 先将路径M值替换为沿线累计距离，便于控制点定位
+	for _, route := range routes {
+		length := 0.0
+		for _, part := range route.Parts {
+			for i := range part {
+				if i > 0 {
+					length += math.Hypot(part[i].X-part[i-1].X, part[i].Y-part[i-1].Y)
+				}
+				part[i].M = length
+			}
+		}
+	}
+
+	options.ControlPoints.ResetReading()
+	for {
+		feature := options.ControlPoints.GetNextFeatureRow()
+		if feature == nil {
+			break
+		}
+		geometry := C.OGR_F_GetGeometryRef(feature)
+		id := C.GoString(C.OGR_F_GetFieldAsString(feature, C.int(routeIndex)))
+		if route, ok := routes[id]; ok && geometry != nil {
+			distance, offset, located := route.Locate(float64(C.OGR_G_GetX(geometry, 0)), float64(C.OGR_G_GetY(geometry, 0)))
+			if located && (options.SearchRadius <= 0 || math.Abs(offset) <= options.SearchRadius) {
+				controls[id] = append(controls[id], control{distance, float64(C.OGR_F_GetFieldAsDouble(feature, C.int(measureIndex)))})
+			}
+		}
+		C.OGR_F_Destroy(feature)
+	}
+	options.ControlPoints.ResetReading()
+
+	for id, route := range routes {
+		list := controls[id]
+		sort.Slice(list, func(i, j int) bool { return list[i].distance < list[j].distance })
+		for _, part := range route.Parts {
+			for i := range part {
+				d := part[i].M
+				switch {
+				case len(list) == 0:
+					// 无控制点的路径按长度赋值
+					part[i].M = options.StartMeasure + d*nonZero(options.MeasureFactor)
+				case len(list) == 1:
+					part[i].M = list[0].measure + (d-list[0].distance)*nonZero(options.MeasureFactor)
+				default:
+					k := sort.Search(len(list), func(k int) bool { return list[k].distance >= d })
+					if k == 0 {
+						k = 1
+					} else if k == len(list) {
+						k = len(list) - 1
+					}
+					a, b := list[k-1], list[k]
+					if b.distance == a.distance {
+						part[i].M = a.measure
+					} else {
+						part[i].M = a.measure + (d-a.distance)*(b.measure-a.measure)/(b.distance-a.distance)
+					}
+				}
+			}
+		}
+	}
+	return nil
+}
+
+func nonZero(v float64) float64 {
+	if v == 0 {
+		return 1
+	}
+	return v
+}
+
+// measureLine 由带M值的点序列构建LineStringM
+func measureLine(points []MeasurePoint) C.OGRGeometryH {
+	line := C.OGR_G_CreateGeometry(C.wkbLineStringM)
+	for _, p := range points {
+		C.OGR_G_AddPointM(line, C.double(p.X), C.double(p.Y), C.double(p.M))
+	}
+	return line
+}
+
+// createRouteLayer 输出路径图层
+func createRouteLayer(routes map[string]*Route, order []string, srs C.OGRSpatialReferenceH, idFieldDefn C.OGRFieldDefnH) (*GDALLayer, error) {
+	resultLayer, err := newMemoryResultLayer("route_result", "routes", srs, C.wkbMultiLineStringM)
+	if err != nil {
+		return nil, err
+	}
+	C.OGR_L_CreateField(resultLayer.layer, idFieldDefn, C.int(1))
+	defn := resultLayer.GetLayerDefn()
+
+	for _, id := range order {
+		route := routes[id]
+		geometry := C.OGR_G_CreateGeometry(C.wkbMultiLineStringM)
+		for _, part := range route.Parts {
+			C.OGR_G_AddGeometryDirectly(geometry, measureLine(part))
+		}
+		feature := C.OGR_F_Create(defn)
+		C.OGR_F_SetGeometryDirectly(feature, geometry)
+		cID := C.CString(id)
+		C.OGR_F_SetFieldString(feature, 0, cID)
+		C.free(unsafe.Pointer(cID))
+		C.OGR_L_CreateFeature(resultLayer.layer, feature)
+		C.OGR_F_Destroy(feature)
+	}
+	return resultLayer, nil
+}
+
+// ============================================================================
+// 沿路径定位要素
+// ============================================================================
+
+// LocateOptions 沿路径定位选项
+type LocateOptions struct {
+	RouteIDField string  // 路径图层的标识字段
+	SearchRadius float64 // 搜索半径，0表示不限制
+}
+
+// addLocateField 向结果图层添加定位信息字段并返回其索引
+// 与已复制的源字段重名时改用 LR_<base>、LR_<base>_2…，避免覆盖源字段的值
+func addLocateField(resultLayer *GDALLayer, base string, fieldType C.OGRFieldType) C.int {
+	defn := resultLayer.GetLayerDefn()
+	name := base
+	if layerFieldIndex(defn, name) >= 0 {
+		name = "LR_" + base
+	}
+	for n := 2; layerFieldIndex(defn, name) >= 0; n++ {
+		name = fmt.Sprintf("LR_%s_%d", base, n)
+	}
+	addLayerField(resultLayer, name, fieldType)
+	return C.int(layerFieldIndex(resultLayer.GetLayerDefn(), name))
+}
+
+// LocateFeaturesAlongRoutes 沿路径定位点或线要素
+// 点要素输出RID、MEAS、DISTANCE字段（DISTANCE为到路径的带符号距离，右侧为正），几何为路径上的投影点；
+// 线要素以首末点定位，输出RID、FMEAS、TMEAS字段，几何为对应路段。
+// 源要素的全部字段被保留，定位字段与源字段重名时加 LR_ 前缀；未找到路径的要素不输出。
+func LocateFeaturesAlongRoutes(routeLayer, inputLayer *GDALLayer, options *LocateOptions) (*GDALLayer, error) {
+	if inputLayer == nil || inputLayer.layer == nil {
+		return nil, fmt.Errorf("输入图层为空")
+	}
+	if options == nil || options.RouteIDField == "" {
+		return nil, fmt.Errorf("必须指定路径标识字段")
+	}
+	routes, err := LoadRoutes(routeLayer, options.RouteIDField)
+	if err != nil {
+		return nil, err
+	}
+
+	inputDefn := inputLayer.GetLayerDefn()
+	flatType := C.OGR_GT_Flatten(C.OGR_FD_GetGeomType(inputDefn))
+	isLine := flatType == C.wkbLineString || flatType == C.wkbMultiLineString
+	if !isLine && flatType != C.wkbPoint {
+		return nil, fmt.Errorf("只支持点或线图层的定位")
+	}
+
+	resultType := C.OGRwkbGeometryType(C.wkbPoint)
+	if isLine {
+		resultType = C.wkbMultiLineStringM
+	}
+	resultLayer, err := newMemoryResultLayer("locate_result", "located", inputLayer.GetSpatialRef(), resultType)
+	if err != nil {
+		return nil, err
+	}
+	copyLayerFieldDefns(inputDefn, resultLayer.layer)
+	ridIndex := addLocateField(resultLayer, "RID", C.OFTString)
+	var valueIndexes []C.int
+	if isLine {
+		valueIndexes = []C.int{addLocateField(resultLayer, "FMEAS", C.OFTReal), addLocateField(resultLayer, "TMEAS", C.OFTReal)}
+	} else {
+		valueIndexes = []C.int{addLocateField(resultLayer, "MEAS", C.OFTReal), addLocateField(resultLayer, "DISTANCE", C.OFTReal)}
+	}
+	resultDefn := resultLayer.GetLayerDefn()
+
+	// nearestRoute 查找距离点最近且在搜索半径内的路径，返回投影点、M值和偏移距离
+	nearestRoute := func(x, y float64) (best *Route, px, py, m, offset float64) {
+		for _, route := range routes {
+			projX, projY, projM, projOffset, ok := route.Project(x, y)
+			if !ok || (options.SearchRadius > 0 && math.Abs(projOffset) > options.SearchRadius) {
+				continue
+			}
+			if best == nil || math.Abs(projOffset) < math.Abs(offset) {
+				best, px, py, m, offset = route, projX, projY, projM, projOffset
+			}
+		}
+		return best, px, py, m, offset
+	}
+
+	inputLayer.ResetReading()
+	defer inputLayer.ResetReading()
+	for {
+		feature := inputLayer.GetNextFeatureRow()
+		if feature == nil {
+			break
+		}
+		geometry := C.OGR_F_GetGeometryRef(feature)
+		if geometry == nil || C.OGR_G_IsEmpty(geometry) != 0 {
+			C.OGR_F_Destroy(feature)
+			continue
+		}
+
+		var outGeom C.OGRGeometryH
+		var route *Route
+		var values []float64
+		if isLine {
+			parts := readMeasureParts(geometry, 0)
+			if len(parts) == 0 {
+				C.OGR_F_Destroy(feature)
+				continue
+			}
+			first, last := parts[0][0], parts[len(parts)-1][len(parts[len(parts)-1])-1]
+			var fromM, toM float64
+			route, _, _, fromM, _ = nearestRoute(first.X, first.Y)
+			if route != nil {
+				var ok bool
+				toM, _, ok = route.Locate(last.X, last.Y)
+				if ok {
+					outGeom = C.OGR_G_CreateGeometry(C.wkbMultiLineStringM)
+					for _, piece := range route.SubRoute(fromM, toM) {
+						C.OGR_G_AddGeometryDirectly(outGeom, measureLine(piece))
+					}
+					values = []float64{fromM, toM}
+				}
+			}
+		} else {
+			x, y := float64(C.OGR_G_GetX(geometry, 0)), float64(C.OGR_G_GetY(geometry, 0))
+			var px, py, m, offset float64
+			route, px, py, m, offset = nearestRoute(x, y)
+			if route != nil {
+				outGeom = C.OGR_G_CreateGeometry(C.wkbPoint)
+				C.OGR_G_SetPoint_2D(outGeom, 0, C.double(px), C.double(py))
+				values = []float64{m, offset}
+			}
+		}
+
+		if outGeom != nil {
+			newFeature := C.OGR_F_Create(resultDefn)
+			C.OGR_F_SetGeometryDirectly(newFeature, outGeom)
+			copyFeatureAttributes(feature, newFeature, inputDefn, resultDefn)
+			cRID := C.CString(route.ID)
+			C.OGR_F_SetFieldString(newFeature, ridIndex, cRID)
+			C.free(unsafe.Pointer(cRID))
+			for i, v := range values {
+				C.OGR_F_SetFieldDouble(newFeature, valueIndexes[i], C.double(v))
+			}
+			C.OGR_L_CreateFeature(resultLayer.layer, newFeature)
+			C.OGR_F_Destroy(newFeature)
+		}
+		C.OGR_F_Destroy(feature)
+	}
+
+	return resultLayer, nil
+}
+
+// ============================================================================
+// 路径事件（动态分段）
+// ============================================================================
+
+// RouteEventOptions 路径事件选项
+type RouteEventOptions struct {
+	RouteIDField     string // 路径图层的标识字段
+	EventRouteField  string // 事件表的路径标识字段，默认与RouteIDField相同
+	MeasureField     string // 点事件的M值字段
+	FromMeasureField string // 线事件的起始M值字段
+	ToMeasureField   string // 线事件的终止M值字段
+	OffsetField      string // 偏移字段（可选），正值偏向路径前进方向右侧
+}
+
+// MakeRouteEventLayer 根据事件表（路径标识+M值）生成点或线事件图层
+// 设置MeasureField时生成点事件，否则根据FromMeasureField/ToMeasureField生成线事件。
+// 事件表可以是无几何的属性表；输出保留事件表全部字段，并附加LOC_ERROR字段记录定位状态（重名时加 LR_ 前缀）。
+func MakeRouteEventLayer(routeLayer, eventTable *GDALLayer, options *RouteEventOptions) (*GDALLayer, error) {
+	if eventTable == nil || eventTable.layer == nil {
+		return nil, fmt.Errorf("事件表为空")
+	}
+	if options == nil || options.RouteIDField == "" {
+		return nil, fmt.Errorf("必须指定路径标识字段")
+	}
+	routes, err := LoadRoutes(routeLayer, options.RouteIDField)
+	if err != nil {
+		return nil, err
+	}
+
+	eventDefn := eventTable.GetLayerDefn()
+	eventRouteField := options.EventRouteField
+	if eventRouteField == "" {
+		eventRouteField = options.RouteIDField
+	}
+	routeIndex := layerFieldIndex(eventDefn, eventRouteField)
+	if routeIndex < 0 {
+		return nil, fmt.Errorf("事件表路径标识字段不存在: %s", eventRouteField)
+	}
+
+	isPoint := options.MeasureField != ""
+	var measureIndex, fromIndex, toIndex int
+	if isPoint {
+		measureIndex = layerFieldIndex(eventDefn, options.MeasureField)
+		if measureIndex < 0 {
+			return nil, fmt.Errorf("M值字段不存在: %s", options.MeasureField)
+		}
+	} else {
+		fromIndex = layerFieldIndex(eventDefn, options.FromMeasureField)
+		toIndex = layerFieldIndex(eventDefn, options.ToMeasureField)
+		if fromIndex < 0 || toIndex < 0 {
+			return nil, fmt.Errorf("M值字段不存在: %s/%s", options.FromMeasureField, options.ToMeasureField)
+		}
+	}
+	offsetIndex := -1
+	if options.OffsetField != "" {
+		offsetIndex = layerFieldIndex(eventDefn, options.OffsetField)
+		if offsetIndex < 0 {
+			return nil, fmt.Errorf("偏移字段不存在: %s", options.OffsetField)
+		}
+	}
+
+	resultType := C.OGRwkbGeometryType(C.wkbPoint)
+	if !isPoint {
+		resultType = C.wkbMultiLineStringM
+	}
+	resultLayer, err := newMemoryResultLayer("route_event_result", "route_events", routeLayer.GetSpatialRef(), resultType)
+	if err != nil {
+		return nil, err
+	}
+	copyLayerFieldDefns(eventDefn, resultLayer.layer)
+	errorIndex := addLocateField(resultLayer, "LOC_ERROR", C.OFTString)
+	resultDefn := resultLayer.GetLayerDefn()
+
+	eventTable.ResetReading()
+	defer eventTable.ResetReading()
+	for {
+		event := eventTable.GetNextFeatureRow()
+		if event == nil {
+			break
+		}
+
+		id := C.GoString(C.OGR_F_GetFieldAsString(event, C.int(routeIndex)))
+		offset := 0.0
+		if offsetIndex >= 0 {
+			offset = float64(C.OGR_F_GetFieldAsDouble(event, C.int(offsetIndex)))
+		}
+
+		var geometry C.OGRGeometryH
+		status := LocErrorNone
+		route, ok := routes[id]
+		switch {
+		case !ok:
+			status = LocErrorRouteNotFound
+		case isPoint:
+			m := float64(C.OGR_F_GetFieldAsDouble(event, C.int(measureIndex)))
+			x, y, dirX, dirY, found := route.PointAtMeasure(m)
+			if !found {
+				status = LocErrorMeasure
+				break
+			}
+			geometry = C.OGR_G_CreateGeometry(C.wkbPoint)
+			C.OGR_G_SetPoint_2D(geometry, 0, C.double(x+offset*dirY), C.double(y-offset*dirX))
+		default:
+			fromM := float64(C.OGR_F_GetFieldAsDouble(event, C.int(fromIndex)))
+			toM := float64(C.OGR_F_GetFieldAsDouble(event, C.int(toIndex)))
+			pieces := route.SubRoute(fromM, toM)
+			if len(pieces) == 0 {
+				status = LocErrorMeasure
+				break
+			}
+			minM, maxM := route.MeasureRange()
+			if math.Min(fromM, toM) < minM || math.Max(fromM, toM) > maxM {
+				status = LocErrorPartialMatch
+			}
+			geometry = C.OGR_G_CreateGeometry(C.wkbMultiLineStringM)
+			for _, piece := range pieces {
+				if offset != 0 {
+					piece = offsetMeasureLine(piece, offset)
+				}
+				C.OGR_G_AddGeometryDirectly(geometry, measureLine(piece))
+			}
+		}
+
+		feature := C.OGR_F_Create(resultDefn)
+		if geometry != nil {
+			C.OGR_F_SetGeometryDirectly(feature, geometry)
+		}
+		copyFeatureAttributes(event, feature, eventDefn, resultDefn)
+		cStatus := C.CString(status)
+		C.OGR_F_SetFieldString(feature, errorIndex, cStatus)
+		C.free(unsafe.Pointer(cStatus))
+		C.OGR_L_CreateFeature(resultLayer.layer, feature)
+		C.OGR_F_Destroy(feature)
+		C.OGR_F_Destroy(event)
+	}
+
+	return resultLayer, nil
+}
+
+// offsetMeasureLine 将线沿法向偏移（正值向右），顶点处使用相邻线段法向的平均方向
+func offsetMeasureLine(points []MeasurePoint, offset float64) []MeasurePoint {
+	n := len(points)
+	normals := make([][2]float64, n-1)
+	for i := 1; i < n; i++ {
+		dx, dy := points[i].X-points[i-1].X, points[i].Y-points[i-1].Y
+		length := math.Hypot(dx, dy)
+		if length > 0 {
+			normals[i-1] = [2]float64{dy / length, -dx / length}
+		}
+	}
+
+	result := make([]MeasurePoint, n)
+	for i, p := range points {
+		var nx, ny float64
+		switch {
+		case i == 0:
+			nx, ny = normals[0][0], normals[0][1]
+		case i == n-1:
+			nx, ny = normals[n-2][0], normals[n-2][1]
+		default:
+			nx, ny = normals[i-1][0]+normals[i][0], normals[i-1][1]+normals[i][1]
+			if length := math.Hypot(nx, ny); length > 0 {
+				nx, ny = nx/length, ny/length
+			}
+		}
+		result[i] = MeasurePoint{X: p.X + offset*nx, Y: p.Y + offset*ny, M: p.M}
+	}
+	return result
+}
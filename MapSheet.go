@@ -0,0 +1,695 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+/*
+#include "osgeo_utils.h"
+*/
+import "C"
+import (
+	"fmt"
+	"math"
+	"runtime"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// ============================================================================
+// 国家基本比例尺地形图分幅与编号（GB/T 13989-2012）
+// ============================================================================
+
+// MapScale 比例尺分母
+type MapScale int
+
+const (
+	Scale1M   MapScale = 1000000
+	Scale500K MapScale = 500000
+	Scale250K MapScale = 250000
+	Scale100K MapScale = 100000
+	Scale50K  MapScale = 50000
+	Scale25K  MapScale = 25000
+	Scale10K  MapScale = 10000
+	Scale5K   MapScale = 5000
+	Scale2K   MapScale = 2000
+	Scale1K   MapScale = 1000
+	Scale500  MapScale = 500
+)
+
+// MapSheetNumberField 图幅图层中的图号字段名
+const MapSheetNumberField = "SHEET_NO"
+
+// graticuleSheetSpec 经纬度分幅的图幅大小（角秒）及比例尺代码
+type graticuleSheetSpec struct {
+	lonSeconds float64
+	latSeconds float64
+	code       byte
+}
+
+var graticuleSheetSpecs = map[MapScale]graticuleSheetSpec{
+	Scale1M:   {21600, 14400, 0},
+	Scale500K: {10800, 7200, 'B'},
+	Scale250K: {5400, 3600, 'C'},
+	Scale100K: {1800, 1200, 'D'},
+	Scale50K:  {900, 600, 'E'},
+	Scale25K:  {450, 300, 'F'},
+	Scale10K:  {225, 150, 'G'},
+	Scale5K:   {112.5, 75, 'H'},
+}
+
+// rectangularSheetSizes 矩形分幅的图幅边长（米），1:5000为40cm×40cm，其余为50cm×50cm
+var rectangularSheetSizes = map[MapScale]float64{
+	Scale5K:  2000,
+	Scale2K:  1000,
+	Scale1K:  500,
+	Scale500: 250,
+}
+
+// MapSheet 图幅
+type MapSheet struct {
+	Number      string   // 图号
+	Scale       MapScale // 比例尺分母
+	Rectangular bool     // 是否为矩形分幅（范围为投影坐标），否则为经纬度分幅
+	MinX        float64  // 经纬度分幅为经度，矩形分幅为横坐标
+	MinY        float64
+	MaxX        float64
+	MaxY        float64
+}
+
+// String 返回图幅的字符串表示
+func (s *MapSheet) String() string {
+	return fmt.Sprintf("%s (1:%d) [%.6f, %.6f, %.6f, %.6f]", s.Number, int(s.Scale), s.MinX, s.MinY, s.MaxX, s.MaxY)
+}
+
+func getGraticuleSheetSpec(scale MapScale) (graticuleSheetSpec, error) {
+	spec, ok := graticuleSheetSpecs[scale]
+	if !ok {
+		return spec, fmt.Errorf("不支持的经纬度分幅比例尺: 1:%d", int(scale))
+	}
+	return spec, nil
+}
+
+// MapSheetNumber 根据经纬度计算所在图幅的新标准图号（如 J50、J50D001001）
+func MapSheetNumber(lon, lat float64, scale MapScale) (string, error) {
+	sheet, err := MapSheetByCoordinate(lon, lat, scale)
+	if err != nil {
+		return "", err
+	}
+	return sheet.Number, nil
+}
+
+// MapSheetByCoordinate 根据经纬度获取所在的经纬度分幅图幅
+func MapSheetByCoordinate(lon, lat float64, scale MapScale) (*MapSheet, error) {
+	spec, err := getGraticuleSheetSpec(scale)
+	if err != nil {
+		return nil, err
+	}
+	if lat < 0 || lat >= 88 || lon < -180 || lon >= 180 {
+		return nil, fmt.Errorf("经纬度(%.6f, %.6f)超出北半球图幅编号范围", lon, lat)
+	}
+
+	lonSec := lon * 3600
+	latSec := lat * 3600
+	col := math.Floor(lonSec/spec.lonSeconds + 1e-9)
+	row := math.Floor(latSec/spec.latSeconds + 1e-9)
+
+	sheet := &MapSheet{
+		Scale: scale,
+		MinX:  col * spec.lonSeconds / 3600,
+		MinY:  row * spec.latSeconds / 3600,
+		MaxX:  (col + 1) * spec.lonSeconds / 3600,
+		MaxY:  (row + 1) * spec.latSeconds / 3600,
+	}
+	sheet.Number = graticuleSheetNumber((sheet.MinX+sheet.MaxX)/2, (sheet.MinY+sheet.MaxY)/2, spec)
+	return sheet, nil
+}
+
+// graticuleSheetNumber 计算图幅内一点（通常为图幅中心）的图号
+func graticuleSheetNumber(lon, lat float64, spec graticuleSheetSpec) string {
+	row1M := int(math.Floor(lat / 4))
+	col1M := int(math.Floor(lon/6)) + 31
+	letter := byte('A' + row1M)
+	if spec.code == 0 {
+		return fmt.Sprintf("%c%02d", letter, col1M)
+	}
+
+	latIn := (lat - float64(row1M)*4) * 3600
+	lonIn := (lon - float64(col1M-31)*6) * 3600
+	rows := int(math.Round(14400 / spec.latSeconds))
+	row := rows - int(math.Floor(latIn/spec.latSeconds))
+	col := int(math.Floor(lonIn/spec.lonSeconds)) + 1
+	return fmt.Sprintf("%c%02d%c%03d%03d", letter, col1M, spec.code, row, col)
+}
+
+// ParseMapSheetNumber 解析新标准图号，返回图幅范围
+func ParseMapSheetNumber(number string) (*MapSheet, error) {
+	number = strings.ToUpper(strings.TrimSpace(number))
+	if len(number) != 3 && len(number) != 10 {
+		return nil, fmt.Errorf("无效的图号: %s", number)
+	}
+
+	letter := number[0]
+	if letter < 'A' || letter > 'V' {
+		return nil, fmt.Errorf("无效的图号行号: %s", number)
+	}
+	col1M, err := strconv.Atoi(number[1:3])
+	if err != nil || col1M < 1 || col1M > 60 {
+		return nil, fmt.Errorf("无效的图号列号: %s", number)
+	}
+	minLon1M := float64(col1M-31) * 6
+	minLat1M := float64(letter-'A') * 4
+
+	if len(number) == 3 {
+		return &MapSheet{
+			Number: number,
+			Scale:  Scale1M,
+			MinX:   minLon1M,
+			MinY:   minLat1M,
+			MaxX:   minLon1M + 6,
+			MaxY:   minLat1M + 4,
+		}, nil
+	}
+
+	var scale MapScale
+	var spec graticuleSheetSpec
+	for s, sp := range graticuleSheetSpecs {
+		if sp.code == number[3] {
+			scale, spec = s, sp
+			break
+		}
+	}
+	if scale == 0 {
+		return nil, fmt.Errorf("无效的比例尺代码: %c", number[3])
+	}
+
+	row, err1 := strconv.Atoi(number[4:7])
+	col, err2 := strconv.Atoi(number[7:10])
+	rows := int(math.Round(14400 / spec.latSeconds))
+	cols := int(math.Round(21600 / spec.lonSeconds))
+	if err1 != nil || err2 != nil || row < 1 || row > rows || col < 1 || col > cols {
+		return nil, fmt.Errorf("无效的图幅行列号: %s", number)
+	}
+
+	minLon := minLon1M + float64(col-1)*spec.lonSeconds/3600
+	maxLat := minLat1M + 4 - float64(row-1)*spec.latSeconds/3600
+	return &MapSheet{
+		Number: number,
+		Scale:  scale,
+		MinX:   minLon,
+		MinY:   maxLat - spec.latSeconds/3600,
+		MaxX:   minLon + spec.lonSeconds/3600,
+		MaxY:   maxLat,
+	}, nil
+}
+
+// MapSheetsInExtent 获取经纬度范围内的全部经纬度分幅图幅
+func MapSheetsInExtent(minLon, minLat, maxLon, maxLat float64, scale MapScale) ([]*MapSheet, error) {
+	spec, err := getGraticuleSheetSpec(scale)
+	if err != nil {
+		return nil, err
+	}
+	if minLon > maxLon || minLat > maxLat {
+		return nil, fmt.Errorf("无效的范围")
+	}
+
+	colStart := int(math.Floor(minLon * 3600 / spec.lonSeconds))
+	colEnd := int(math.Ceil(maxLon*3600/spec.lonSeconds)) - 1
+	rowStart := int(math.Floor(minLat * 3600 / spec.latSeconds))
+	rowEnd := int(math.Ceil(maxLat*3600/spec.latSeconds)) - 1
+	if colEnd < colStart {
+		colEnd = colStart
+	}
+	if rowEnd < rowStart {
+		rowEnd = rowStart
+	}
+	if err := checkSheetCount(colEnd-colStart+1, rowEnd-rowStart+1); err != nil {
+		return nil, err
+	}
+
+	var sheets []*MapSheet
+	for row := rowEnd; row >= rowStart; row-- {
+		for col := colStart; col <= colEnd; col++ {
+			centerLon := (float64(col) + 0.5) * spec.lonSeconds / 3600
+			centerLat := (float64(row) + 0.5) * spec.latSeconds / 3600
+			sheet, err := MapSheetByCoordinate(centerLon, centerLat, scale)
+			if err != nil {
+				return nil, err
+			}
+			sheets = append(sheets, sheet)
+		}
+	}
+	return sheets, nil
+}
+
+// RectangularSheetByCoordinate 根据投影坐标获取所在的矩形分幅图幅
+// x为横坐标（东），y为纵坐标（北）；图号为西南角纵坐标-横坐标（公里）
+func RectangularSheetByCoordinate(x, y float64, scale MapScale) (*MapSheet, error) {
+	size, ok := rectangularSheetSizes[scale]
+	if !ok {
+		return nil, fmt.Errorf("不支持的矩形分幅比例尺: 1:%d", int(scale))
+	}
+
+	minX := math.Floor(x/size+1e-9) * size
+	minY := math.Floor(y/size+1e-9) * size
+	return &MapSheet{
+		Number:      rectangularSheetNumber(minX, minY, scale),
+		Scale:       scale,
+		Rectangular: true,
+		MinX:        minX,
+		MinY:        minY,
+		MaxX:        minX + size,
+		MaxY:        minY + size,
+	}, nil
+}
+
+// rectangularSheetNumber 按西南角坐标生成矩形分幅图号
+func rectangularSheetNumber(minX, minY float64, scale MapScale) string {
+	decimals := 0
+	switch scale {
+	case Scale2K, Scale1K:
+		decimals = 1
+	case Scale500:
+		decimals = 2
+	}
+	return fmt.Sprintf("%.*f-%.*f", decimals, minY/1000, decimals, minX/1000)
+}
+
+// RectangularSheetsInExtent 获取投影坐标范围内的全部矩形分幅图幅
+func RectangularSheetsInExtent(minX, minY, maxX, maxY float64, scale MapScale) ([]*MapSheet, error) {
+	size, ok := rectangularSheetSizes[scale]
+	if !ok {
+		return nil, fmt.Errorf("不支持的矩形分幅比例尺: 1:%d", int(scale))
+	}
+	if minX > maxX || minY > maxY {
+		return nil, fmt.Errorf("无效的范围")
+	}
+
+	colStart := int(math.Floor(minX / size))
+	colEnd := int(math.Ceil(maxX/size)) - 1
+	rowStart := int(math.Floor(minY / size))
+	rowEnd := int(math.Ceil(maxY/size)) - 1
+	if colEnd < colStart {
+		colEnd = colStart
+	}
+	if rowEnd < rowStart {
+		rowEnd = rowStart
+	}
+	if err := checkSheetCount(colEnd-colStart+1, rowEnd-rowStart+1); err != nil {
+		return nil, err
+	}
+
+	var sheets []*MapSheet
+	for row := rowEnd; row >= rowStart; row-- {
+		for col := colStart; col <= colEnd; col++ {
+			sheet, err := RectangularSheetByCoordinate((float64(col)+0.5)*size, (float64(row)+0.5)*size, scale)
+			if err != nil {
+				return nil, err
+			}
+			sheets = append(sheets, sheet)
+		}
+	}
+	return sheets, nil
+}
+
+// checkSheetCount 防止范围过大生成海量图幅
+func checkSheetCount(cols, rows int) error {
+	const maxSheets = 1000000
+	if cols*rows > maxSheets {
+		return fmt.Errorf("范围内图幅数量过多: %d (上限 %d)", cols*rows, maxSheets)
+	}
+	return nil
+}
+
+// ============================================================================
+// 图幅图层生成
+// ============================================================================
+
+// CreateMapSheetLayer 将图幅列表生成为面图层（返回内存图层，包含SHEET_NO、SCALE字段）
+// 经纬度分幅按CGCS2000地理坐标处理，targetSRS非空时加密边界后转换到目标坐标系；
+// 矩形分幅的坐标视为targetSRS下的坐标。
+func CreateMapSheetLayer(sheets []*MapSheet, targetSRS *SpatialReference) (*GDALLayer, error) {
+	defer runtime.KeepAlive(targetSRS)
+	return createMapSheetLayer(sheets, targetSRS.handle())
+}
+
+func createMapSheetLayer(sheets []*MapSheet, targetSRS C.OGRSpatialReferenceH) (*GDALLayer, error) {
+	var transform C.OGRCoordinateTransformationH
+	layerSRS := targetSRS
+
+	hasGraticule := false
+	for _, sheet := range sheets {
+		if !sheet.Rectangular {
+			hasGraticule = true
+			break
+		}
+	}
+
+	if hasGraticule {
+		geogSRS, err := SRS_CGCS2000.ToOGRGDBSpatialReference()
+		if err != nil {
+			return nil, err
+		}
+		defer C.OSRDestroySpatialReference(geogSRS)
+		C.OSRSetAxisMappingStrategy(geogSRS, C.OAMS_TRADITIONAL_GIS_ORDER)
+
+		if targetSRS == nil {
+			layerSRS = geogSRS
+		} else if C.OSRIsSame(geogSRS, targetSRS) == 0 {
+			transform = C.OCTNewCoordinateTransformation(geogSRS, targetSRS)
+			if transform == nil {
+				return nil, fmt.Errorf("无法创建图幅坐标转换器")
+			}
+			defer C.OCTDestroyCoordinateTransformation(transform)
+		}
+	}
+
+	resultLayer, err := newMemoryResultLayer("map_sheets", "map_sheets", layerSRS, C.wkbPolygon)
+	if err != nil {
+		return nil, err
+	}
+
+	cNumberField := C.CString(MapSheetNumberField)
+	C.addFieldToLayer(resultLayer.layer, cNumberField, C.OFTString)
+	C.free(unsafe.Pointer(cNumberField))
+	cScaleField := C.CString("SCALE")
+	C.addFieldToLayer(resultLayer.layer, cScaleField, C.OFTInteger)
+	C.free(unsafe.Pointer(cScaleField))
+
+	defn := resultLayer.GetLayerDefn()
+	numberIndex := C.int(layerFieldIndex(defn, MapSheetNumberField))
+	scaleIndex := C.int(layerFieldIndex(defn, "SCALE"))
+
+	for _, sheet := range sheets {
+		geometry := C.createTileClipGeometry(C.double(sheet.MinX), C.double(sheet.MinY), C.double(sheet.MaxX), C.double(sheet.MaxY))
+		if !sheet.Rectangular && transform != nil {
+			// 图廓线为经纬线，投影后为曲线，需要加密
+			C.OGR_G_Segmentize(geometry, C.double((sheet.MaxY-sheet.MinY)/16))
+			if C.OGR_G_Transform(geometry, transform) != C.OGRERR_NONE {
+				C.OGR_G_DestroyGeometry(geometry)
+				resultLayer.Close()
+				return nil, fmt.Errorf("图幅 %s 坐标转换失败", sheet.Number)
+			}
+		}
+
+		feature := C.OGR_F_Create(defn)
+		C.OGR_F_SetGeometryDirectly(feature, geometry)
+		cNumber := C.CString(sheet.Number)
+		C.OGR_F_SetFieldString(feature, numberIndex, cNumber)
+		C.free(unsafe.Pointer(cNumber))
+		C.OGR_F_SetFieldInteger(feature, scaleIndex, C.int(sheet.Scale))
+		C.OGR_L_CreateFeature(resultLayer.layer, feature)
+		C.OGR_F_Destroy(feature)
+	}
+
+	return resultLayer, nil
+}
+
+// GenerateMapSheetLayerForExtent 生成覆盖范围的图幅图层
+// 范围(minX, minY, maxX, maxY)位于srs坐标系中；rectangular为true时按矩形分幅（srs须为投影坐标系），
+// 否则按经纬度分幅，并只保留与范围实际相交的图幅。结果图层坐标系为srs。
+func GenerateMapSheetLayerForExtent(minX, minY, maxX, maxY float64, srs *SpatialReference, scale MapScale, rectangular bool) (*GDALLayer, error) {
+	defer runtime.KeepAlive(srs)
+	return generateMapSheetLayerForExtent(minX, minY, maxX, maxY, srs.handle(), scale, rectangular)
+}
+
+func generateMapSheetLayerForExtent(minX, minY, maxX, maxY float64, srs C.OGRSpatialReferenceH, scale MapScale, rectangular bool) (*GDALLayer, error) {
+	if srs == nil {
+		return nil, fmt.Errorf("空间参考为空")
+	}
+
+	if rectangular {
+		if C.OSRIsProjected(srs) == 0 {
+			return nil, fmt.Errorf("矩形分幅需要投影坐标系")
+		}
+		sheets, err := RectangularSheetsInExtent(minX, minY, maxX, maxY, scale)
+		if err != nil {
+			return nil, err
+		}
+		return createMapSheetLayer(sheets, srs)
+	}
+
+	// 将范围转换为CGCS2000经纬度
+	geogSRS, err := SRS_CGCS2000.ToOGRGDBSpatialReference()
+	if err != nil {
+		return nil, err
+	}
+	defer C.OSRDestroySpatialReference(geogSRS)
+	C.OSRSetAxisMappingStrategy(geogSRS, C.OAMS_TRADITIONAL_GIS_ORDER)
+
+	extent := C.createTileClipGeometry(C.double(minX), C.double(minY), C.double(maxX), C.double(maxY))
+	defer C.OGR_G_DestroyGeometry(extent)
+	geogExtent := C.OGR_G_Clone(extent)
+	defer C.OGR_G_DestroyGeometry(geogExtent)
+
+	if C.OSRIsSame(srs, geogSRS) == 0 {
+		C.OGR_G_Segmentize(geogExtent, C.double(math.Max(maxX-minX, maxY-minY)/64))
+		transform := C.OCTNewCoordinateTransformation(srs, geogSRS)
+		if transform == nil {
+			return nil, fmt.Errorf("无法创建到经纬度的坐标转换器")
+		}
+		result := C.OGR_G_Transform(geogExtent, transform)
+		C.OCTDestroyCoordinateTransformation(transform)
+		if result != C.OGRERR_NONE {
+			return nil, fmt.Errorf("范围坐标转换失败")
+		}
+	}
+
+	var env C.OGREnvelope
+	C.OGR_G_GetEnvelope(geogExtent, &env)
+	candidates, err := MapSheetsInExtent(float64(env.MinX), float64(env.MinY), float64(env.MaxX), float64(env.MaxY), scale)
+	if err != nil {
+		return nil, err
+	}
+
+	// 剔除仅与外包矩形相交、与实际范围不相交的图幅
+	sheets := make([]*MapSheet, 0, len(candidates))
+	for _, sheet := range candidates {
+		sheetGeom := C.createTileClipGeometry(C.double(sheet.MinX), C.double(sheet.MinY), C.double(sheet.MaxX), C.double(sheet.MaxY))
+		if C.OGR_G_Intersects(sheetGeom, geogExtent) != 0 {
+			sheets = append(sheets, sheet)
+		}
+		C.OGR_G_DestroyGeometry(sheetGeom)
+	}
+
+	return createMapSheetLayer(sheets, srs)
+}
+
+// GenerateMapSheetLayerForLayer 生成覆盖图层范围的图幅图层，坐标系与图层一致
+func GenerateMapSheetLayerForLayer(layer *GDALLayer, scale MapScale, rectangular bool) (*GDALLayer, error) {
+	minX, minY, maxX, maxY, err := GetLayerExtent(layer)
+	if err != nil {
+		return nil, err
+	}
+	srs := layer.GetSpatialRef()
+	if srs == nil {
+		return nil, fmt.Errorf("图层没有定义空间参考系统")
+	}
+	return generateMapSheetLayerForExtent(minX, minY, maxX, maxY, srs, scale, rectangular)
+}
+
+// GenerateMapSheetLayer 生成覆盖栅格范围的图幅图层，坐标系与栅格当前活动数据集一致，
+// 可直接作为ClipRasterByLayer的裁剪图层
+func (rd *RasterDataset) GenerateMapSheetLayer(scale MapScale, rectangular bool) (*GDALLayer, error) {
+	activeDS := rd.GetActiveDataset()
+	if activeDS == nil {
+		return nil, fmt.Errorf("dataset is nil")
+	}
+
+	var gt [6]C.double
+	if C.GDALGetGeoTransform(activeDS, &gt[0]) != C.CE_None {
+		return nil, fmt.Errorf("栅格没有地理变换信息")
+	}
+	width := float64(C.GDALGetRasterXSize(activeDS))
+	height := float64(C.GDALGetRasterYSize(activeDS))
+
+	// 四个角点求范围（兼容旋转的地理变换）
+	minX, minY := math.Inf(1), math.Inf(1)
+	maxX, maxY := math.Inf(-1), math.Inf(-1)
+	for _, p := range [][2]float64{{0, 0}, {width, 0}, {0, height}, {width, height}} {
+		x := float64(gt[0]) + p[0]*float64(gt[1]) + p[1]*float64(gt[2])
+		y := float64(gt[3]) + p[0]*float64(gt[4]) + p[1]*float64(gt[5])
+		minX, maxX = math.Min(minX, x), math.Max(maxX, x)
+		minY, maxY = math.Min(minY, y), math.Max(maxY, y)
+	}
+
+	srs := C.OSRNewSpatialReference(C.GDALGetProjectionRef(activeDS))
+	if srs == nil {
+		return nil, fmt.Errorf("栅格没有定义空间参考系统")
+	}
+	defer C.OSRDestroySpatialReference(srs)
+	C.OSRSetAxisMappingStrategy(srs, C.OAMS_TRADITIONAL_GIS_ORDER)
+
+	return generateMapSheetLayerForExtent(minX, minY, maxX, maxY, srs, scale, rectangular)
+}
+
+// ClipRasterByMapSheets 按标准图幅裁剪栅格，输出文件以图号命名
+func (rd *RasterDataset) ClipRasterByMapSheets(scale MapScale, rectangular bool, options *ClipOptions) ([]ClipResult, error) {
+	sheetLayer, err := rd.GenerateMapSheetLayer(scale, rectangular)
+	if err != nil {
+		return nil, err
+	}
+	defer sheetLayer.Close()
+
+	if options == nil {
+		options = &ClipOptions{}
+	}
+	opts := *options
+	opts.NameField = MapSheetNumberField
+	return rd.ClipRasterByLayer(sheetLayer, &opts)
+}
+
+// ============================================================================
+// 按面图层拆分矢量
+// ============================================================================
+
+// SplitLayer 使用面图层（如图幅图层）拆分矢量图层，返回以nameField取值为键的内存图层
+// 源要素按拆分面裁切，两图层坐标系不同时拆分面会被转换到源图层坐标系；
+// nameField取值必须唯一，出现重复取值时返回错误
+func SplitLayer(sourceLayer, splitLayer *GDALLayer, nameField string) (map[string]*GDALLayer, error) {
+	if sourceLayer == nil || sourceLayer.layer == nil {
+		return nil, fmt.Errorf("源图层为空")
+	}
+	if splitLayer == nil || splitLayer.layer == nil {
+		return nil, fmt.Errorf("拆分图层为空")
+	}
+
+	splitDefn := splitLayer.GetLayerDefn()
+	nameIndex := layerFieldIndex(splitDefn, nameField)
+	if nameIndex < 0 {
+		return nil, fmt.Errorf("拆分图层字段不存在: %s", nameField)
+	}
+
+	sourceSRS := sourceLayer.GetSpatialRef()
+	splitSRS := splitLayer.GetSpatialRef()
+	var transform C.OGRCoordinateTransformationH
+	if sourceSRS != nil && splitSRS != nil && C.OSRIsSame(sourceSRS, splitSRS) == 0 {
+		transform = C.OCTNewCoordinateTransformation(splitSRS, sourceSRS)
+		if transform == nil {
+			return nil, fmt.Errorf("无法创建拆分图层坐标转换器")
+		}
+		defer C.OCTDestroyCoordinateTransformation(transform)
+	}
+
+	sourceDefn := sourceLayer.GetLayerDefn()
+	geomType := C.OGR_FD_GetGeomType(sourceDefn)
+	results := make(map[string]*GDALLayer)
+	seen := make(map[string]bool)
+	closeResults := func() {
+		for _, l := range results {
+			l.Close()
+		}
+	}
+
+	splitLayer.ResetReading()
+	defer splitLayer.ResetReading()
+	for {
+		splitFeature := splitLayer.GetNextFeatureRow()
+		if splitFeature == nil {
+			break
+		}
+		name := C.GoString(C.OGR_F_GetFieldAsString(splitFeature, C.int(nameIndex)))
+		if seen[name] {
+			C.OGR_F_Destroy(splitFeature)
+			closeResults()
+			return nil, fmt.Errorf("拆分图层字段 %s 取值重复: %s", nameField, name)
+		}
+		seen[name] = true
+		splitGeom := C.OGR_F_GetGeometryRef(splitFeature)
+		if splitGeom == nil {
+			C.OGR_F_Destroy(splitFeature)
+			continue
+		}
+		clipGeom := C.OGR_G_Clone(splitGeom)
+		C.OGR_F_Destroy(splitFeature)
+		if transform != nil && C.OGR_G_Transform(clipGeom, transform) != C.OGRERR_NONE {
+			C.OGR_G_DestroyGeometry(clipGeom)
+			continue
+		}
+
+		partLayer, err := splitLayerByGeometry(sourceLayer, clipGeom, name, geomType)
+		C.OGR_G_DestroyGeometry(clipGeom)
+		if err != nil {
+			closeResults()
+			return nil, err
+		}
+		if partLayer == nil {
+			continue
+		}
+		results[name] = partLayer
+	}
+
+	return results, nil
+}
+
+// splitLayerByGeometry 提取源图层落在裁切面内的部分，无要素时返回nil
+func splitLayerByGeometry(sourceLayer *GDALLayer, clipGeom C.OGRGeometryH, name string, geomType C.OGRwkbGeometryType) (*GDALLayer, error) {
+	sourceDefn := sourceLayer.GetLayerDefn()
+
+	C.OGR_L_SetSpatialFilter(sourceLayer.layer, clipGeom)
+	defer C.OGR_L_SetSpatialFilter(sourceLayer.layer, nil)
+
+	var resultLayer *GDALLayer
+	sourceLayer.ResetReading()
+	for {
+		feature := sourceLayer.GetNextFeatureRow()
+		if feature == nil {
+			break
+		}
+		geometry := C.OGR_F_GetGeometryRef(feature)
+		if geometry == nil {
+			C.OGR_F_Destroy(feature)
+			continue
+		}
+
+		part := C.OGR_G_Intersection(geometry, clipGeom)
+		if part == nil || C.OGR_G_IsEmpty(part) != 0 {
+			if part != nil {
+				C.OGR_G_DestroyGeometry(part)
+			}
+			C.OGR_F_Destroy(feature)
+			continue
+		}
+		if geomType != C.wkbUnknown && C.OGR_GT_Flatten(C.OGR_G_GetGeometryType(part)) != C.OGR_GT_Flatten(geomType) {
+			if forced := C.forceGeometryType(part, geomType); forced != nil {
+				C.OGR_G_DestroyGeometry(part)
+				part = forced
+			}
+		}
+
+		if resultLayer == nil {
+			var err error
+			resultLayer, err = newMemoryResultLayer("split_result", name, sourceLayer.GetSpatialRef(), geomType)
+			if err != nil {
+				C.OGR_G_DestroyGeometry(part)
+				C.OGR_F_Destroy(feature)
+				return nil, err
+			}
+			copyLayerFieldDefns(sourceDefn, resultLayer.layer)
+		}
+
+		resultDefn := resultLayer.GetLayerDefn()
+		newFeature := C.OGR_F_Create(resultDefn)
+		C.OGR_F_SetGeometryDirectly(newFeature, part)
+		copyFeatureAttributes(feature, newFeature, sourceDefn, resultDefn)
+		C.OGR_L_CreateFeature(resultLayer.layer, newFeature)
+		C.OGR_F_Destroy(newFeature)
+		C.OGR_F_Destroy(feature)
+	}
+	sourceLayer.ResetReading()
+
+	return resultLayer, nil
+}
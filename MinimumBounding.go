@@ -0,0 +1,510 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+/*
+#include "osgeo_utils.h"
+*/
+import "C"
+import (
+	"fmt"
+	"log"
+	"math"
+	"math/rand"
+	"unsafe"
+)
+
+// ============================================================================
+// 最小外包几何：最小面积外接矩形、最小外接圆、凹包
+// ============================================================================
+
+// OrientedRectangle 最小面积外接矩形
+type OrientedRectangle struct {
+	Width       float64 // 短边长度
+	Length      float64 // 长边长度
+	Orientation float64 // 长边方位角（自正北顺时针，0-180度）
+	Area        float64
+	CenterX     float64
+	CenterY     float64
+}
+
+// BoundingCircle 最小外接圆
+type BoundingCircle struct {
+	CenterX float64
+	CenterY float64
+	Radius  float64
+}
+
+// collectGeometryPoints 收集几何体的全部顶点
+func collectGeometryPoints(geometry C.OGRGeometryH) [][2]float64 {
+	if geometry == nil {
+		return nil
+	}
+	subCount := int(C.OGR_G_GetGeometryCount(geometry))
+	if subCount > 0 {
+		var points [][2]float64
+		for i := 0; i < subCount; i++ {
+			points = append(points, collectGeometryPoints(C.OGR_G_GetGeometryRef(geometry, C.int(i)))...)
+		}
+		return points
+	}
+	count := int(C.OGR_G_GetPointCount(geometry))
+	points := make([][2]float64, count)
+	for i := 0; i < count; i++ {
+		points[i] = [2]float64{float64(C.OGR_G_GetX(geometry, C.int(i))), float64(C.OGR_G_GetY(geometry, C.int(i)))}
+	}
+	return points
+}
+
+// convexHullPoints 返回凸包顶点（不含闭合点）
+func convexHullPoints(geometry C.OGRGeometryH) [][2]float64 {
+	hull := C.OGR_G_ConvexHull(geometry)
+	if hull == nil {
+		return nil
+	}
+	defer C.OGR_G_DestroyGeometry(hull)
+
+	points := collectGeometryPoints(hull)
+	if len(points) > 1 && points[0] == points[len(points)-1] {
+		points = points[:len(points)-1]
+	}
+	return points
+}
+
+// minimumAreaRectangle 计算几何体的最小面积外接矩形（旋转卡壳），返回矩形面几何体（对外接口为 (*Geometry).MinimumAreaRectangle）
+func minimumAreaRectangle(geometry C.OGRGeometryH) (C.OGRGeometryH, *OrientedRectangle, error) {
+	if geometry == nil {
+		return nil, nil, fmt.Errorf("几何体为空")
+	}
+	hull := convexHullPoints(geometry)
+	if len(hull) < 2 {
+		return nil, nil, fmt.Errorf("几何体顶点不足，无法计算外接矩形")
+	}
+
+	bestArea := math.Inf(1)
+	var corners [4][2]float64
+	var rect OrientedRectangle
+	n := len(hull)
+	for i := 0; i < n; i++ {
+		p, q := hull[i], hull[(i+1)%n]
+		edgeLen := math.Hypot(q[0]-p[0], q[1]-p[1])
+		if edgeLen == 0 {
+			continue
+		}
+		ux, uy := (q[0]-p[0])/edgeLen, (q[1]-p[1])/edgeLen
+		vx, vy := -uy, ux
+
+		minA, maxA := math.Inf(1), math.Inf(-1)
+		minB, maxB := math.Inf(1), math.Inf(-1)
+		for _, pt := range hull {
+			a := pt[0]*ux + pt[1]*uy
+			b := pt[0]*vx + pt[1]*vy
+			minA, maxA = math.Min(minA, a), math.Max(maxA, a)
+			minB, maxB = math.Min(minB, b), math.Max(maxB, b)
+		}
+
+		area := (maxA - minA) * (maxB - minB)
+		if area >= bestArea {
+			continue
+		}
+		bestArea = area
+		corner := func(a, b float64) [2]float64 {
+			return [2]float64{a*ux + b*vx, a*uy + b*vy}
+		}
+		corners = [4][2]float64{corner(minA, minB), corner(maxA, minB), corner(maxA, maxB), corner(minA, maxB)}
+
+		sideU, sideV := maxA-minA, maxB-minB
+		dirX, dirY := ux, uy
+		rect.Length, rect.Width = sideU, sideV
+		if sideV > sideU {
+			dirX, dirY = vx, vy
+			rect.Length, rect.Width = sideV, sideU
+		}
+		azimuth := math.Mod(math.Atan2(dirX, dirY)*180/math.Pi+360, 180)
+		rect.Orientation = azimuth
+		rect.Area = area
+		center := corner((minA+maxA)/2, (minB+maxB)/2)
+		rect.CenterX, rect.CenterY = center[0], center[1]
+	}
+	if math.IsInf(bestArea, 1) {
+		return nil, nil, fmt.Errorf("几何体顶点重合，无法计算外接矩形")
+	}
+
+	ring := C.OGR_G_CreateGeometry(C.wkbLinearRing)
+	for _, c := range corners {
+		C.OGR_G_AddPoint_2D(ring, C.double(c[0]), C.double(c[1]))
+	}
+	C.OGR_G_AddPoint_2D(ring, C.double(corners[0][0]), C.double(corners[0][1]))
+	polygon := C.OGR_G_CreateGeometry(C.wkbPolygon)
+	C.OGR_G_AddGeometryDirectly(polygon, ring)
+	return polygon, &rect, nil
+}
+
+// minimumBoundingCircle 计算几何体的最小外接圆（Welzl增量算法），
+// 返回以quadSegs（每四分之一圆的段数）逼近的圆面几何体（对外接口为 (*Geometry).MinimumBoundingCircle）
+func minimumBoundingCircle(geometry C.OGRGeometryH, quadSegs int) (C.OGRGeometryH, *BoundingCircle, error) {
+	if geometry == nil {
+		return nil, nil, fmt.Errorf("几何体为空")
+	}
+	points := convexHullPoints(geometry)
+	if len(points) == 0 {
+		return nil, nil, fmt.Errorf("几何体没有顶点")
+	}
+	if quadSegs <= 0 {
+		quadSegs = 30
+	}
+
+	circle := welzlCircle(points)
+
+	center := C.OGR_G_CreateGeometry(C.wkbPoint)
+	C.OGR_G_SetPoint_2D(center, 0, C.double(circle.CenterX), C.double(circle.CenterY))
+	defer C.OGR_G_DestroyGeometry(center)
+
+	// 缓冲得到的多边形内接于圆，按段数放大半径使其外接于原圆
+	radius := circle.Radius / math.Cos(math.Pi/float64(4*quadSegs))
+	polygon := C.OGR_G_Buffer(center, C.double(radius), C.int(quadSegs))
+	if polygon == nil {
+		return nil, nil, fmt.Errorf("生成外接圆失败")
+	}
+	return polygon, &circle, nil
+}
+
+// welzlCircle 随机增量法求点集最小外接圆
+func welzlCircle(points [][2]float64) BoundingCircle {
+	shuffled := make([][2]float64, len(points))
+	copy(shuffled, points)
+	rnd := rand.New(rand.NewSource(1))
+	rnd.Shuffle(len(shuffled), func(i, j int) { shuffled[i], shuffled[j] = shuffled[j], shuffled[i] })
+
+	const eps = 1e-9
+	contains := func(c BoundingCircle, p [2]float64) bool {
+		return math.Hypot(p[0]-c.CenterX, p[1]-c.CenterY) <= c.Radius*(1+eps)+eps
+	}
+	fromTwo := func(a, b [2]float64) BoundingCircle {
+		return BoundingCircle{(a[0] + b[0]) / 2, (a[1] + b[1]) / 2, math.Hypot(a[0]-b[0], a[1]-b[1]) / 2}
+	}
+	fromThree := func(a, b, c [2]float64) BoundingCircle {
+		d := 2 * (a[0]*(b[1]-c[1]) + b[0]*(c[1]-a[1]) + c[0]*(a[1]-b[1]))
+		if math.Abs(d) < 1e-12 {
+			// 三点共线时取距离最远的两点
+			best := fromTwo(a, b)
+			for _, cand := range []BoundingCircle{fromTwo(a, c), fromTwo(b, c)} {
+				if cand.Radius > best.Radius {
+					best = cand
+				}
+			}
+			return best
+		}
+		a2, b2, c2 := a[0]*a[0]+a[1]*a[1], b[0]*b[0]+b[1]*b[1], c[0]*c[0]+c[1]*c[1]
+		x := (a2*(b[1]-c[1]) + b2*(c[1]-a[1]) + c2*(a[1]-b[1])) / d
+		y := (a2*(c[0]-b[0]) + b2*(a[0]-c[0]) + c2*(b[0]-a[0])) / d
+		return BoundingCircle{x, y, math.Hypot(a[0]-x, a[1]-y)}
+	}
+
+	circle := BoundingCircle{shuffled[0][0], shuffled[0][1], 0}
+	for i := 1; i < len(shuffled); i++ {
+		if contains(circle, shuffled[i]) {
+			continue
+		}
+		circle = BoundingCircle{shuffled[i][0], shuffled[i][1], 0}
+		for j := 0; j < i; j++ {
+			if contains(circle, shuffled[j]) {
+				continue
+			}
+			circle = fromTwo(shuffled[i], shuffled[j])
+			for k := 0; k < j; k++ {
+				if !contains(circle, shuffled[k]) {
+					circle = fromThree(shuffled[i], shuffled[j], shuffled[k])
+				}
+			}
+		}
+	}
+	return circle
+}
+
+// concaveHullGeometry 计算几何体顶点的凹包（对外接口为 (*Geometry).ConcaveHull）
+// ratio: 凹度比例（0-1），1等价于凸包，越小越贴合点集；allowHoles: 是否允许结果带洞
+func concaveHullGeometry(geometry C.OGRGeometryH, ratio float64, allowHoles bool) C.OGRGeometryH {
+	if geometry == nil {
+		return nil
+	}
+	ratio = math.Max(0, math.Min(1, ratio))
+	holes := 0
+	if allowHoles {
+		holes = 1
+	}
+	return C.OGR_G_ConcaveHull(geometry, C.double(ratio), C.int(holes))
+}
+
+// ============================================================================
+// 图层级最小外包几何
+// ============================================================================
+
+// BoundingGeometryType 外包几何类型
+type BoundingGeometryType int
+
+const (
+	BoundingByRectangle   BoundingGeometryType = iota // 最小面积外接矩形
+	BoundingByCircle                                  // 最小外接圆
+	BoundingByConvexHull                              // 凸包
+	BoundingByConcaveHull                             // 凹包
+)
+
+// MinimumBoundingOptions 图层级外包几何选项
+type MinimumBoundingOptions struct {
+	Type         BoundingGeometryType
+	GroupField   string  // 分组字段：同组要素合并计算一个外包几何；为空时逐要素计算
+	GroupAll     bool    // 为true时全部要素合并计算一个外包几何（优先于GroupField）
+	ConcaveRatio float64 // 凹包凹度比例（0-1），默认0.3
+	AllowHoles   bool    // 凹包是否允许带洞
+	QuadSegs     int     // 外接圆每四分之一圆的段数，默认30
+	// DegenerateBuffer 大于0时，外包几何退化为点或线的要素（分组）输出其凸包按此距离缓冲后的面；
+	// 否则不写入结果图层，仅在日志中报告数量
+	DegenerateBuffer float64
+}
+
+// MinimumBoundingGeometryLayer 计算图层的最小外包几何
+// 逐要素计算时保留源字段；分组计算时输出分组字段与要素数量字段COUNT。
+// 矩形结果附加MBG_WIDTH、MBG_LENGTH、MBG_ORIENT字段，外接圆结果附加MBG_DIAM字段。
+// 结果图层固定为面图层：单点、重合点或共线点的外包几何会退化为点、线或零面积面，
+// 这类要素（或分组）按DegenerateBuffer缓冲后输出（不带MBG_*字段值），未设置时跳过并记录数量。
+func MinimumBoundingGeometryLayer(sourceLayer *GDALLayer, options *MinimumBoundingOptions) (*GDALLayer, error) {
+	if sourceLayer == nil || sourceLayer.layer == nil {
+		return nil, fmt.Errorf("源图层为空")
+	}
+	if options == nil {
+		options = &MinimumBoundingOptions{}
+	}
+	opts := *options
+	if opts.ConcaveRatio <= 0 {
+		opts.ConcaveRatio = 0.3
+	}
+
+	sourceDefn := sourceLayer.GetLayerDefn()
+	grouped := opts.GroupAll || opts.GroupField != ""
+	groupIndex := -1
+	if !opts.GroupAll && opts.GroupField != "" {
+		groupIndex = layerFieldIndex(sourceDefn, opts.GroupField)
+		if groupIndex < 0 {
+			return nil, fmt.Errorf("分组字段不存在: %s", opts.GroupField)
+		}
+	}
+
+	resultLayer, err := newMemoryResultLayer("bounding_result", "minimum_bounding", sourceLayer.GetSpatialRef(), C.wkbPolygon)
+	if err != nil {
+		return nil, err
+	}
+
+	// 结果字段
+	if !grouped {
+		copyLayerFieldDefns(sourceDefn, resultLayer.layer)
+	} else {
+		if groupIndex >= 0 {
+			C.OGR_L_CreateField(resultLayer.layer, C.OGR_FD_GetFieldDefn(sourceDefn, C.int(groupIndex)), C.int(1))
+		}
+		addBoundingField(resultLayer, "COUNT", C.OFTInteger)
+	}
+	switch opts.Type {
+	case BoundingByRectangle:
+		addBoundingField(resultLayer, "MBG_WIDTH", C.OFTReal)
+		addBoundingField(resultLayer, "MBG_LENGTH", C.OFTReal)
+		addBoundingField(resultLayer, "MBG_ORIENT", C.OFTReal)
+	case BoundingByCircle:
+		addBoundingField(resultLayer, "MBG_DIAM", C.OFTReal)
+	}
+	resultDefn := resultLayer.GetLayerDefn()
+
+	// writeResult 计算外包几何并写入结果要素，source为逐要素模式下的源要素
+	degenerate := 0
+	writeResult := func(geometry C.OGRGeometryH, source C.OGRFeatureH, groupValue C.OGRFeatureH, count int) error {
+		var bounding C.OGRGeometryH
+		var values map[string]float64
+		if !boundingIsDegenerate(geometry) {
+			var err error
+			bounding, values, err = computeBoundingGeometry(geometry, &opts)
+			if err != nil {
+				return err
+			}
+			if boundingIsDegenerate(bounding) {
+				C.OGR_G_DestroyGeometry(bounding)
+				bounding, values = nil, nil
+			}
+		}
+		if bounding == nil {
+			if opts.DegenerateBuffer > 0 {
+				bounding = bufferDegenerateBounding(geometry, opts.DegenerateBuffer, opts.QuadSegs)
+			}
+			if bounding == nil {
+				degenerate++
+				return nil
+			}
+		}
+		feature := C.OGR_F_Create(resultDefn)
+		C.OGR_F_SetGeometryDirectly(feature, bounding)
+		if source != nil {
+			copyFeatureAttributes(source, feature, sourceDefn, resultDefn)
+		} else {
+			if groupValue != nil {
+				// 分组字段按源字段定义创建，直接复制原始值以保持类型
+				C.OGR_F_SetFieldRaw(feature, 0, C.OGR_F_GetRawFieldRef(groupValue, C.int(groupIndex)))
+			}
+			C.OGR_F_SetFieldInteger(feature, C.int(layerFieldIndex(resultDefn, "COUNT")), C.int(count))
+		}
+		for name, value := range values {
+			C.OGR_F_SetFieldDouble(feature, C.int(layerFieldIndex(resultDefn, name)), C.double(value))
+		}
+		C.OGR_L_CreateFeature(resultLayer.layer, feature)
+		C.OGR_F_Destroy(feature)
+		return nil
+	}
+
+	type boundingGroup struct {
+		collection C.OGRGeometryH
+		sample     C.OGRFeatureH // 保存分组值的要素
+		count      int
+	}
+	groups := make(map[string]*boundingGroup)
+	var order []string
+	defer func() {
+		for _, g := range groups {
+			C.OGR_G_DestroyGeometry(g.collection)
+			if g.sample != nil {
+				C.OGR_F_Destroy(g.sample)
+			}
+		}
+	}()
+
+	sourceLayer.ResetReading()
+	defer sourceLayer.ResetReading()
+	for {
+		feature := sourceLayer.GetNextFeatureRow()
+		if feature == nil {
+			break
+		}
+		geometry := C.OGR_F_GetGeometryRef(feature)
+		if geometry == nil || C.OGR_G_IsEmpty(geometry) != 0 {
+			C.OGR_F_Destroy(feature)
+			continue
+		}
+
+		if !grouped {
+			err := writeResult(geometry, feature, nil, 1)
+			C.OGR_F_Destroy(feature)
+			if err != nil {
+				resultLayer.Close()
+				return nil, err
+			}
+			continue
+		}
+
+		key := ""
+		if groupIndex >= 0 {
+			key = C.GoString(C.OGR_F_GetFieldAsString(feature, C.int(groupIndex)))
+		}
+		group, ok := groups[key]
+		if !ok {
+			group = &boundingGroup{collection: C.OGR_G_CreateGeometry(C.wkbGeometryCollection)}
+			if groupIndex >= 0 {
+				group.sample = C.OGR_F_Clone(feature)
+			}
+			groups[key] = group
+			order = append(order, key)
+		}
+		C.OGR_G_AddGeometry(group.collection, geometry)
+		group.count++
+		C.OGR_F_Destroy(feature)
+	}
+
+	for _, key := range order {
+		group := groups[key]
+		if err := writeResult(group.collection, nil, group.sample, group.count); err != nil {
+			resultLayer.Close()
+			return nil, fmt.Errorf("分组 %s: %v", key, err)
+		}
+	}
+	if degenerate > 0 {
+		log.Printf("最小外包几何: %d 个要素（分组）的外包几何退化为点或线，未写入结果图层（可设置DegenerateBuffer输出）", degenerate)
+	}
+
+	return resultLayer, nil
+}
+
+func addBoundingField(layer *GDALLayer, name string, fieldType C.OGRFieldType) {
+	cName := C.CString(name)
+	defer C.free(unsafe.Pointer(cName))
+	C.addFieldToLayer(layer.layer, cName, fieldType)
+}
+
+// boundingIsDegenerate 判断几何体的凸包是否退化为点或线（无法得到非零面积的外包面）
+func boundingIsDegenerate(geometry C.OGRGeometryH) bool {
+	hull := C.OGR_G_ConvexHull(geometry)
+	if hull == nil {
+		return true
+	}
+	defer C.OGR_G_DestroyGeometry(hull)
+	flat := C.OGR_GT_Flatten(C.OGR_G_GetGeometryType(hull))
+	return (flat != C.wkbPolygon && flat != C.wkbMultiPolygon) || C.OGR_G_Area(hull) <= 0
+}
+
+// bufferDegenerateBounding 将退化为点或线的凸包按distance缓冲为面
+func bufferDegenerateBounding(geometry C.OGRGeometryH, distance float64, quadSegs int) C.OGRGeometryH {
+	if quadSegs <= 0 {
+		quadSegs = 30
+	}
+	hull := C.OGR_G_ConvexHull(geometry)
+	if hull == nil {
+		return nil
+	}
+	defer C.OGR_G_DestroyGeometry(hull)
+	return C.OGR_G_Buffer(hull, C.double(distance), C.int(quadSegs))
+}
+
+// computeBoundingGeometry 按选项计算外包几何，返回几何体及附加字段值
+func computeBoundingGeometry(geometry C.OGRGeometryH, opts *MinimumBoundingOptions) (C.OGRGeometryH, map[string]float64, error) {
+	switch opts.Type {
+	case BoundingByRectangle:
+		polygon, rect, err := minimumAreaRectangle(geometry)
+		if err != nil {
+			return nil, nil, err
+		}
+		return polygon, map[string]float64{
+			"MBG_WIDTH":  rect.Width,
+			"MBG_LENGTH": rect.Length,
+			"MBG_ORIENT": rect.Orientation,
+		}, nil
+	case BoundingByCircle:
+		polygon, circle, err := minimumBoundingCircle(geometry, opts.QuadSegs)
+		if err != nil {
+			return nil, nil, err
+		}
+		return polygon, map[string]float64{"MBG_DIAM": circle.Radius * 2}, nil
+	case BoundingByConvexHull:
+		hull := C.OGR_G_ConvexHull(geometry)
+		if hull == nil {
+			return nil, nil, fmt.Errorf("凸包计算失败")
+		}
+		return hull, nil, nil
+	case BoundingByConcaveHull:
+		hull := concaveHullGeometry(geometry, opts.ConcaveRatio, opts.AllowHoles)
+		if hull == nil {
+			return nil, nil, fmt.Errorf("凹包计算失败")
+		}
+		return hull, nil, nil
+	default:
+		return nil, nil, fmt.Errorf("不支持的外包几何类型: %d", opts.Type)
+	}
+}
@@ -0,0 +1,410 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+/*
+#include "osgeo_utils.h"
+*/
+import "C"
+import (
+	"bytes"
+	"fmt"
+	"image"
+	"image/png"
+	"math"
+	"unsafe"
+
+	"github.com/paulmach/orb"
+	"github.com/paulmach/orb/geojson"
+	"github.com/paulmach/orb/project"
+)
+
+// ============================================================================
+// GCJ-02 / BD-09 偏移坐标转换（国内互联网地图平台坐标）
+// ============================================================================
+
+// OffsetCoordSystem 经纬度坐标体系
+type OffsetCoordSystem int
+
+const (
+	// CoordWGS84 WGS84/CGCS2000真实经纬度（两者在互联网地图精度下可视为一致）
+	CoordWGS84 OffsetCoordSystem = iota
+	// CoordGCJ02 国测局加密坐标（高德、腾讯等平台）
+	CoordGCJ02
+	// CoordBD09 百度坐标
+	CoordBD09
+)
+
+func (c OffsetCoordSystem) String() string {
+	switch c {
+	case CoordWGS84:
+		return "WGS84"
+	case CoordGCJ02:
+		return "GCJ-02"
+	case CoordBD09:
+		return "BD-09"
+	default:
+		return "未知坐标体系"
+	}
+}
+
+const (
+	gcjSemiMajor    = 6378245.0
+	gcjEccentricity = 0.00669342162296594323
+	bdXPi           = math.Pi * 3000.0 / 180.0
+
+	// 迭代反算的收敛阈值（度），约0.01毫米
+	offsetInverseTolerance = 1e-10
+	offsetInverseMaxIter   = 30
+)
+
+// IsOutOfChina 判断经纬度是否在中国范围外（范围外不做偏移）
+func IsOutOfChina(lon, lat float64) bool {
+	return lon < 72.004 || lon > 137.8347 || lat < 0.8293 || lat > 55.8271
+}
+
+func gcjTransformLat(x, y float64) float64 {
+	ret := -100.0 + 2.0*x + 3.0*y + 0.2*y*y + 0.1*x*y + 0.2*math.Sqrt(math.Abs(x))
+	ret += (20.0*math.Sin(6.0*x*math.Pi) + 20.0*math.Sin(2.0*x*math.Pi)) * 2.0 / 3.0
+	ret += (20.0*math.Sin(y*math.Pi) + 40.0*math.Sin(y/3.0*math.Pi)) * 2.0 / 3.0
+	ret += (160.0*math.Sin(y/12.0*math.Pi) + 320*math.Sin(y*math.Pi/30.0)) * 2.0 / 3.0
+	return ret
+}
+
+func gcjTransformLon(x, y float64) float64 {
+	ret := 300.0 + x + 2.0*y + 0.1*x*x + 0.1*x*y + 0.1*math.Sqrt(math.Abs(x))
+	ret += (20.0*math.Sin(6.0*x*math.Pi) + 20.0*math.Sin(2.0*x*math.Pi)) * 2.0 / 3.0
+	ret += (20.0*math.Sin(x*math.Pi) + 40.0*math.Sin(x/3.0*math.Pi)) * 2.0 / 3.0
+	ret += (150.0*math.Sin(x/12.0*math.Pi) + 300.0*math.Sin(x/30.0*math.Pi)) * 2.0 / 3.0
+	return ret
+}
+
+// WGS84ToGCJ02 WGS84经纬度转GCJ-02
+func WGS84ToGCJ02(lon, lat float64) (float64, float64) {
+	if IsOutOfChina(lon, lat) {
+		return lon, lat
+	}
+	dLat := gcjTransformLat(lon-105.0, lat-35.0)
+	dLon := gcjTransformLon(lon-105.0, lat-35.0)
+	radLat := lat / 180.0 * math.Pi
+	magic := math.Sin(radLat)
+	magic = 1 - gcjEccentricity*magic*magic
+	sqrtMagic := math.Sqrt(magic)
+	dLat = (dLat * 180.0) / ((gcjSemiMajor * (1 - gcjEccentricity)) / (magic * sqrtMagic) * math.Pi)
+	dLon = (dLon * 180.0) / (gcjSemiMajor / sqrtMagic * math.Cos(radLat) * math.Pi)
+	return lon + dLon, lat + dLat
+}
+
+// GCJ02ToWGS84 GCJ-02转WGS84经纬度（迭代反算，精度优于1厘米）
+func GCJ02ToWGS84(lon, lat float64) (float64, float64) {
+	if IsOutOfChina(lon, lat) {
+		return lon, lat
+	}
+	return invertOffset(WGS84ToGCJ02, lon, lat, lon, lat)
+}
+
+// GCJ02ToBD09 GCJ-02转BD-09
+func GCJ02ToBD09(lon, lat float64) (float64, float64) {
+	z := math.Sqrt(lon*lon+lat*lat) + 0.00002*math.Sin(lat*bdXPi)
+	theta := math.Atan2(lat, lon) + 0.000003*math.Cos(lon*bdXPi)
+	return z*math.Cos(theta) + 0.0065, z*math.Sin(theta) + 0.006
+}
+
+// BD09ToGCJ02 BD-09转GCJ-02（以近似公式为初值迭代反算）
+func BD09ToGCJ02(lon, lat float64) (float64, float64) {
+	x := lon - 0.0065
+	y := lat - 0.006
+	z := math.Sqrt(x*x+y*y) - 0.00002*math.Sin(y*bdXPi)
+	theta := math.Atan2(y, x) - 0.000003*math.Cos(x*bdXPi)
+	return invertOffset(GCJ02ToBD09, lon, lat, z*math.Cos(theta), z*math.Sin(theta))
+}
+
+// WGS84ToBD09 WGS84经纬度转BD-09
+func WGS84ToBD09(lon, lat float64) (float64, float64) {
+	return GCJ02ToBD09(WGS84ToGCJ02(lon, lat))
+}
+
+// BD09ToWGS84 BD-09转WGS84经纬度
+func BD09ToWGS84(lon, lat float64) (float64, float64) {
+	return GCJ02ToWGS84(BD09ToGCJ02(lon, lat))
+}
+
+// invertOffset 迭代求解forward(x) = (lon, lat)，从(initLon, initLat)开始
+func invertOffset(forward func(lon, lat float64) (float64, float64), lon, lat, initLon, initLat float64) (float64, float64) {
+	x, y := initLon, initLat
+	for i := 0; i < offsetInverseMaxIter; i++ {
+		fx, fy := forward(x, y)
+		dx, dy := fx-lon, fy-lat
+		x -= dx
+		y -= dy
+		if math.Abs(dx) < offsetInverseTolerance && math.Abs(dy) < offsetInverseTolerance {
+			break
+		}
+	}
+	return x, y
+}
+
+// ConvertOffsetCoord 在WGS84、GCJ-02、BD-09之间转换单个经纬度
+func ConvertOffsetCoord(lon, lat float64, from, to OffsetCoordSystem) (float64, float64) {
+	if from == to {
+		return lon, lat
+	}
+
+	// 统一先转到WGS84
+	switch from {
+	case CoordGCJ02:
+		lon, lat = GCJ02ToWGS84(lon, lat)
+	case CoordBD09:
+		lon, lat = BD09ToWGS84(lon, lat)
+	}
+
+	switch to {
+	case CoordGCJ02:
+		return WGS84ToGCJ02(lon, lat)
+	case CoordBD09:
+		return WGS84ToBD09(lon, lat)
+	default:
+		return lon, lat
+	}
+}
+
+// offsetConverter 返回坐标体系转换函数
+func offsetConverter(from, to OffsetCoordSystem) (func(lon, lat float64) (float64, float64), error) {
+	for _, c := range []OffsetCoordSystem{from, to} {
+		if c < CoordWGS84 || c > CoordBD09 {
+			return nil, fmt.Errorf("不支持的坐标体系: %d", int(c))
+		}
+	}
+	return func(lon, lat float64) (float64, float64) {
+		return ConvertOffsetCoord(lon, lat, from, to)
+	}, nil
+}
+
+// ============================================================================
+// 几何体、图层与GeoJSON转换
+// ============================================================================
+
+// transformGeometryOffset 转换经纬度几何体的坐标体系，返回新几何体（对外接口为 (*Geometry).TransformOffset）
+func transformGeometryOffset(geometry C.OGRGeometryH, from, to OffsetCoordSystem) (C.OGRGeometryH, error) {
+	if geometry == nil {
+		return nil, fmt.Errorf("几何体为空")
+	}
+	convert, err := offsetConverter(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	result := C.OGR_G_Clone(geometry)
+	mapGeometryVertices(result, func(x, y, z float64) (float64, float64, float64) {
+		nx, ny := convert(x, y)
+		return nx, ny, z
+	})
+	return result, nil
+}
+
+// TransformLayerOffset 转换图层的坐标体系（返回新的内存图层）
+// 图层可以是经纬度或投影坐标系，投影坐标会先反算为经纬度，偏移后再投影回原坐标系，
+// 因此结果图层的空间参考与源图层一致，可以继续接入ReprojectLayer等处理流程。
+func TransformLayerOffset(sourceLayer *GDALLayer, from, to OffsetCoordSystem) (*GDALLayer, error) {
+	if sourceLayer == nil || sourceLayer.layer == nil {
+		return nil, fmt.Errorf("源图层为空")
+	}
+	convert, err := offsetConverter(from, to)
+	if err != nil {
+		return nil, err
+	}
+
+	sourceSRS := sourceLayer.GetSpatialRef()
+	if sourceSRS == nil {
+		return nil, fmt.Errorf("源图层没有定义空间参考系统")
+	}
+
+	geog, err := newGeographicTransforms(sourceSRS)
+	if err != nil {
+		return nil, err
+	}
+	defer geog.destroy()
+
+	return transformLayerGeometries(sourceLayer, sourceSRS, "offset_result", func(geometry C.OGRGeometryH) error {
+		if C.OGR_G_Transform(geometry, geog.toGeog) != C.OGRERR_NONE {
+			return fmt.Errorf("转换到经纬度失败")
+		}
+		mapGeometryVertices(geometry, func(x, y, z float64) (float64, float64, float64) {
+			nx, ny := convert(x, y)
+			return nx, ny, z
+		})
+		if C.OGR_G_Transform(geometry, geog.fromGeog) != C.OGRERR_NONE {
+			return fmt.Errorf("转换回源坐标系失败")
+		}
+		return nil
+	})
+}
+
+// ConvertOffset 转换图层的坐标体系（返回新的内存图层）
+func (gl *GDALLayer) ConvertOffset(from, to OffsetCoordSystem) (*GDALLayer, error) {
+	return TransformLayerOffset(gl, from, to)
+}
+
+// TransformFeatureCollectionOffset 转换GeoJSON要素集合的坐标体系（返回新的要素集合，原集合不变）
+func TransformFeatureCollectionOffset(fc *geojson.FeatureCollection, from, to OffsetCoordSystem) (*geojson.FeatureCollection, error) {
+	if fc == nil {
+		return nil, fmt.Errorf("要素集合为空")
+	}
+	convert, err := offsetConverter(from, to)
+	if err != nil {
+		return nil, err
+	}
+	projection := func(p orb.Point) orb.Point {
+		lon, lat := convert(p[0], p[1])
+		return orb.Point{lon, lat}
+	}
+
+	result := geojson.NewFeatureCollection()
+	result.ExtraMembers = fc.ExtraMembers
+	for _, feature := range fc.Features {
+		if feature == nil {
+			continue
+		}
+		newFeature := &geojson.Feature{
+			ID:           feature.ID,
+			Type:         feature.Type,
+			Properties:   feature.Properties.Clone(),
+			ExtraMembers: feature.ExtraMembers,
+		}
+		if feature.Geometry != nil {
+			newFeature.Geometry = project.Geometry(orb.Clone(feature.Geometry), projection)
+			if len(feature.BBox) > 0 {
+				newFeature.BBox = geojson.NewBBox(newFeature.Geometry.Bound())
+			}
+		}
+		result.Append(newFeature)
+	}
+	return result, nil
+}
+
+// ============================================================================
+// 栅格瓦片转换
+// ============================================================================
+
+// ReadTileOffset 读取偏移坐标体系下的XYZ瓦片（PNG，黑色背景转透明）
+// source为影像本身的坐标体系（通常为CoordWGS84），target为瓦片服务所用体系，
+// 输出瓦片可直接叠加在GCJ-02或BD-09底图上。采用最近邻采样。
+func (rd *RasterDataset) ReadTileOffset(zoom, x, y, tileSize int, source, target OffsetCoordSystem) ([]byte, error) {
+	if source == target {
+		return rd.ReadTile(zoom, x, y, tileSize)
+	}
+	if rd.warpedDS == nil {
+		return nil, fmt.Errorf("dataset is not reprojected to web mercator")
+	}
+	convert, err := offsetConverter(target, source)
+	if err != nil {
+		return nil, err
+	}
+
+	// 目标瓦片像素中心 -> 影像坐标体系下的Web墨卡托坐标
+	toSource := func(mx, my float64) (float64, float64) {
+		lon, lat := WebMercatorToLatLon(mx, my)
+		lon, lat = convert(lon, lat)
+		return LatLonToWebMercator(lon, lat)
+	}
+
+	minX, minY, maxX, maxY := TileToWebMercatorBounds(x, y, zoom)
+	pixelSize := (maxX - minX) / float64(tileSize)
+
+	// 沿瓦片边界采样求源范围
+	srcMinX, srcMinY := math.Inf(1), math.Inf(1)
+	srcMaxX, srcMaxY := math.Inf(-1), math.Inf(-1)
+	const edgeSamples = 16
+	for i := 0; i <= edgeSamples; i++ {
+		t := float64(i) / edgeSamples
+		for _, p := range [][2]float64{
+			{minX + t*(maxX-minX), minY}, {minX + t*(maxX-minX), maxY},
+			{minX, minY + t*(maxY-minY)}, {maxX, minY + t*(maxY-minY)},
+		} {
+			sx, sy := toSource(p[0], p[1])
+			srcMinX, srcMaxX = math.Min(srcMinX, sx), math.Max(srcMaxX, sx)
+			srcMinY, srcMaxY = math.Min(srcMinY, sy), math.Max(srcMaxY, sy)
+		}
+	}
+
+	// 外扩2个像素并保持像素为正方形
+	srcMinX -= 2 * pixelSize
+	srcMinY -= 2 * pixelSize
+	side := math.Max(srcMaxX-srcMinX, srcMaxY-srcMinY) + 2*pixelSize
+	srcMaxX = srcMinX + side
+	srcMaxY = srcMinY + side
+	srcSize := int(math.Ceil(side / pixelSize))
+	if srcSize > 4*tileSize {
+		srcSize = 4 * tileSize
+	}
+	srcPixel := side / float64(srcSize)
+
+	buffer := make([]byte, srcSize*srcSize*4)
+	bands := int(C.readTileData(
+		rd.warpedDS,
+		C.double(srcMinX), C.double(srcMinY), C.double(srcMaxX), C.double(srcMaxY),
+		C.int(srcSize),
+		(*C.uchar)(unsafe.Pointer(&buffer[0])),
+	))
+	if bands == 0 {
+		return nil, fmt.Errorf("failed to read tile data")
+	}
+	if bands != 3 && bands != 4 {
+		return nil, fmt.Errorf("unsupported band count: %d", bands)
+	}
+
+	plane := srcSize * srcSize
+	rgbaImg := image.NewRGBA(image.Rect(0, 0, tileSize, tileSize))
+	for row := 0; row < tileSize; row++ {
+		my := maxY - (float64(row)+0.5)*pixelSize
+		for col := 0; col < tileSize; col++ {
+			mx := minX + (float64(col)+0.5)*pixelSize
+			sx, sy := toSource(mx, my)
+
+			srcCol := int((sx - srcMinX) / srcPixel)
+			srcRow := int((srcMaxY - sy) / srcPixel)
+			if srcCol < 0 || srcCol >= srcSize || srcRow < 0 || srcRow >= srcSize {
+				continue
+			}
+
+			idx := srcRow*srcSize + srcCol
+			r := buffer[idx]
+			g := buffer[idx+plane]
+			b := buffer[idx+2*plane]
+			a := byte(255)
+			if bands == 4 {
+				a = buffer[idx+3*plane]
+			}
+			// 黑色背景转透明
+			if r == 0 && g == 0 && b == 0 {
+				a = 0
+			}
+
+			o := (row*tileSize + col) * 4
+			rgbaImg.Pix[o] = r
+			rgbaImg.Pix[o+1] = g
+			rgbaImg.Pix[o+2] = b
+			rgbaImg.Pix[o+3] = a
+		}
+	}
+
+	var buf bytes.Buffer
+	if err := png.Encode(&buf, rgbaImg); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
@@ -0,0 +1,755 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+/*
+#include "osgeo_utils.h"
+*/
+import "C"
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"math"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unsafe"
+
+	"github.com/jackc/pgx/v5"
+	"github.com/jackc/pgx/v5/pgconn"
+	"github.com/jackc/pgx/v5/stdlib"
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// PostgreSQL COPY 批量写入
+// 以 COPY ... FROM STDIN (FORMAT binary) 流式写入要素，几何以EWKB传输；
+// 数据写完后再创建主键和空间索引。需要 gorm.io/driver/postgres（pgx）驱动。
+// ============================================================================
+
+// PGCopyMode COPY写入模式
+type PGCopyMode int
+
+const (
+	PGCopyCreate  PGCopyMode = iota // 新建表，表已存在时报错
+	PGCopyReplace                   // 写入临时表并建好索引后，在一个事务中替换原表
+	PGCopyAppend                    // 追加到已有表（不存在时新建），列按名称对应并转换为目标列类型
+)
+
+// PGCopyOptions COPY写入选项
+type PGCopyOptions struct {
+	Schema             string                  // 默认public
+	SRID               int                     // 目标坐标系，默认4490，源图层坐标系不同时自动转换
+	GeometryColumn     string                  // 几何列名，默认geom
+	IDColumn           string                  // 自增主键列名，默认id
+	Mode               PGCopyMode              // 写入模式
+	SingleGeometryType bool                    // 保持单部件几何类型（默认提升为Multi类型，避免单部件与多部件混合时写入失败）
+	DropDependents     bool                    // 替换模式下一并删除依赖原表的视图、外键等对象（DROP ... CASCADE），默认存在依赖时报错
+	ProgressInterval   int                     // 进度回调间隔（要素数），默认100000
+	Progress           func(stats PGCopyStats) // 进度回调
+}
+
+// PGCopyStats COPY写入统计
+type PGCopyStats struct {
+	Features      int64         // 写入的要素数
+	Skipped       int64         // 因几何处理失败跳过的要素数
+	Bytes         int64         // 传输的数据量（字节）
+	CopyDuration  time.Duration // COPY耗时
+	IndexDuration time.Duration // 建索引及替换耗时
+	TotalDuration time.Duration // 总耗时
+}
+
+// FeaturesPerSecond COPY阶段每秒写入的要素数
+func (s PGCopyStats) FeaturesPerSecond() float64 {
+	if s.CopyDuration <= 0 {
+		return 0
+	}
+	return float64(s.Features) / s.CopyDuration.Seconds()
+}
+
+// MBPerSecond COPY阶段每秒传输的数据量（MB）
+func (s PGCopyStats) MBPerSecond() float64 {
+	if s.CopyDuration <= 0 {
+		return 0
+	}
+	return float64(s.Bytes) / 1024 / 1024 / s.CopyDuration.Seconds()
+}
+
+// String 统计摘要
+func (s PGCopyStats) String() string {
+	return fmt.Sprintf("写入 %d 个要素（跳过 %d），%.1f MB，COPY耗时 %s（%.0f 要素/秒，%.1f MB/秒），索引耗时 %s",
+		s.Features, s.Skipped, float64(s.Bytes)/1024/1024, s.CopyDuration.Round(time.Millisecond),
+		s.FeaturesPerSecond(), s.MBPerSecond(), s.IndexDuration.Round(time.Millisecond))
+}
+
+// pgCopyColumn 属性列与源字段的对应关系
+type pgCopyColumn struct {
+	name        string
+	sourceIndex C.int
+	fieldType   C.OGRFieldType
+	dbType      string
+}
+
+// CopyGDALLayerToPG 使用COPY协议将图层写入PostgreSQL
+func CopyGDALLayerToPG(DB *gorm.DB, gdalLayer *GDALLayer, tableName string, options *PGCopyOptions) (*PGCopyStats, error) {
+	if gdalLayer == nil || gdalLayer.layer == nil {
+		return nil, fmt.Errorf("无效的GDALLayer")
+	}
+	if tableName == "" {
+		return nil, fmt.Errorf("表名为空")
+	}
+
+	opts := PGCopyOptions{}
+	if options != nil {
+		opts = *options
+	}
+	if opts.Schema == "" {
+		opts.Schema = "public"
+	}
+	if opts.SRID == 0 {
+		opts.SRID = 4490
+	}
+	if opts.GeometryColumn == "" {
+		opts.GeometryColumn = "geom"
+	}
+	if opts.IDColumn == "" {
+		opts.IDColumn = "id"
+	}
+	if opts.ProgressInterval <= 0 {
+		opts.ProgressInterval = 100000
+	}
+
+	stats := &PGCopyStats{}
+	err := withPgxConn(DB, func(ctx context.Context, conn *pgx.Conn) error {
+		return copyLayerWithConn(ctx, conn, gdalLayer, tableName, &opts, stats)
+	})
+	if err != nil {
+		return stats, err
+	}
+
+	log.Printf("成功将图层数据写入表 %s.%s: %s", opts.Schema, tableName, stats)
+	return stats, nil
+}
+
+// copyLayerWithConn 在pgx连接上完成建表、COPY、建索引和替换
+func copyLayerWithConn(ctx context.Context, conn *pgx.Conn, gdalLayer *GDALLayer, tableName string, opts *PGCopyOptions, stats *PGCopyStats) error {
+	start := time.Now()
+	defer func() { stats.TotalDuration = time.Since(start) }()
+
+	exists, err := pgTableExists(ctx, conn, opts.Schema, tableName)
+	if err != nil {
+		return err
+	}
+	if exists && opts.Mode == PGCopyCreate {
+		return fmt.Errorf("表已存在: %s.%s", opts.Schema, tableName)
+	}
+
+	defn := gdalLayer.GetLayerDefn()
+	columns := pgCopyColumns(defn, opts)
+	geomType := pgCopyGeometryType(C.OGR_FD_GetGeomType(defn), opts.SingleGeometryType)
+	hasZ := C.OGR_GT_HasZ(C.OGR_FD_GetGeomType(defn)) != 0
+
+	// 确定写入的表：替换模式写入临时表
+	loadTable := tableName
+	createTable := !exists
+	switch {
+	case opts.Mode == PGCopyReplace:
+		loadTable = pgStagingTableName(tableName)
+		createTable = true
+	case opts.Mode == PGCopyAppend && exists:
+		return pgCopyAppendExisting(ctx, conn, gdalLayer, tableName, columns, opts, stats)
+	}
+	qualified := quoteIdentifier(opts.Schema) + "." + quoteIdentifier(loadTable)
+
+	if createTable {
+		if _, err := conn.Exec(ctx, pgCopyCreateTableSQL(qualified, columns, geomType, hasZ, opts)); err != nil {
+			return fmt.Errorf("创建表失败: %v", err)
+		}
+	}
+	dropStaging := func() {
+		if loadTable != tableName {
+			conn.Exec(ctx, "DROP TABLE IF EXISTS "+qualified)
+		}
+	}
+
+	if err := pgCopyLayerRows(ctx, conn, gdalLayer, qualified, columns, opts, hasZ, stats); err != nil {
+		dropStaging()
+		return err
+	}
+
+	// 建索引并替换
+	indexStart := time.Now()
+	defer func() { stats.IndexDuration = time.Since(indexStart) }()
+
+	if createTable {
+		for _, statement := range pgCopyIndexSQL(opts.Schema, loadTable, qualified, opts) {
+			if _, err := conn.Exec(ctx, statement); err != nil {
+				dropStaging()
+				return fmt.Errorf("创建索引失败: %v", err)
+			}
+		}
+	}
+	if loadTable != tableName {
+		if err := pgSwapStagingTable(ctx, conn, opts, loadTable, tableName); err != nil {
+			dropStaging()
+			return err
+		}
+		qualified = quoteIdentifier(opts.Schema) + "." + quoteIdentifier(tableName)
+	}
+	if _, err := conn.Exec(ctx, "ANALYZE "+qualified); err != nil {
+		log.Printf("ANALYZE %s 失败: %v", qualified, err)
+	}
+	return nil
+}
+
+// withPgxConn 从gorm连接池取出一个连接，以pgx连接执行fn
+func withPgxConn(DB *gorm.DB, fn func(ctx context.Context, conn *pgx.Conn) error) error {
+	sqlDB, err := DB.DB()
+	if err != nil {
+		return fmt.Errorf("获取数据库连接失败: %v", err)
+	}
+
+	ctx := context.Background()
+	conn, err := sqlDB.Conn(ctx)
+	if err != nil {
+		return fmt.Errorf("获取数据库连接失败: %v", err)
+	}
+	defer conn.Close()
+
+	return conn.Raw(func(driverConn interface{}) error {
+		stdConn, ok := driverConn.(*stdlib.Conn)
+		if !ok {
+			return fmt.Errorf("COPY写入需要pgx驱动（gorm.io/driver/postgres），当前驱动: %T", driverConn)
+		}
+		return fn(ctx, stdConn.Conn())
+	})
+}
+
+// pgCopyLayerRows 以二进制COPY将图层要素写入qualified表的columns列和几何列
+func pgCopyLayerRows(ctx context.Context, conn *pgx.Conn, gdalLayer *GDALLayer, qualified string, columns []pgCopyColumn, opts *PGCopyOptions, hasZ bool, stats *PGCopyStats) error {
+	reader, err := newPGCopyReader(gdalLayer, columns, opts, hasZ, stats)
+	if err != nil {
+		return err
+	}
+	defer reader.close()
+
+	columnNames := make([]string, 0, len(columns)+1)
+	for _, column := range columns {
+		columnNames = append(columnNames, quoteIdentifier(column.name))
+	}
+	columnNames = append(columnNames, quoteIdentifier(opts.GeometryColumn))
+	copySQL := fmt.Sprintf("COPY %s (%s) FROM STDIN (FORMAT binary)", qualified, strings.Join(columnNames, ", "))
+
+	copyStart := time.Now()
+	if _, err := conn.PgConn().CopyFrom(ctx, reader, copySQL); err != nil {
+		return fmt.Errorf("COPY写入失败（已写入 %d 个要素）: %v", stats.Features, err)
+	}
+	stats.CopyDuration = time.Since(copyStart)
+	return nil
+}
+
+// pgTableExists 检查表是否存在
+func pgTableExists(ctx context.Context, conn *pgx.Conn, schema, table string) (bool, error) {
+	var exists bool
+	err := conn.QueryRow(ctx,
+		`SELECT EXISTS (SELECT 1 FROM information_schema.tables WHERE table_schema = $1 AND table_name = $2)`,
+		schema, table).Scan(&exists)
+	if err != nil {
+		return false, fmt.Errorf("检查表存在性失败: %v", err)
+	}
+	return exists, nil
+}
+
+// pgCopyAppendExisting 追加到已有表
+// 目标表的列类型（NUMERIC、varchar(n)、单部件几何等）可能与COPY的二进制格式不一致，
+// 因此先COPY到会话临时表，再转换为目标列类型后 INSERT ... SELECT。
+func pgCopyAppendExisting(ctx context.Context, conn *pgx.Conn, gdalLayer *GDALLayer, tableName string, columns []pgCopyColumn, opts *PGCopyOptions, stats *PGCopyStats) error {
+	qualified := quoteIdentifier(opts.Schema) + "." + quoteIdentifier(tableName)
+	targetColumns, err := pgTargetColumns(ctx, conn, qualified)
+	if err != nil {
+		return err
+	}
+	geomColumn, ok := targetColumns[opts.GeometryColumn]
+	if !ok {
+		return fmt.Errorf("目标表 %s.%s 中没有几何列 %s", opts.Schema, tableName, opts.GeometryColumn)
+	}
+
+	kept := make([]pgCopyColumn, 0, len(columns))
+	for _, column := range columns {
+		if _, exists := targetColumns[column.name]; exists {
+			kept = append(kept, column)
+		} else {
+			log.Printf("目标表 %s.%s 中没有列 %s，已忽略", opts.Schema, tableName, column.name)
+		}
+	}
+
+	// 几何的坐标系、单/多部件类型和维度以目标列为准
+	copyOpts := *opts
+	hasZ := C.OGR_GT_HasZ(C.OGR_FD_GetGeomType(gdalLayer.GetLayerDefn())) != 0
+	if typeName, z, srid, typed := parsePGGeometryType(geomColumn.sqlType); typed {
+		copyOpts.SingleGeometryType = !strings.HasPrefix(strings.ToUpper(typeName), "MULTI")
+		hasZ = z
+		if srid > 0 {
+			copyOpts.SRID = srid
+		}
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("开始事务失败: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	staging := quoteIdentifier("gogeo_copy_staging")
+	definitions := make([]string, 0, len(kept)+1)
+	for _, column := range kept {
+		definitions = append(definitions, fmt.Sprintf("%s %s", quoteIdentifier(column.name), column.dbType))
+	}
+	definitions = append(definitions, fmt.Sprintf("%s GEOMETRY", quoteIdentifier(opts.GeometryColumn)))
+	if _, err := tx.Exec(ctx, fmt.Sprintf("CREATE TEMP TABLE %s (%s) ON COMMIT DROP", staging, strings.Join(definitions, ", "))); err != nil {
+		return fmt.Errorf("创建临时表失败: %v", err)
+	}
+	if err := pgCopyLayerRows(ctx, conn, gdalLayer, staging, kept, &copyOpts, hasZ, stats); err != nil {
+		return err
+	}
+	if err := pgCheckValueLengths(ctx, tx, staging, kept, targetColumns); err != nil {
+		return err
+	}
+
+	indexStart := time.Now()
+	defer func() { stats.IndexDuration = time.Since(indexStart) }()
+
+	names := make([]string, 0, len(kept)+1)
+	values := make([]string, 0, len(kept)+1)
+	for _, column := range append(kept, pgCopyColumn{name: opts.GeometryColumn}) {
+		names = append(names, quoteIdentifier(column.name))
+		values = append(values, fmt.Sprintf("%s::%s", quoteIdentifier(column.name), targetColumns[column.name].sqlType))
+	}
+	if _, err := tx.Exec(ctx, fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s",
+		qualified, strings.Join(names, ", "), strings.Join(values, ", "), staging)); err != nil {
+		return fmt.Errorf("写入目标表失败: %v", err)
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("提交事务失败: %v", err)
+	}
+
+	if _, err := conn.Exec(ctx, "ANALYZE "+qualified); err != nil {
+		log.Printf("ANALYZE %s 失败: %v", qualified, err)
+	}
+	return nil
+}
+
+var pgLengthTypePattern = regexp.MustCompile(`^(character varying|character|varchar|char|bit varying|bit)\((\d+)\)$`)
+
+// pgCheckValueLengths 检查源数据是否超过目标列的长度限制
+// 显式转换为 varchar(n)/char(n) 时PostgreSQL会静默截断，写入前先报错以免丢失数据。
+func pgCheckValueLengths(ctx context.Context, tx pgx.Tx, staging string, columns []pgCopyColumn, targetColumns map[string]pgTargetColumn) error {
+	for _, column := range columns {
+		match := pgLengthTypePattern.FindStringSubmatch(targetColumns[column.name].sqlType)
+		if match == nil {
+			continue
+		}
+		limit, _ := strconv.Atoi(match[2])
+
+		var count, maxLength int64
+		err := tx.QueryRow(ctx, fmt.Sprintf("SELECT count(*), coalesce(max(char_length(%s::text)), 0) FROM %s WHERE char_length(%s::text) > %d",
+			quoteIdentifier(column.name), staging, quoteIdentifier(column.name), limit)).Scan(&count, &maxLength)
+		if err != nil {
+			return fmt.Errorf("检查列 %s 长度失败: %v", column.name, err)
+		}
+		if count > 0 {
+			return fmt.Errorf("列 %s 有 %d 个值超过目标类型 %s 的长度（最长 %d），请先加宽该列",
+				column.name, count, targetColumns[column.name].sqlType, maxLength)
+		}
+	}
+	return nil
+}
+
+// pgCopyColumns 根据图层字段生成列定义，列名统一为小写，跳过与主键列、几何列同名的字段
+func pgCopyColumns(defn C.OGRFeatureDefnH, opts *PGCopyOptions) []pgCopyColumn {
+	reserved := map[string]bool{
+		strings.ToLower(opts.IDColumn):       true,
+		strings.ToLower(opts.GeometryColumn): true,
+	}
+
+	fieldCount := int(C.OGR_FD_GetFieldCount(defn))
+	columns := make([]pgCopyColumn, 0, fieldCount)
+	for i := 0; i < fieldCount; i++ {
+		fieldDefn := C.OGR_FD_GetFieldDefn(defn, C.int(i))
+		name := strings.ToLower(C.GoString(C.OGR_Fld_GetNameRef(fieldDefn)))
+		if name == "" || reserved[name] {
+			continue
+		}
+		reserved[name] = true
+
+		fieldType := C.OGR_Fld_GetType(fieldDefn)
+		columns = append(columns, pgCopyColumn{
+			name:        name,
+			sourceIndex: C.int(i),
+			fieldType:   fieldType,
+			dbType:      pgCopyColumnType(fieldType),
+		})
+	}
+	return columns
+}
+
+// pgCopyColumnType 字段类型对应的列类型
+// 与 convertFieldTypeToPostgreSQL 不同，实数统一为DOUBLE PRECISION、字符串统一为TEXT，
+// 以便使用二进制COPY格式且不会因字段宽度不足中断整个COPY。
+func pgCopyColumnType(fieldType C.OGRFieldType) string {
+	switch fieldType {
+	case C.OFTInteger:
+		return "INTEGER"
+	case C.OFTInteger64:
+		return "BIGINT"
+	case C.OFTReal:
+		return "DOUBLE PRECISION"
+	case C.OFTDate:
+		return "DATE"
+	case C.OFTTime:
+		return "TIME"
+	case C.OFTDateTime:
+		return "TIMESTAMP"
+	case C.OFTBinary:
+		return "BYTEA"
+	default:
+		return "TEXT"
+	}
+}
+
+// pgCopyGeometryType 几何列类型，默认提升为Multi类型
+func pgCopyGeometryType(layerType C.OGRwkbGeometryType, single bool) string {
+	flat := C.OGR_GT_Flatten(layerType)
+	if !single {
+		switch flat {
+		case C.wkbPoint, C.wkbLineString, C.wkbPolygon:
+			flat = C.OGR_GT_GetCollection(flat)
+		}
+	}
+	return convertOGRGeometryTypeForPG(flat)
+}
+
+// pgCopyCreateTableSQL 建表语句（不含索引和主键约束）
+func pgCopyCreateTableSQL(qualified string, columns []pgCopyColumn, geomType string, hasZ bool, opts *PGCopyOptions) string {
+	definitions := []string{fmt.Sprintf("%s BIGSERIAL", quoteIdentifier(opts.IDColumn))}
+	for _, column := range columns {
+		definitions = append(definitions, fmt.Sprintf("%s %s", quoteIdentifier(column.name), column.dbType))
+	}
+	if hasZ {
+		geomType += "Z"
+	}
+	definitions = append(definitions, fmt.Sprintf("%s GEOMETRY(%s, %d)", quoteIdentifier(opts.GeometryColumn), geomType, opts.SRID))
+	return fmt.Sprintf("CREATE TABLE %s (\n\t%s\n)", qualified, strings.Join(definitions, ",\n\t"))
+}
+
+// pgCopyIndexSQL 数据写入后创建主键和空间索引
+func pgCopyIndexSQL(schema, table, qualified string, opts *PGCopyOptions) []string {
+	return []string{
+		fmt.Sprintf("ALTER TABLE %s ADD CONSTRAINT %s PRIMARY KEY (%s)",
+			qualified, quoteIdentifier(pgIdentifier(table+"_pkey")), quoteIdentifier(opts.IDColumn)),
+		fmt.Sprintf("CREATE INDEX %s ON %s USING GIST (%s)",
+			quoteIdentifier(pgIdentifier("idx_"+table+"_"+opts.GeometryColumn)), qualified, quoteIdentifier(opts.GeometryColumn)),
+	}
+}
+
+// pgSwapStagingTable 在一个事务中删除原表并将临时表及其索引改名
+// 原表存在依赖对象（视图、外键等）时报错，设置 DropDependents 后一并删除。
+func pgSwapStagingTable(ctx context.Context, conn *pgx.Conn, opts *PGCopyOptions, staging, table string) error {
+	schema := quoteIdentifier(opts.Schema)
+	dropTable := fmt.Sprintf("DROP TABLE IF EXISTS %s.%s", schema, quoteIdentifier(table))
+	if opts.DropDependents {
+		dropTable += " CASCADE"
+	}
+	statements := []string{
+		dropTable,
+		fmt.Sprintf("ALTER TABLE %s.%s RENAME TO %s", schema, quoteIdentifier(staging), quoteIdentifier(table)),
+		fmt.Sprintf("ALTER TABLE %s.%s RENAME CONSTRAINT %s TO %s", schema, quoteIdentifier(table),
+			quoteIdentifier(pgIdentifier(staging+"_pkey")), quoteIdentifier(pgIdentifier(table+"_pkey"))),
+		fmt.Sprintf("ALTER INDEX %s.%s RENAME TO %s", schema,
+			quoteIdentifier(pgIdentifier("idx_"+staging+"_"+opts.GeometryColumn)),
+			quoteIdentifier(pgIdentifier("idx_"+table+"_"+opts.GeometryColumn))),
+		fmt.Sprintf("ALTER SEQUENCE IF EXISTS %s.%s RENAME TO %s", schema,
+			quoteIdentifier(pgIdentifier(staging+"_"+opts.IDColumn+"_seq")),
+			quoteIdentifier(pgIdentifier(table+"_"+opts.IDColumn+"_seq"))),
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("开始事务失败: %v", err)
+	}
+	for _, statement := range statements {
+		if _, err := tx.Exec(ctx, statement); err != nil {
+			tx.Rollback(ctx)
+			var pgErr *pgconn.PgError
+			if errors.As(err, &pgErr) && pgErr.Code == "2BP01" {
+				return fmt.Errorf("原表 %s.%s 存在依赖对象，未替换（如需一并删除请设置 DropDependents）: %s",
+					opts.Schema, table, pgErr.Detail)
+			}
+			return fmt.Errorf("替换表失败: %v", err)
+		}
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("提交事务失败: %v", err)
+	}
+	return nil
+}
+
+// pgStagingTableName 临时表名
+func pgStagingTableName(table string) string {
+	return pgIdentifier(fmt.Sprintf("%s_stg%d", table, time.Now().UnixNano()%1e9))
+}
+
+// pgIdentifier 将标识符截断到PostgreSQL的63字节上限（按UTF-8字符边界）
+func pgIdentifier(name string) string {
+	if len(name) <= 63 {
+		return name
+	}
+	cut := 63
+	for cut > 0 && (name[cut]&0xC0) == 0x80 {
+		cut--
+	}
+	return name[:cut]
+}
+
+// ============================================================================
+// 二进制COPY流
+// ============================================================================
+
+var (
+	pgCopySignature = []byte("PGCOPY\n\xff\r\n\x00")
+	pgEpoch         = time.Date(2000, 1, 1, 0, 0, 0, 0, time.UTC)
+)
+
+// pgCopyReader 按需读取要素并编码为二进制COPY数据
+type pgCopyReader struct {
+	layer     *GDALLayer
+	columns   []pgCopyColumn
+	opts      *PGCopyOptions
+	hasZ      bool
+	stats     *PGCopyStats
+	transform C.OGRCoordinateTransformationH
+	targetSRS C.OGRSpatialReferenceH
+	buf       bytes.Buffer
+	finished  bool
+	started   time.Time
+}
+
+func newPGCopyReader(layer *GDALLayer, columns []pgCopyColumn, opts *PGCopyOptions, hasZ bool, stats *PGCopyStats) (*pgCopyReader, error) {
+	r := &pgCopyReader{
+		layer:   layer,
+		columns: columns,
+		opts:    opts,
+		hasZ:    hasZ,
+		stats:   stats,
+	}
+
+	r.targetSRS = C.OSRNewSpatialReference(nil)
+	if C.OSRImportFromEPSG(r.targetSRS, C.int(opts.SRID)) != C.OGRERR_NONE {
+		C.OSRDestroySpatialReference(r.targetSRS)
+		return nil, fmt.Errorf("无法创建目标坐标系 EPSG:%d", opts.SRID)
+	}
+	C.OSRSetAxisMappingStrategy(r.targetSRS, C.OAMS_TRADITIONAL_GIS_ORDER)
+
+	if sourceSRS := layer.GetSpatialRef(); sourceSRS != nil && C.OSRIsSame(sourceSRS, r.targetSRS) == 0 {
+		r.transform = C.OCTNewCoordinateTransformation(sourceSRS, r.targetSRS)
+		if r.transform == nil {
+			r.close()
+			return nil, fmt.Errorf("无法创建到 EPSG:%d 的坐标转换", opts.SRID)
+		}
+	}
+
+	r.buf.Write(pgCopySignature)
+	binary.Write(&r.buf, binary.BigEndian, int32(0)) // 标志位
+	binary.Write(&r.buf, binary.BigEndian, int32(0)) // 头扩展长度
+	layer.ResetReading()
+	r.started = time.Now()
+	return r, nil
+}
+
+func (r *pgCopyReader) close() {
+	if r.transform != nil {
+		C.OCTDestroyCoordinateTransformation(r.transform)
+		r.transform = nil
+	}
+	if r.targetSRS != nil {
+		C.OSRDestroySpatialReference(r.targetSRS)
+		r.targetSRS = nil
+	}
+}
+
+// Read 实现io.Reader，缓冲区为空时编码下一个要素
+func (r *pgCopyReader) Read(p []byte) (int, error) {
+	for r.buf.Len() == 0 {
+		if r.finished {
+			return 0, io.EOF
+		}
+		r.encodeNext()
+	}
+	n, _ := r.buf.Read(p)
+	r.stats.Bytes += int64(n)
+	return n, nil
+}
+
+// encodeNext 编码下一个要素，没有更多要素时写入结束标记
+func (r *pgCopyReader) encodeNext() {
+	for {
+		feature := r.layer.GetNextFeatureRow()
+		if feature == nil {
+			binary.Write(&r.buf, binary.BigEndian, int16(-1))
+			r.finished = true
+			return
+		}
+
+		ewkb, err := r.encodeGeometry(C.OGR_F_GetGeometryRef(feature))
+		if err != nil {
+			r.stats.Skipped++
+			log.Printf("跳过要素 FID=%d: %v", int64(C.OGR_F_GetFID(feature)), err)
+			C.OGR_F_Destroy(feature)
+			continue
+		}
+
+		binary.Write(&r.buf, binary.BigEndian, int16(len(r.columns)+1))
+		for _, column := range r.columns {
+			r.encodeField(feature, column)
+		}
+		writePGCopyValue(&r.buf, ewkb)
+		C.OGR_F_Destroy(feature)
+
+		r.stats.Features++
+		if r.opts.Progress != nil && r.stats.Features%int64(r.opts.ProgressInterval) == 0 {
+			r.stats.CopyDuration = time.Since(r.started)
+			r.opts.Progress(*r.stats)
+		}
+		return
+	}
+}
+
+// encodeField 按列类型编码字段值
+func (r *pgCopyReader) encodeField(feature C.OGRFeatureH, column pgCopyColumn) {
+	index := column.sourceIndex
+	if C.OGR_F_IsFieldSetAndNotNull(feature, index) == 0 {
+		writePGCopyValue(&r.buf, nil)
+		return
+	}
+
+	var value [8]byte
+	switch column.fieldType {
+	case C.OFTInteger:
+		binary.BigEndian.PutUint32(value[:4], uint32(int32(C.OGR_F_GetFieldAsInteger(feature, index))))
+		writePGCopyValue(&r.buf, value[:4])
+	case C.OFTInteger64:
+		binary.BigEndian.PutUint64(value[:], uint64(int64(C.OGR_F_GetFieldAsInteger64(feature, index))))
+		writePGCopyValue(&r.buf, value[:])
+	case C.OFTReal:
+		binary.BigEndian.PutUint64(value[:], math.Float64bits(float64(C.OGR_F_GetFieldAsDouble(feature, index))))
+		writePGCopyValue(&r.buf, value[:])
+	case C.OFTDate, C.OFTTime, C.OFTDateTime:
+		t, ok := featureDateTime(feature, index)
+		if !ok {
+			writePGCopyValue(&r.buf, nil)
+			return
+		}
+		// 按字段中的日期时间原值写入（TIMESTAMP不含时区）
+		// time.Duration只能表示约±292年，按Unix秒计算以支持 0001-01-01、9999-12-31 等日期
+		wall := time.Date(t.Year(), t.Month(), t.Day(), t.Hour(), t.Minute(), t.Second(), t.Nanosecond(), time.UTC)
+		seconds := wall.Unix() - pgEpoch.Unix()
+		switch column.fieldType {
+		case C.OFTDate:
+			binary.BigEndian.PutUint32(value[:4], uint32(int32(seconds/86400)))
+			writePGCopyValue(&r.buf, value[:4])
+		case C.OFTTime:
+			midnight := time.Date(wall.Year(), wall.Month(), wall.Day(), 0, 0, 0, 0, time.UTC)
+			binary.BigEndian.PutUint64(value[:], uint64(wall.Sub(midnight).Microseconds()))
+			writePGCopyValue(&r.buf, value[:])
+		default:
+			binary.BigEndian.PutUint64(value[:], uint64(seconds*1e6+int64(wall.Nanosecond()/1e3)))
+			writePGCopyValue(&r.buf, value[:])
+		}
+	case C.OFTBinary:
+		writePGCopyValue(&r.buf, featureBinary(feature, index))
+	default:
+		text := C.GoString(C.OGR_F_GetFieldAsString(feature, index))
+		writePGCopyValue(&r.buf, []byte(strings.ToValidUTF8(text, "?")))
+	}
+}
+
+// encodeGeometry 几何转换到目标坐标系、调整类型和维度后编码为带SRID的EWKB
+func (r *pgCopyReader) encodeGeometry(geometry C.OGRGeometryH) ([]byte, error) {
+	if geometry == nil {
+		return nil, nil
+	}
+
+	geom := C.OGR_G_Clone(geometry)
+	defer func() { C.OGR_G_DestroyGeometry(geom) }()
+
+	if r.transform != nil && C.OGR_G_Transform(geom, r.transform) != C.OGRERR_NONE {
+		return nil, fmt.Errorf("坐标转换失败")
+	}
+	if !r.opts.SingleGeometryType {
+		switch C.OGR_GT_Flatten(C.OGR_G_GetGeometryType(geom)) {
+		case C.wkbPoint:
+			geom = C.OGR_G_ForceToMultiPoint(geom)
+		case C.wkbLineString:
+			geom = C.OGR_G_ForceToMultiLineString(geom)
+		case C.wkbPolygon:
+			geom = C.OGR_G_ForceToMultiPolygon(geom)
+		}
+	}
+	if r.hasZ {
+		C.OGR_G_Set3D(geom, 1)
+	} else {
+		C.OGR_G_FlattenTo2D(geom)
+	}
+
+	size := int(C.OGR_G_WkbSize(geom))
+	if size <= 0 {
+		return nil, fmt.Errorf("无效的几何对象")
+	}
+	wkb := make([]byte, size)
+	if C.OGR_G_ExportToWkb(geom, C.wkbNDR, (*C.uchar)(unsafe.Pointer(&wkb[0]))) != C.OGRERR_NONE {
+		return nil, fmt.Errorf("导出WKB失败")
+	}
+	return wkbToEWKB(wkb, r.opts.SRID), nil
+}
+
+// wkbToEWKB 在小端WKB的类型码上设置SRID标志并插入SRID
+// OGR导出的三维WKB使用0x80000000标志，与EWKB的Z标志一致。
+func wkbToEWKB(wkb []byte, srid int) []byte {
+	const ewkbSRIDFlag = 0x20000000
+	ewkb := make([]byte, len(wkb)+4)
+	ewkb[0] = wkb[0]
+	binary.LittleEndian.PutUint32(ewkb[1:5], binary.LittleEndian.Uint32(wkb[1:5])|ewkbSRIDFlag)
+	binary.LittleEndian.PutUint32(ewkb[5:9], uint32(srid))
+	copy(ewkb[9:], wkb[5:])
+	return ewkb
+}
+
+// writePGCopyValue 写入一个字段（长度+数据），value为nil时写入NULL
+func writePGCopyValue(buf *bytes.Buffer, value []byte) {
+	if value == nil {
+		binary.Write(buf, binary.BigEndian, int32(-1))
+		return
+	}
+	binary.Write(buf, binary.BigEndian, int32(len(value)))
+	buf.Write(value)
+}
@@ -0,0 +1,350 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+/*
+#include "osgeo_utils.h"
+*/
+import "C"
+import (
+	"context"
+	"fmt"
+	"log"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// PostGIS 增量合并
+// 源图层先以COPY写入会话临时表，再在同一事务中按键字段对目标表执行
+// UPDATE（仅更新有变化的行）、INSERT（新增行）和可选的DELETE（源中已不存在的行）。
+// ============================================================================
+
+// PGMergeOptions 合并选项
+type PGMergeOptions struct {
+	Schema         string   // 默认public
+	KeyFields      []string // 键字段（必填），按小写列名与目标表匹配
+	GeometryColumn string   // 目标表几何列名，默认geom
+	SRID           int      // 目标表几何列未声明SRID时使用，默认4490
+	DeleteMissing  bool     // 删除目标表中源数据里不存在的行
+	DryRun         bool     // 只统计变化，最后回滚事务
+}
+
+// PGMergeResult 合并结果统计
+type PGMergeResult struct {
+	Source    int64         // 源要素数（写入临时表的行数）
+	Skipped   int64         // 几何处理失败跳过的要素数
+	NullKeys  int64         // 键字段为空被忽略的要素数
+	Inserted  int64         // 新增行数
+	Updated   int64         // 更新行数
+	Unchanged int64         // 键匹配且无变化的源要素数（目标表存在重复键时按源键计数）
+	Deleted   int64         // 删除行数
+	DryRun    bool          // 是否为试运行（未提交）
+	Duration  time.Duration // 总耗时
+}
+
+// String 合并结果摘要
+func (r PGMergeResult) String() string {
+	summary := fmt.Sprintf("源要素 %d（跳过 %d，空键 %d）：新增 %d，更新 %d，未变化 %d，删除 %d，耗时 %s",
+		r.Source, r.Skipped, r.NullKeys, r.Inserted, r.Updated, r.Unchanged, r.Deleted, r.Duration.Round(time.Millisecond))
+	if r.DryRun {
+		summary += "（试运行，未提交）"
+	}
+	return summary
+}
+
+// pgTargetColumn 目标表的列及其类型
+type pgTargetColumn struct {
+	name    string
+	sqlType string
+}
+
+// MergeGDALLayerToPG 按键字段将图层合并到已有的PostGIS表，全部修改在一个事务中提交
+// 只合并源图层与目标表同名的列；源数据中的键必须唯一，值超过目标列长度（如varchar(n)）时报错而不截断。
+func MergeGDALLayerToPG(DB *gorm.DB, gdalLayer *GDALLayer, tableName string, options *PGMergeOptions) (*PGMergeResult, error) {
+	if gdalLayer == nil || gdalLayer.layer == nil {
+		return nil, fmt.Errorf("无效的GDALLayer")
+	}
+	if tableName == "" {
+		return nil, fmt.Errorf("表名为空")
+	}
+	if options == nil || len(options.KeyFields) == 0 {
+		return nil, fmt.Errorf("未指定键字段")
+	}
+
+	opts := *options
+	if opts.Schema == "" {
+		opts.Schema = "public"
+	}
+	if opts.GeometryColumn == "" {
+		opts.GeometryColumn = "geom"
+	}
+	opts.GeometryColumn = strings.ToLower(opts.GeometryColumn)
+
+	result := &PGMergeResult{DryRun: opts.DryRun}
+	start := time.Now()
+	err := withPgxConn(DB, func(ctx context.Context, conn *pgx.Conn) error {
+		return mergeLayerWithConn(ctx, conn, gdalLayer, tableName, &opts, result)
+	})
+	result.Duration = time.Since(start)
+	if err != nil {
+		return result, err
+	}
+
+	log.Printf("合并图层到表 %s.%s: %s", opts.Schema, tableName, result)
+	return result, nil
+}
+
+// mergeLayerWithConn 在pgx连接上完成临时表加载和合并
+func mergeLayerWithConn(ctx context.Context, conn *pgx.Conn, gdalLayer *GDALLayer, tableName string, opts *PGMergeOptions, result *PGMergeResult) error {
+	qualified := quoteIdentifier(opts.Schema) + "." + quoteIdentifier(tableName)
+	targetColumns, err := pgTargetColumns(ctx, conn, qualified)
+	if err != nil {
+		return err
+	}
+
+	geomColumn, ok := targetColumns[opts.GeometryColumn]
+	if !ok {
+		return fmt.Errorf("目标表 %s.%s 中没有几何列 %s", opts.Schema, tableName, opts.GeometryColumn)
+	}
+
+	// 源列与目标列按名称对应
+	defn := gdalLayer.GetLayerDefn()
+	copyOpts := &PGCopyOptions{GeometryColumn: opts.GeometryColumn}
+	columns := make([]pgCopyColumn, 0)
+	for _, column := range pgCopyColumns(defn, copyOpts) {
+		if _, exists := targetColumns[column.name]; exists {
+			columns = append(columns, column)
+		}
+	}
+
+	keys := make([]string, 0, len(opts.KeyFields))
+	for _, key := range opts.KeyFields {
+		key = strings.ToLower(key)
+		if !pgHasColumn(columns, key) {
+			return fmt.Errorf("键字段 %s 不在源图层与目标表的公共列中", key)
+		}
+		keys = append(keys, key)
+	}
+
+	// 几何列的坐标系、单/多部件类型和维度以目标表为准
+	hasZ := C.OGR_GT_HasZ(C.OGR_FD_GetGeomType(defn)) != 0
+	copyOpts.SingleGeometryType = true
+	copyOpts.SRID = opts.SRID
+	if copyOpts.SRID == 0 {
+		copyOpts.SRID = 4490
+	}
+	if typeName, z, srid, typed := parsePGGeometryType(geomColumn.sqlType); typed {
+		copyOpts.SingleGeometryType = !strings.HasPrefix(strings.ToUpper(typeName), "MULTI")
+		hasZ = z
+		if srid > 0 {
+			copyOpts.SRID = srid
+		}
+	}
+
+	tx, err := conn.Begin(ctx)
+	if err != nil {
+		return fmt.Errorf("开始事务失败: %v", err)
+	}
+	defer tx.Rollback(ctx)
+
+	// 加载到临时表
+	staging := quoteIdentifier("gogeo_merge_staging")
+	definitions := make([]string, 0, len(columns)+1)
+	for _, column := range columns {
+		definitions = append(definitions, fmt.Sprintf("%s %s", quoteIdentifier(column.name), column.dbType))
+	}
+	definitions = append(definitions, fmt.Sprintf("%s GEOMETRY", quoteIdentifier(opts.GeometryColumn)))
+	if _, err := tx.Exec(ctx, fmt.Sprintf("CREATE TEMP TABLE %s (%s) ON COMMIT DROP", staging, strings.Join(definitions, ", "))); err != nil {
+		return fmt.Errorf("创建临时表失败: %v", err)
+	}
+
+	stats := &PGCopyStats{}
+	if err := pgCopyLayerRows(ctx, conn, gdalLayer, staging, columns, copyOpts, hasZ, stats); err != nil {
+		return err
+	}
+	result.Source = stats.Features
+	result.Skipped = stats.Skipped
+
+	// 忽略空键，检查重复键
+	nullConditions := make([]string, len(keys))
+	for i, key := range keys {
+		nullConditions[i] = quoteIdentifier(key) + " IS NULL"
+	}
+	tag, err := tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s WHERE %s", staging, strings.Join(nullConditions, " OR ")))
+	if err != nil {
+		return fmt.Errorf("清理空键失败: %v", err)
+	}
+	result.NullKeys = tag.RowsAffected()
+
+	// 转换为varchar(n)等定长类型时超长值会被截断，先检查并报错
+	if err := pgCheckValueLengths(ctx, tx, staging, columns, targetColumns); err != nil {
+		return err
+	}
+
+	quotedKeys := make([]string, len(keys))
+	for i, key := range keys {
+		quotedKeys[i] = quoteIdentifier(key)
+	}
+	var duplicate string
+	err = tx.QueryRow(ctx, fmt.Sprintf("SELECT concat_ws(', ', %s) FROM %s GROUP BY %s HAVING count(*) > 1 LIMIT 1",
+		strings.Join(quotedKeys, ", "), staging, strings.Join(quotedKeys, ", "))).Scan(&duplicate)
+	if err == nil {
+		return fmt.Errorf("源数据中存在重复键: %s", duplicate)
+	}
+	if err != pgx.ErrNoRows {
+		return fmt.Errorf("检查重复键失败: %v", err)
+	}
+	if _, err := tx.Exec(ctx, "ANALYZE "+staging); err != nil {
+		return fmt.Errorf("ANALYZE临时表失败: %v", err)
+	}
+
+	// 生成合并语句，源列转换为目标列类型后再比较和写入
+	source := func(name string) string {
+		return fmt.Sprintf("s.%s::%s", quoteIdentifier(name), targetColumns[name].sqlType)
+	}
+	keyConditions := make([]string, len(keys))
+	for i, key := range keys {
+		keyConditions[i] = fmt.Sprintf("t.%s = %s", quoteIdentifier(key), source(key))
+	}
+	keyMatch := strings.Join(keyConditions, " AND ")
+
+	var assignments, targetValues, sourceValues, insertColumns, insertValues []string
+	for _, column := range columns {
+		name := quoteIdentifier(column.name)
+		insertColumns = append(insertColumns, name)
+		insertValues = append(insertValues, source(column.name))
+		if pgHasKey(keys, column.name) {
+			continue
+		}
+		assignments = append(assignments, fmt.Sprintf("%s = %s", name, source(column.name)))
+		targetValues = append(targetValues, "t."+name)
+		sourceValues = append(sourceValues, source(column.name))
+	}
+	geomName := quoteIdentifier(opts.GeometryColumn)
+	assignments = append(assignments, fmt.Sprintf("%s = %s", geomName, source(opts.GeometryColumn)))
+	targetValues = append(targetValues, fmt.Sprintf("ST_AsEWKB(t.%s)", geomName))
+	sourceValues = append(sourceValues, fmt.Sprintf("ST_AsEWKB(%s)", source(opts.GeometryColumn)))
+	insertColumns = append(insertColumns, geomName)
+	insertValues = append(insertValues, source(opts.GeometryColumn))
+
+	// 按源键统计未变化数：目标表中同键的行都与源数据一致
+	valuesDiffer := fmt.Sprintf("(%s) IS DISTINCT FROM (%s)", strings.Join(targetValues, ", "), strings.Join(sourceValues, ", "))
+	err = tx.QueryRow(ctx, fmt.Sprintf(
+		"SELECT count(*) FROM %s s WHERE EXISTS (SELECT 1 FROM %s t WHERE %s) AND NOT EXISTS (SELECT 1 FROM %s t WHERE %s AND %s)",
+		staging, qualified, keyMatch, qualified, keyMatch, valuesDiffer)).Scan(&result.Unchanged)
+	if err != nil {
+		return fmt.Errorf("匹配键失败: %v", err)
+	}
+
+	tag, err = tx.Exec(ctx, fmt.Sprintf("UPDATE %s AS t SET %s FROM %s s WHERE %s AND %s",
+		qualified, strings.Join(assignments, ", "), staging, keyMatch, valuesDiffer))
+	if err != nil {
+		return fmt.Errorf("更新要素失败: %v", err)
+	}
+	result.Updated = tag.RowsAffected()
+
+	tag, err = tx.Exec(ctx, fmt.Sprintf("INSERT INTO %s (%s) SELECT %s FROM %s s WHERE NOT EXISTS (SELECT 1 FROM %s t WHERE %s)",
+		qualified, strings.Join(insertColumns, ", "), strings.Join(insertValues, ", "), staging, qualified, keyMatch))
+	if err != nil {
+		return fmt.Errorf("新增要素失败: %v", err)
+	}
+	result.Inserted = tag.RowsAffected()
+
+	if opts.DeleteMissing {
+		tag, err = tx.Exec(ctx, fmt.Sprintf("DELETE FROM %s t WHERE NOT EXISTS (SELECT 1 FROM %s s WHERE %s)",
+			qualified, staging, keyMatch))
+		if err != nil {
+			return fmt.Errorf("删除要素失败: %v", err)
+		}
+		result.Deleted = tag.RowsAffected()
+	}
+
+	if opts.DryRun {
+		return nil
+	}
+	if err := tx.Commit(ctx); err != nil {
+		return fmt.Errorf("提交事务失败: %v", err)
+	}
+	return nil
+}
+
+// pgTargetColumns 查询表的列名及完整类型（含长度、精度和几何类型修饰）
+func pgTargetColumns(ctx context.Context, conn *pgx.Conn, qualified string) (map[string]pgTargetColumn, error) {
+	rows, err := conn.Query(ctx,
+		`SELECT attname, format_type(atttypid, atttypmod) FROM pg_attribute
+		 WHERE attrelid = to_regclass($1) AND attnum > 0 AND NOT attisdropped`, qualified)
+	if err != nil {
+		return nil, fmt.Errorf("查询表结构失败: %v", err)
+	}
+	defer rows.Close()
+
+	columns := make(map[string]pgTargetColumn)
+	for rows.Next() {
+		var column pgTargetColumn
+		if err := rows.Scan(&column.name, &column.sqlType); err != nil {
+			return nil, fmt.Errorf("查询表结构失败: %v", err)
+		}
+		columns[column.name] = column
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("查询表结构失败: %v", err)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("目标表不存在: %s", qualified)
+	}
+	return columns, nil
+}
+
+var pgGeometryTypePattern = regexp.MustCompile(`(?i)^geometry\((\w+?)(ZM|Z|M)?(?:,\s*(\d+))?\)$`)
+
+// parsePGGeometryType 解析 geometry(MultiPolygonZ,4490) 形式的列类型
+// 返回几何类型名、是否含Z、SRID；无类型修饰时typed为false
+func parsePGGeometryType(sqlType string) (typeName string, hasZ bool, srid int, typed bool) {
+	match := pgGeometryTypePattern.FindStringSubmatch(sqlType)
+	if match == nil {
+		return "", false, 0, false
+	}
+	dims := strings.ToUpper(match[2])
+	if match[3] != "" {
+		srid, _ = strconv.Atoi(match[3])
+	}
+	return match[1], dims == "Z" || dims == "ZM", srid, true
+}
+
+func pgHasColumn(columns []pgCopyColumn, name string) bool {
+	for _, column := range columns {
+		if column.name == name {
+			return true
+		}
+	}
+	return false
+}
+
+func pgHasKey(keys []string, name string) bool {
+	for _, key := range keys {
+		if key == name {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,179 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+import (
+	"context"
+	"strings"
+	"testing"
+
+	"github.com/jackc/pgx/v5"
+)
+
+// 源数据：A 与目标一致，B 名称变化，C 为新增
+const pgMergeTestFeatures = `{"type": "FeatureCollection", "features": [
+{"type": "Feature", "properties": {"code": "A", "name": "甲", "value": 1.5}, "geometry": {"type": "Point", "coordinates": [116.1, 39.1]}},
+{"type": "Feature", "properties": {"code": "B", "name": "乙二", "value": 2.5}, "geometry": {"type": "Point", "coordinates": [116.2, 39.2]}},
+{"type": "Feature", "properties": {"code": "C", "name": "丙", "value": 3.5}, "geometry": {"type": "Point", "coordinates": [116.3, 39.3]}}
+]}`
+
+// pgMergeTest 合并测试的目标表
+type pgMergeTest struct {
+	t     *testing.T
+	ctx   context.Context
+	conn  *pgx.Conn
+	table string
+}
+
+// newPGMergeTest 创建目标表并写入 A、B、D 三行
+func newPGMergeTest(t *testing.T, name string) *pgMergeTest {
+	t.Helper()
+	ctx, conn := testPGConn(t)
+	m := &pgMergeTest{t: t, ctx: ctx, conn: conn, table: testPGTable(t, ctx, conn, name)}
+	m.exec("CREATE TABLE " + m.table + " (id SERIAL PRIMARY KEY, code VARCHAR(10), name TEXT, value NUMERIC(10,2), geom GEOMETRY(Point, 4326))")
+	m.exec("INSERT INTO " + m.table + ` (code, name, value, geom) VALUES
+		('A', '甲', 1.5, ST_SetSRID(ST_MakePoint(116.1, 39.1), 4326)),
+		('B', '乙', 2.5, ST_SetSRID(ST_MakePoint(116.2, 39.2), 4326)),
+		('D', '丁', 4.5, ST_SetSRID(ST_MakePoint(116.4, 39.4), 4326))`)
+	return m
+}
+
+func (m *pgMergeTest) options() *PGMergeOptions {
+	return &PGMergeOptions{Schema: "public", KeyFields: []string{"code"}, GeometryColumn: "geom", SRID: 4326}
+}
+
+func (m *pgMergeTest) merge(opts *PGMergeOptions, features string) (*PGMergeResult, error) {
+	result := &PGMergeResult{DryRun: opts.DryRun}
+	err := mergeLayerWithConn(m.ctx, m.conn, testGeoJSONLayer(m.t, features), m.table, opts, result)
+	return result, err
+}
+
+func (m *pgMergeTest) exec(statement string) {
+	m.t.Helper()
+	if _, err := m.conn.Exec(m.ctx, statement); err != nil {
+		m.t.Fatalf("执行失败 %q: %v", statement, err)
+	}
+}
+
+func (m *pgMergeTest) count(where string) int64 {
+	m.t.Helper()
+	return testPGCount(m.t, m.ctx, m.conn, "SELECT count(*) FROM "+m.table+" WHERE "+where)
+}
+
+func TestPGMergeUpsert(t *testing.T) {
+	m := newPGMergeTest(t, "gogeo_merge_upsert")
+	opts := m.options()
+
+	result, err := m.merge(opts, pgMergeTestFeatures)
+	if err != nil {
+		t.Fatalf("合并失败: %v", err)
+	}
+	if result.Source != 3 || result.Inserted != 1 || result.Updated != 1 || result.Unchanged != 1 || result.Deleted != 0 {
+		t.Fatalf("合并结果不正确: %s", result)
+	}
+	if n := m.count("true"); n != 4 {
+		t.Fatalf("期望4行，实际 %d", n)
+	}
+	if n := m.count("code = 'B' AND name = '乙二'"); n != 1 {
+		t.Fatal("B 未更新")
+	}
+	if n := m.count("code = 'C' AND ST_SRID(geom) = 4326"); n != 1 {
+		t.Fatal("C 未插入")
+	}
+
+	// 再次合并时全部未变化
+	result, err = m.merge(opts, pgMergeTestFeatures)
+	if err != nil {
+		t.Fatalf("再次合并失败: %v", err)
+	}
+	if result.Inserted != 0 || result.Updated != 0 || result.Unchanged != 3 {
+		t.Fatalf("再次合并结果不正确: %s", result)
+	}
+}
+
+func TestPGMergeDeleteMissing(t *testing.T) {
+	m := newPGMergeTest(t, "gogeo_merge_delete")
+	opts := m.options()
+
+	opts.DeleteMissing = true
+	result, err := m.merge(opts, pgMergeTestFeatures)
+	if err != nil {
+		t.Fatalf("合并失败: %v", err)
+	}
+	if result.Inserted != 1 || result.Updated != 1 || result.Unchanged != 1 || result.Deleted != 1 {
+		t.Fatalf("合并结果不正确: %s", result)
+	}
+	if n := m.count("true"); n != 3 {
+		t.Fatalf("期望3行，实际 %d", n)
+	}
+	if n := m.count("code = 'D'"); n != 0 {
+		t.Fatal("源数据中不存在的 D 应被删除")
+	}
+}
+
+func TestPGMergeDryRun(t *testing.T) {
+	m := newPGMergeTest(t, "gogeo_merge_dryrun")
+	opts := m.options()
+
+	opts.DryRun = true
+	opts.DeleteMissing = true
+	result, err := m.merge(opts, pgMergeTestFeatures)
+	if err != nil {
+		t.Fatalf("试运行失败: %v", err)
+	}
+	if !result.DryRun || result.Inserted != 1 || result.Updated != 1 || result.Unchanged != 1 || result.Deleted != 1 {
+		t.Fatalf("试运行结果不正确: %s", result)
+	}
+
+	// 试运行不修改目标表
+	if n := m.count("true"); n != 3 {
+		t.Fatalf("期望保持3行，实际 %d", n)
+	}
+	if n := m.count("code = 'B' AND name = '乙'"); n != 1 {
+		t.Fatal("试运行不应更新 B")
+	}
+	if n := m.count("code = 'C'"); n != 0 {
+		t.Fatal("试运行不应插入 C")
+	}
+}
+
+func TestPGMergeDuplicateKeys(t *testing.T) {
+	m := newPGMergeTest(t, "gogeo_merge_duplicate")
+	opts := m.options()
+
+	// 源数据中键重复时报错
+	_, err := m.merge(opts, strings.Replace(pgMergeTestFeatures, `"code": "C"`, `"code": "A"`, 1))
+	if err == nil || !strings.Contains(err.Error(), "重复键") {
+		t.Fatalf("源数据重复键应报错，实际: %v", err)
+	}
+	if n := m.count("true"); n != 3 {
+		t.Fatalf("失败的合并不应修改目标表，实际 %d 行", n)
+	}
+
+	// 目标表中键重复时按源键计数
+	m.exec("INSERT INTO gogeo_merge_duplicate (code, name, value, geom) SELECT code, name, value, geom FROM gogeo_merge_duplicate WHERE code IN ('A', 'B')")
+	result, err := m.merge(opts, pgMergeTestFeatures)
+	if err != nil {
+		t.Fatalf("合并失败: %v", err)
+	}
+	if result.Inserted != 1 || result.Updated != 2 || result.Unchanged != 1 {
+		t.Fatalf("合并结果不正确: %s", result)
+	}
+	if n := m.count("code = 'B' AND name = '乙二'"); n != 2 {
+		t.Fatalf("B 的两行都应更新，实际 %d", n)
+	}
+}
@@ -0,0 +1,292 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+/*
+#include "osgeo_utils.h"
+*/
+import "C"
+import (
+	"fmt"
+	"runtime"
+	"strconv"
+	"strings"
+	"unsafe"
+)
+
+// ============================================================================
+// PostGIS 流式读取
+// 查询在服务端游标上执行，每次只取回 PageSize 行，内存占用与表的大小无关。
+// ============================================================================
+
+// PostGISQuery 流式查询参数
+type PostGISQuery struct {
+	SQL      string    // 自定义SELECT语句，指定后忽略Columns/Where/OrderBy/Limit/Offset
+	Columns  []string  // 属性列投影，为空时读取全部列（几何列和主键列始终读取）
+	Where    string    // 属性过滤条件（SQL表达式）
+	BBox     *Extent   // 范围过滤（图层坐标系）
+	Filter   *Geometry // 几何过滤，与BBox同时指定时取两者的交集
+	OrderBy  string    // 排序表达式，如 "gid DESC"
+	Limit    int       // 最大行数，0表示不限制
+	Offset   int       // 跳过的行数
+	PageSize int       // 游标每页行数，默认1000
+}
+
+// PostGISFeatureIterator PostGIS要素迭代器
+// 用法与 database/sql.Rows 相同：
+//
+//	it, err := reader.Iterate(query)
+//	defer it.Close()
+//	for it.Next() { feature := it.Feature() ... }
+//	if err := it.Err(); err != nil { ... }
+type PostGISFeatureIterator struct {
+	dataset C.OGRDataSourceH
+	layer   C.OGRLayerH
+	current *GDALFeature
+	count   int64
+	err     error
+}
+
+// Iterate 按查询参数创建要素迭代器，迭代结束后必须调用Close
+func (r *PostGISReader) Iterate(query *PostGISQuery) (*PostGISFeatureIterator, error) {
+	if query == nil {
+		query = &PostGISQuery{}
+	}
+	pageSize := query.PageSize
+	if pageSize <= 0 {
+		pageSize = 1000
+	}
+
+	cConnStr := C.CString(r.config.ConnectionString())
+	defer C.free(unsafe.Pointer(cConnStr))
+
+	dataset := C.OGROpen(cConnStr, C.int(0), nil) // 0表示只读
+	if dataset == nil {
+		return nil, fmt.Errorf("无法连接到PostGIS数据库: %s", r.config.String())
+	}
+
+	sql := query.SQL
+	if sql == "" {
+		var err error
+		if sql, err = r.buildStreamSQL(dataset, query); err != nil {
+			C.OGR_DS_Destroy(dataset)
+			return nil, err
+		}
+	}
+
+	filter, err := streamSpatialFilter(query)
+	if err != nil {
+		C.OGR_DS_Destroy(dataset)
+		return nil, err
+	}
+	defer filter.Close()
+
+	cSQL := C.CString(sql)
+	defer C.free(unsafe.Pointer(cSQL))
+	cKey := C.CString("OGR_PG_CURSOR_PAGE")
+	defer C.free(unsafe.Pointer(cKey))
+	cPageSize := C.CString(strconv.Itoa(pageSize))
+	defer C.free(unsafe.Pointer(cPageSize))
+
+	// 游标页大小在创建结果图层时读取，使用线程局部配置避免影响其他查询
+	runtime.LockOSThread()
+	C.CPLSetThreadLocalConfigOption(cKey, cPageSize)
+	layer := C.OGR_DS_ExecuteSQL(dataset, cSQL, filter.handle(), nil)
+	C.CPLSetThreadLocalConfigOption(cKey, nil)
+	runtime.UnlockOSThread()
+
+	if layer == nil {
+		C.OGR_DS_Destroy(dataset)
+		return nil, fmt.Errorf("执行查询失败: %s", sql)
+	}
+
+	it := &PostGISFeatureIterator{
+		dataset: dataset,
+		layer:   layer,
+	}
+	runtime.SetFinalizer(it, (*PostGISFeatureIterator).Close)
+	return it, nil
+}
+
+// ForEach 流式遍历查询结果，fn返回错误时停止遍历
+// 传给fn的要素在fn返回后即被释放，需要保留时请调用Clone。
+func (r *PostGISReader) ForEach(query *PostGISQuery, fn func(feature *GDALFeature) error) error {
+	it, err := r.Iterate(query)
+	if err != nil {
+		return err
+	}
+	defer it.Close()
+
+	for it.Next() {
+		if err := fn(it.Feature()); err != nil {
+			return err
+		}
+	}
+	return it.Err()
+}
+
+// buildStreamSQL 根据查询参数生成SELECT语句
+func (r *PostGISReader) buildStreamSQL(dataset C.OGRDataSourceH, query *PostGISQuery) (string, error) {
+	if r.config.Table == "" {
+		return "", fmt.Errorf("未指定表名或SQL")
+	}
+	schema := r.config.Schema
+	if schema == "" {
+		schema = "public"
+	}
+
+	columns := "*"
+	if len(query.Columns) > 0 {
+		// 通过表图层获取几何列和主键列，确保投影后仍能得到几何和FID
+		cLayerName := C.CString(schema + "." + r.config.Table)
+		defer C.free(unsafe.Pointer(cLayerName))
+		tableLayer := C.OGR_DS_GetLayerByName(dataset, cLayerName)
+		if tableLayer == nil {
+			return "", fmt.Errorf("无法找到图层: %s.%s", schema, r.config.Table)
+		}
+
+		selected := make([]string, 0, len(query.Columns)+2)
+		seen := make(map[string]bool)
+		add := func(name string) {
+			if name != "" && !seen[name] {
+				seen[name] = true
+				selected = append(selected, quoteIdentifier(name))
+			}
+		}
+		add(C.GoString(C.OGR_L_GetFIDColumn(tableLayer)))
+		add(C.GoString(C.OGR_L_GetGeometryColumn(tableLayer)))
+		for _, column := range query.Columns {
+			add(column)
+		}
+		columns = strings.Join(selected, ", ")
+	}
+
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("SELECT %s FROM %s.%s", columns, quoteIdentifier(schema), quoteIdentifier(r.config.Table)))
+	if query.Where != "" {
+		sb.WriteString(" WHERE " + query.Where)
+	}
+	if query.OrderBy != "" {
+		sb.WriteString(" ORDER BY " + query.OrderBy)
+	}
+	if query.Limit > 0 {
+		sb.WriteString(fmt.Sprintf(" LIMIT %d", query.Limit))
+	}
+	if query.Offset > 0 {
+		sb.WriteString(fmt.Sprintf(" OFFSET %d", query.Offset))
+	}
+	return sb.String(), nil
+}
+
+// streamSpatialFilter 合并BBox和Filter为空间过滤几何，均未指定时返回nil
+func streamSpatialFilter(query *PostGISQuery) (*Geometry, error) {
+	var bbox *Geometry
+	if query.BBox != nil {
+		e := query.BBox
+		bbox = NewPolygonGeometry([][][2]float64{{
+			{e.MinX, e.MinY}, {e.MaxX, e.MinY}, {e.MaxX, e.MaxY}, {e.MinX, e.MaxY}, {e.MinX, e.MinY},
+		}})
+	}
+
+	switch {
+	case bbox != nil && query.Filter != nil:
+		defer bbox.Close()
+		return bbox.Intersection(query.Filter)
+	case bbox != nil:
+		return bbox, nil
+	case query.Filter != nil:
+		return query.Filter.Clone()
+	}
+	return nil, nil
+}
+
+// quoteIdentifier 为SQL标识符加双引号
+func quoteIdentifier(name string) string {
+	return `"` + strings.ReplaceAll(name, `"`, `""`) + `"`
+}
+
+// Next 读取下一个要素，没有更多要素或出错时返回false
+func (it *PostGISFeatureIterator) Next() bool {
+	if it.current != nil {
+		it.current.Destroy()
+		it.current = nil
+	}
+	if it.layer == nil {
+		return false
+	}
+
+	// GDAL错误状态是线程局部的，读取和检查需在同一线程上完成
+	runtime.LockOSThread()
+	defer runtime.UnlockOSThread()
+	C.CPLErrorReset()
+	feature := C.OGR_L_GetNextFeature(it.layer)
+	if feature == nil {
+		if C.CPLGetLastErrorType() >= C.CE_Failure {
+			it.err = fmt.Errorf("读取要素失败: %s", C.GoString(C.CPLGetLastErrorMsg()))
+		}
+		return false
+	}
+	it.current = &GDALFeature{Feature: feature}
+	it.count++
+	return true
+}
+
+// Feature 当前要素，在下一次调用Next或Close前有效
+func (it *PostGISFeatureIterator) Feature() *GDALFeature {
+	return it.current
+}
+
+// Count 已读取的要素数
+func (it *PostGISFeatureIterator) Count() int64 {
+	return it.count
+}
+
+// FieldNames 结果集的字段名
+func (it *PostGISFeatureIterator) FieldNames() []string {
+	if it.layer == nil {
+		return nil
+	}
+	defn := C.OGR_L_GetLayerDefn(it.layer)
+	fieldCount := int(C.OGR_FD_GetFieldCount(defn))
+	names := make([]string, fieldCount)
+	for i := 0; i < fieldCount; i++ {
+		names[i] = C.GoString(C.OGR_Fld_GetNameRef(C.OGR_FD_GetFieldDefn(defn, C.int(i))))
+	}
+	return names
+}
+
+// Err 迭代过程中的错误
+func (it *PostGISFeatureIterator) Err() error {
+	return it.err
+}
+
+// Close 释放游标和数据库连接，重复调用安全
+func (it *PostGISFeatureIterator) Close() {
+	if it.current != nil {
+		it.current.Destroy()
+		it.current = nil
+	}
+	if it.layer != nil {
+		C.OGR_DS_ReleaseResultSet(it.dataset, it.layer)
+		it.layer = nil
+	}
+	if it.dataset != nil {
+		C.OGR_DS_Destroy(it.dataset)
+		it.dataset = nil
+	}
+	runtime.SetFinalizer(it, nil)
+}
@@ -0,0 +1,158 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+/*
+#include "osgeo_utils.h"
+*/
+import "C"
+import (
+	"fmt"
+	"unsafe"
+)
+
+// PostGISWriter 通过GDAL PostgreSQL驱动写入PostGIS表
+type PostGISWriter struct {
+	config         *PostGISConfig
+	Overwrite      bool   // 表已存在时是否删除重建
+	GeometryColumn string // 几何列名，默认geom
+}
+
+// NewPostGISWriter 创建新的PostGIS写入器
+func NewPostGISWriter(config *PostGISConfig, overwrite bool) *PostGISWriter {
+	return &PostGISWriter{
+		config:         config,
+		Overwrite:      overwrite,
+		GeometryColumn: "geom",
+	}
+}
+
+// WriteLayer 将图层写入config指定的表（单事务提交）
+func (w *PostGISWriter) WriteLayer(sourceLayer *GDALLayer) error {
+	if sourceLayer == nil || sourceLayer.layer == nil {
+		return fmt.Errorf("源图层为空")
+	}
+	if w.config == nil || w.config.Table == "" {
+		return fmt.Errorf("未指定目标表")
+	}
+
+	cConnStr := C.CString(w.config.ConnectionString())
+	defer C.free(unsafe.Pointer(cConnStr))
+
+	dataset := C.OGROpen(cConnStr, C.int(1), nil) // 1表示可写
+	if dataset == nil {
+		return fmt.Errorf("无法连接到PostGIS数据库: %s", w.config.String())
+	}
+	defer C.OGR_DS_Destroy(dataset)
+
+	schema := w.config.Schema
+	if schema == "" {
+		schema = "public"
+	}
+	qualifiedName := fmt.Sprintf("%s.%s", schema, w.config.Table)
+
+	cQualifiedName := C.CString(qualifiedName)
+	defer C.free(unsafe.Pointer(cQualifiedName))
+	if C.OGR_DS_GetLayerByName(dataset, cQualifiedName) != nil && !w.Overwrite {
+		return fmt.Errorf("表已存在: %s", qualifiedName)
+	}
+
+	layerOptions := []string{
+		"SCHEMA=" + schema,
+		"SPATIAL_INDEX=GIST",
+	}
+	if w.GeometryColumn != "" {
+		layerOptions = append(layerOptions, "GEOMETRY_NAME="+w.GeometryColumn)
+	}
+	if w.Overwrite {
+		layerOptions = append(layerOptions, "OVERWRITE=YES")
+	}
+
+	var cOptions **C.char
+	for _, option := range layerOptions {
+		cOption := C.CString(option)
+		cOptions = C.CSLAddString(cOptions, cOption)
+		C.free(unsafe.Pointer(cOption))
+	}
+	defer C.CSLDestroy(cOptions)
+
+	cTable := C.CString(w.config.Table)
+	defer C.free(unsafe.Pointer(cTable))
+
+	sourceDefn := sourceLayer.GetLayerDefn()
+	targetLayer := C.OGR_DS_CreateLayer(dataset, cTable, sourceLayer.GetSpatialRef(),
+		C.OGR_FD_GetGeomType(sourceDefn), cOptions)
+	if targetLayer == nil {
+		return fmt.Errorf("无法创建表: %s", qualifiedName)
+	}
+
+	mappings, err := createPostGISFields(sourceDefn, targetLayer)
+	if err != nil {
+		return err
+	}
+
+	C.OGR_L_StartTransaction(targetLayer)
+	targetDefn := C.OGR_L_GetLayerDefn(targetLayer)
+	sourceLayer.ResetReading()
+	for {
+		sourceFeature := sourceLayer.GetNextFeatureRow()
+		if sourceFeature == nil {
+			break
+		}
+
+		targetFeature := C.OGR_F_Create(targetDefn)
+		if geom := C.OGR_F_GetGeometryRef(sourceFeature); geom != nil {
+			C.OGR_F_SetGeometry(targetFeature, geom)
+		}
+		copyFeatureFields(sourceFeature, targetFeature, mappings)
+		result := C.OGR_L_CreateFeature(targetLayer, targetFeature)
+		C.OGR_F_Destroy(targetFeature)
+		C.OGR_F_Destroy(sourceFeature)
+
+		if result != C.OGRERR_NONE {
+			C.OGR_L_RollbackTransaction(targetLayer)
+			return fmt.Errorf("写入要素失败 (错误代码: %d)", int(result))
+		}
+	}
+
+	if C.OGR_L_CommitTransaction(targetLayer) != C.OGRERR_NONE {
+		return fmt.Errorf("提交事务失败: %s", qualifiedName)
+	}
+	return nil
+}
+
+// createPostGISFields 在目标表中创建源图层的字段，返回源字段到目标字段的映射
+// PostgreSQL驱动会规范化字段名（转小写等），因此按创建顺序而不是字段名建立映射。
+func createPostGISFields(sourceDefn C.OGRFeatureDefnH, targetLayer C.OGRLayerH) ([]FieldMapping, error) {
+	targetDefn := C.OGR_L_GetLayerDefn(targetLayer)
+	fieldCount := int(C.OGR_FD_GetFieldCount(sourceDefn))
+	mappings := make([]FieldMapping, 0, fieldCount)
+
+	for i := 0; i < fieldCount; i++ {
+		fieldDefn := C.OGR_FD_GetFieldDefn(sourceDefn, C.int(i))
+		fieldName := C.GoString(C.OGR_Fld_GetNameRef(fieldDefn))
+		if C.OGR_L_CreateField(targetLayer, fieldDefn, C.int(1)) != C.OGRERR_NONE {
+			return nil, fmt.Errorf("无法创建字段: %s", fieldName)
+		}
+		mappings = append(mappings, FieldMapping{
+			SourceIndex: i,
+			TargetIndex: int(C.OGR_FD_GetFieldCount(targetDefn)) - 1,
+			FieldName:   fieldName,
+		})
+	}
+	return mappings, nil
+}
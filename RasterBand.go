@@ -549,7 +549,7 @@ func (rd *RasterDataset) PaletteToRGB() (*RasterDataset, error) {
 		hasGeoInfo:    rd.hasGeoInfo,
 	}
 
-	return newRD, nil
+	return manageRasterDataset(newRD), nil
 }
 
 // RGBToPalette 将RGB图像转换为调色板图像
@@ -580,7 +580,7 @@ func (rd *RasterDataset) RGBToPalette(colorCount int) (*RasterDataset, error) {
 		hasGeoInfo:    rd.hasGeoInfo,
 	}
 
-	return newRD, nil
+	return manageRasterDataset(newRD), nil
 }
 
 // ==================== 辅助方法 ====================
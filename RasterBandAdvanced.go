@@ -501,7 +501,7 @@ func (rd *RasterDataset) MergeBandsToNewDataset(bandIndices []int) (*RasterDatas
 		hasGeoInfo:    rd.hasGeoInfo,
 	}
 
-	return newRD, nil
+	return manageRasterDataset(newRD), nil
 }
 
 // SplitBands 将多波段数据集拆分为单波段数据集数组
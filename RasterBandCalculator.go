@@ -648,7 +648,7 @@ func (rd *RasterDataset) CreateSingleBandDataset(data []float64, dataType BandDa
 		hasGeoInfo:    hasGeoInfo,
 	}
 
-	return newRD, nil
+	return manageRasterDataset(newRD), nil
 }
 
 // SaveAsGeoTIFF 快捷方法：导出为GeoTIFF
@@ -9,7 +9,6 @@ import "C"
 
 import (
 	"fmt"
-	"runtime"
 	"unsafe"
 )
 
@@ -727,7 +726,7 @@ func (rd *RasterDataset) createNewDataset(cDS C.GDALDatasetH) *RasterDataset {
 	}
 
 	// 关键：为新数据集设置 finalizer，确保 MEM 数据集最终被释放
-	runtime.SetFinalizer(newRD, (*RasterDataset).Close)
+	manageRasterDataset(newRD)
 
 	return newRD
 }
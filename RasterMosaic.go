@@ -10,7 +10,6 @@ import "C"
 
 import (
 	"fmt"
-	"runtime"
 )
 
 // ResampleMethod 重采样方法
@@ -129,7 +128,7 @@ func MosaicDatasets(datasets []*RasterDataset, options *MosaicOptions) (*RasterD
 		hasGeoInfo:    true,
 	}
 
-	runtime.SetFinalizer(rd, (*RasterDataset).Close)
+	manageRasterDataset(rd)
 
 	return rd, nil
 }
@@ -275,7 +274,7 @@ func cloneDataset(src *RasterDataset) (*RasterDataset, error) {
 		hasGeoInfo:    src.hasGeoInfo,
 	}
 
-	runtime.SetFinalizer(rd, (*RasterDataset).Close)
+	manageRasterDataset(rd)
 	return rd, nil
 }
 
@@ -151,7 +151,7 @@ func OpenRasterDataset(imagePath string, reProj bool) (*RasterDataset, error) {
 		hasGeoInfo:    hasGeoInfo,
 	}
 
-	runtime.SetFinalizer(rd, (*RasterDataset).Close)
+	manageRasterDataset(rd)
 
 	return rd, nil
 }
@@ -234,6 +234,7 @@ func (rd *RasterDataset) Close() {
 
 	// 清除 finalizer，防止重复调用
 	runtime.SetFinalizer(rd, nil)
+	untrackResource(resourceKey(ResourceRasterDataset, unsafe.Pointer(rd)))
 
 	// 先关闭 warpedDS（如果存在且与 dataset 不同）
 	if rd.warpedDS != nil {
@@ -834,7 +835,7 @@ func (rd *RasterDataset) DefineProjectionToMemory(epsgCode int) (*RasterDataset,
 		hasGeoInfo:    true,
 	}
 
-	runtime.SetFinalizer(newRD, (*RasterDataset).Close)
+	manageRasterDataset(newRD)
 	return newRD, nil
 }
 
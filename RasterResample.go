@@ -9,7 +9,6 @@ import "C"
 
 import (
 	"fmt"
-	"runtime"
 )
 
 // ==================== 栅格重采样 ====================
@@ -196,7 +195,7 @@ func (rd *RasterDataset) Resample(options *ResampleOptions) (*RasterDataset, err
 		hasGeoInfo:    rd.hasGeoInfo,
 	}
 
-	runtime.SetFinalizer(result, (*RasterDataset).Close)
+	manageRasterDataset(result)
 	return result, nil
 }
 
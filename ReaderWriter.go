@@ -26,7 +26,6 @@ import (
 	"fmt"
 	"os"
 	"path/filepath"
-	"runtime"
 	"strings"
 	"unsafe"
 )
@@ -61,9 +60,7 @@ func (r *PostGISReader) ReadGeometryTable() (*GDALLayer, error) {
 	// 初始化GDAL
 
 	// 构建连接字符串
-	connStr := fmt.Sprintf("PG:host=%s port=%s dbname=%s user=%s password=%s",
-		r.config.Host, r.config.Port, r.config.Database,
-		r.config.User, r.config.Password)
+	connStr := r.config.ConnectionString()
 
 	cConnStr := C.CString(connStr)
 	defer C.free(unsafe.Pointer(cConnStr))
@@ -77,7 +74,7 @@ func (r *PostGISReader) ReadGeometryTable() (*GDALLayer, error) {
 	// 打开数据源
 	dataset := C.OGROpen(cConnStr, C.int(0), nil) // 0表示只读
 	if dataset == nil {
-		return nil, fmt.Errorf("无法连接到PostGIS数据库: %s", connStr)
+		return nil, fmt.Errorf("无法连接到PostGIS数据库: %s", r.config.String())
 	}
 
 	// 构建图层名称（包含schema）
@@ -105,21 +102,43 @@ func (r *PostGISReader) ReadGeometryTable() (*GDALLayer, error) {
 	}
 
 	// 设置finalizer以确保资源清理
-	runtime.SetFinalizer(gdalLayer, (*GDALLayer).cleanup)
+	manageLayer(gdalLayer)
 
 	return gdalLayer, nil
 }
 
+// ConnectionString 生成GDAL PostgreSQL驱动使用的连接字符串
+func (c *PostGISConfig) ConnectionString() string {
+	parts := []string{"PG:"}
+	add := func(key, value string) {
+		if value == "" {
+			return
+		}
+		value = strings.ReplaceAll(value, `\`, `\\`)
+		value = strings.ReplaceAll(value, "'", `\'`)
+		parts = append(parts, fmt.Sprintf("%s='%s'", key, value))
+	}
+	add("host", c.Host)
+	add("port", c.Port)
+	add("dbname", c.Database)
+	add("user", c.User)
+	add("password", c.Password)
+	return parts[0] + strings.Join(parts[1:], " ")
+}
+
+// String 返回不含密码的连接描述
+func (c *PostGISConfig) String() string {
+	return fmt.Sprintf("host=%s port=%s dbname=%s user=%s", c.Host, c.Port, c.Database, c.User)
+}
+
+// MakePGReader 使用默认连接配置（名为"default"的连接）创建读取器，schema默认为public
 func MakePGReader(table string) *PostGISReader {
-	con := MainConfig
 	config := &PostGISConfig{
-		Host:     con.Host,
-		Port:     con.Port,
-		Database: con.Dbname,
-		User:     con.Username,
-		Password: con.Password,
-		Schema:   "public", // 可选，默认为public
-		Table:    table,
+		Schema: "public", // 可选，默认为public
+		Table:  table,
+	}
+	if profile, err := GetConnection(DefaultConnectionName); err == nil {
+		config = profile.PostGISConfig("", table)
 	}
 	// 创建读取器
 	reader := NewPostGISReader(config)
@@ -129,7 +148,8 @@ func MakePGReader(table string) *PostGISReader {
 // FileGeoReader 文件地理数据读取器
 type FileGeoReader struct {
 	FilePath string
-	FileType string // "shp", "gdb"
+	FileType string // "shp", "gdb", "geojson", "dxf", "kml", "kmz", "gpkg", "fgb", "parquet"
+	Encoding string // Shapefile DBF编码（如 "GBK"、"UTF-8"），为空时自动检测
 }
 
 // NewFileGeoReader 创建新的文件地理数据读取器
@@ -168,6 +188,12 @@ func determineFileType(filePath string) (string, error) {
 		return "kml", nil
 	case ".kmz":
 		return "kmz", nil
+	case ".gpkg":
+		return "gpkg", nil
+	case ".fgb":
+		return "fgb", nil
+	case ".parquet", ".geoparquet":
+		return "parquet", nil
 	default:
 		// 检查是否为文件夹(可能是GDB)
 		if info, err := os.Stat(filePath); err == nil && info.IsDir() {
@@ -185,17 +211,20 @@ func (r *FileGeoReader) ReadShapeFile(layerName ...string) (*GDALLayer, error) {
 		return nil, fmt.Errorf("文件类型不是Shapefile: %s", r.FileType)
 	}
 
-	cFilePath := C.CString(r.FilePath)
-	defer C.free(unsafe.Pointer(cFilePath))
-
 	// 获取Shapefile驱动
 	driver := C.OGRGetDriverByName(C.CString("ESRI Shapefile"))
 	if driver == nil {
 		return nil, fmt.Errorf("无法获取Shapefile驱动")
 	}
 
-	// 打开数据源
-	dataset := C.OGROpen(cFilePath, C.int(0), nil) // 0表示只读
+	// 以打开选项指定编码，字符串由该编码转换为UTF-8
+	encoding := normalizeSHPEncoding(r.Encoding)
+	if encoding == "" {
+		encoding = detectSHPEncoding(r.FilePath)
+	}
+
+	// 以只读模式打开数据源
+	dataset := openShapefile(r.FilePath, false, encoding)
 	if dataset == nil {
 		return nil, fmt.Errorf("无法打开Shapefile: %s", r.FilePath)
 	}
@@ -219,14 +248,24 @@ func (r *FileGeoReader) ReadShapeFile(layerName ...string) (*GDALLayer, error) {
 		return nil, fmt.Errorf("无法获取图层")
 	}
 
+	// 有字段名映射文件时还原被截断的字段名
+	resultSet := false
+	if renamed, err := restoreSHPFieldNames(dataset, layer, r.FilePath); err != nil {
+		fmt.Printf("警告: %v，使用Shapefile中的字段名\n", err)
+	} else if renamed != nil {
+		layer = renamed
+		resultSet = true
+	}
+
 	gdalLayer := &GDALLayer{
-		layer:   layer,
-		dataset: dataset,
-		driver:  driver,
+		layer:     layer,
+		dataset:   dataset,
+		driver:    driver,
+		resultSet: resultSet,
 	}
 
 	// 设置finalizer以确保资源清理
-	runtime.SetFinalizer(gdalLayer, (*GDALLayer).cleanup)
+	manageLayer(gdalLayer)
 
 	return gdalLayer, nil
 }
@@ -282,7 +321,7 @@ func (r *FileGeoReader) ReadGDBFile(layerName ...string) (*GDALLayer, error) {
 	}
 
 	// 设置finalizer以确保资源清理
-	runtime.SetFinalizer(gdalLayer, (*GDALLayer).cleanup)
+	manageLayer(gdalLayer)
 
 	return gdalLayer, nil
 }
@@ -302,6 +341,12 @@ func (r *FileGeoReader) ReadLayer(layerName ...string) (*GDALLayer, error) {
 		return r.ReadKMLFile(layerName...)
 	case "kmz":
 		return r.ReadKMZFile(layerName...)
+	case "gpkg":
+		return r.ReadGPKGFile(layerName...)
+	case "fgb":
+		return r.ReadFlatGeobufFile(layerName...)
+	case "parquet":
+		return r.ReadGeoParquetFile(layerName...)
 	default:
 		return nil, fmt.Errorf("不支持的文件类型: %s", r.FileType)
 	}
@@ -426,8 +471,12 @@ func ReadGeospatialFile(filePath string, layerName ...string) (*GDALLayer, error
 // FileGeoWriter 文件地理数据写入器
 type FileGeoWriter struct {
 	FilePath  string
-	FileType  string // "shp", "gdb"
-	Overwrite bool   // 是否覆盖已存在的文件
+	FileType  string // "shp", "gdb", "geojson", "dxf", "kml", "kmz", "gpkg", "fgb", "parquet"
+	Overwrite bool   // 是否覆盖已存在的文件（GeoPackage为覆盖同名图层）
+	Encoding  string // Shapefile DBF编码，默认GBK
+
+	// SHPMaxPartSize 单个.shp/.dbf文件的大小上限（字节），超过时分块写入，默认2GB
+	SHPMaxPartSize int64
 }
 
 // NewFileGeoWriter 创建新的文件地理数据写入器
@@ -462,6 +511,12 @@ func determineFileTypeForWrite(filePath string) (string, error) {
 		return "kml", nil
 	case ".kmz":
 		return "kmz", nil
+	case ".gpkg":
+		return "gpkg", nil
+	case ".fgb":
+		return "fgb", nil
+	case ".parquet", ".geoparquet":
+		return "parquet", nil
 	default:
 		if strings.HasSuffix(strings.ToLower(filePath), ".gdb") {
 			return "gdb", nil
@@ -471,59 +526,29 @@ func determineFileTypeForWrite(filePath string) (string, error) {
 }
 
 // WriteShapeFile 写入Shapefile
-func (w *FileGeoWriter) WriteShapeFile(sourceLayer *GDALLayer, layerName string) error {
+func (w *FileGeoWriter) WriteShapeFile(sourceLayer *GDALLayer, layerName string) (err error) {
 	if w.FileType != "shp" {
 		return fmt.Errorf("文件类型不是Shapefile: %s", w.FileType)
 	}
 
-	// 设置Shapefile编码为GBK/GB2312（中文Windows系统）
-	C.CPLSetConfigOption(C.CString("SHAPE_ENCODING"), C.CString("GBK"))
-	defer C.CPLSetConfigOption(C.CString("SHAPE_ENCODING"), nil)
+	// Shapefile编码，默认GBK/GB2312（中文Windows系统），通过图层创建选项ENCODING传递
+	encoding := normalizeSHPEncoding(w.Encoding)
+	if encoding == "" {
+		encoding = SHPEncodingGBK
+	}
+
 	// 如果需要覆盖，先删除已存在的文件
 	if w.Overwrite {
 		w.removeShapeFiles()
 	}
 
-	// 获取Shapefile驱动
-	driver := C.OGRGetDriverByName(C.CString("ESRI Shapefile"))
-	if driver == nil {
-		return fmt.Errorf("无法获取Shapefile驱动")
-	}
-
-	// 创建数据源
-	cFilePath := C.CString(w.FilePath)
-	defer C.free(unsafe.Pointer(cFilePath))
-
-	dataset := C.OGR_Dr_CreateDataSource(driver, cFilePath, nil)
-	if dataset == nil {
-		return fmt.Errorf("无法创建Shapefile: %s", w.FilePath)
-	}
-	defer C.OGR_DS_Destroy(dataset)
-
-	// 获取源图层信息
-	sourceDefn := sourceLayer.GetLayerDefn()
-	geomType := C.OGR_FD_GetGeomType(sourceDefn)
-	srs := sourceLayer.GetSpatialRef()
-
-	// 创建图层
-	cLayerName := C.CString(layerName)
-	defer C.free(unsafe.Pointer(cLayerName))
-
-	newLayer := C.OGR_DS_CreateLayer(dataset, cLayerName, srs, geomType, nil)
-	if newLayer == nil {
-		return fmt.Errorf("无法创建图层: %s", layerName)
-	}
-
-	// 复制字段定义
-	err := w.copyFieldDefinitions(sourceDefn, newLayer)
+	// 超过2GB时分块写入；字段名截断后保持唯一，原字段名记录在.fieldmap.json中
+	parts, err := w.writeShapefileParts(sourceLayer, layerName, encoding)
 	if err != nil {
 		return err
 	}
-
-	// 复制要素
-	err = w.copyFeatures(sourceLayer, newLayer)
-	if err != nil {
-		return err
+	if parts > 1 {
+		fmt.Printf("Shapefile已分为%d个文件写入\n", parts)
 	}
 
 	return nil
@@ -531,6 +556,11 @@ func (w *FileGeoWriter) WriteShapeFile(sourceLayer *GDALLayer, layerName string)
 
 // WriteGDBFile 写入GDB文件
 func (w *FileGeoWriter) WriteGDBFile(sourceLayer *GDALLayer, layerName string) error {
+	return w.WriteGDBFileToDataset(sourceLayer, layerName, "")
+}
+
+// WriteGDBFileToDataset 写入GDB文件中的指定要素数据集，datasetName为空时写入GDB根目录
+func (w *FileGeoWriter) WriteGDBFileToDataset(sourceLayer *GDALLayer, layerName string, datasetName string) error {
 	if w.FileType != "gdb" {
 		return fmt.Errorf("文件类型不是GDB: %s", w.FileType)
 	}
@@ -577,13 +607,22 @@ func (w *FileGeoWriter) WriteGDBFile(sourceLayer *GDALLayer, layerName string) e
 	cLayerName := C.CString(layerName)
 	defer C.free(unsafe.Pointer(cLayerName))
 
-	newLayer := C.OGR_DS_CreateLayer(dataset, cLayerName, srs, geomType, nil)
+	// 指定要素数据集
+	var options **C.char
+	if datasetName != "" {
+		datasetOpt := C.CString("FEATURE_DATASET=" + datasetName)
+		defer C.free(unsafe.Pointer(datasetOpt))
+		options = C.CSLAddString(options, datasetOpt)
+		defer C.CSLDestroy(options)
+	}
+
+	newLayer := C.OGR_DS_CreateLayer(dataset, cLayerName, srs, geomType, options)
 	if newLayer == nil {
 		return fmt.Errorf("无法创建图层: %s", layerName)
 	}
 
 	// 复制字段定义
-	err := w.copyFieldDefinitions(sourceDefn, newLayer)
+	err := w.copyFieldDefinitions(sourceDefn, newLayer, nil)
 	if err != nil {
 		return err
 	}
@@ -612,13 +651,19 @@ func (w *FileGeoWriter) WriteLayer(sourceLayer *GDALLayer, layerName string) err
 		return w.WriteKMLFile(sourceLayer, layerName)
 	case "kmz":
 		return w.WriteKMZFile(sourceLayer, layerName)
+	case "gpkg":
+		return w.WriteGPKGFile(sourceLayer, layerName)
+	case "fgb":
+		return w.WriteFlatGeobufFile(sourceLayer, layerName)
+	case "parquet":
+		return w.WriteGeoParquetFile(sourceLayer, layerName)
 	default:
 		return fmt.Errorf("不支持的文件类型: %s", w.FileType)
 	}
 }
 
 // copyFieldDefinitions 复制字段定义（改进版）
-func (w *FileGeoWriter) copyFieldDefinitions(sourceDefn C.OGRFeatureDefnH, targetLayer C.OGRLayerH) error {
+func (w *FileGeoWriter) copyFieldDefinitions(sourceDefn C.OGRFeatureDefnH, targetLayer C.OGRLayerH, fieldNames map[string]string) error {
 	fieldCount := int(C.OGR_FD_GetFieldCount(sourceDefn))
 
 	// GDB保留字段名列表
@@ -646,7 +691,7 @@ func (w *FileGeoWriter) copyFieldDefinitions(sourceDefn C.OGRFeatureDefnH, targe
 		}
 
 		// 处理字段名（确保符合GDB命名规范）
-		fieldName := w.sanitizeFieldName(originalName)
+		fieldName := w.sanitizeFieldName(originalName, fieldNames)
 
 		// 处理字段类型（确保与GDB兼容）
 		targetFieldType := w.mapFieldTypeForGDB(fieldType, w.FileType)
@@ -678,17 +723,14 @@ func (w *FileGeoWriter) copyFieldDefinitions(sourceDefn C.OGRFeatureDefnH, targe
 }
 
 // sanitizeFieldName 清理字段名以符合目标格式要求
-func (w *FileGeoWriter) sanitizeFieldName(name string) string {
-	// 移除特殊字符，替换为下划线
-	sanitized := strings.ReplaceAll(name, " ", "_")
-	sanitized = strings.ReplaceAll(sanitized, "-", "_")
-	sanitized = strings.ReplaceAll(sanitized, ".", "_")
-
-	// 确保字段名不以数字开头
-	if len(sanitized) > 0 && sanitized[0] >= '0' && sanitized[0] <= '9' {
-		sanitized = "f_" + sanitized
+// fieldNames 为写入Shapefile时预先生成的原字段名到唯一短字段名的映射，其他格式传nil
+func (w *FileGeoWriter) sanitizeFieldName(name string, fieldNames map[string]string) string {
+	if mapped, ok := fieldNames[name]; ok {
+		return mapped
 	}
 
+	sanitized := replaceFieldNameChars(name)
+
 	// 限制字段名长度（Shapefile限制为10个字符）
 	if w.FileType == "shp" && len(sanitized) > 10 {
 		sanitized = sanitized[:10]
@@ -697,6 +739,18 @@ func (w *FileGeoWriter) sanitizeFieldName(name string) string {
 	return sanitized
 }
 
+// replaceFieldNameChars 将字段名中的特殊字符替换为下划线，并确保不以数字开头
+func replaceFieldNameChars(name string) string {
+	sanitized := strings.ReplaceAll(name, " ", "_")
+	sanitized = strings.ReplaceAll(sanitized, "-", "_")
+	sanitized = strings.ReplaceAll(sanitized, ".", "_")
+
+	if len(sanitized) > 0 && sanitized[0] >= '0' && sanitized[0] <= '9' {
+		sanitized = "f_" + sanitized
+	}
+	return sanitized
+}
+
 // mapFieldTypeForGDB 映射字段类型以兼容目标格式
 func (w *FileGeoWriter) mapFieldTypeForGDB(sourceType C.OGRFieldType, targetFormat string) C.OGRFieldType {
 	switch sourceType {
@@ -708,7 +762,10 @@ func (w *FileGeoWriter) mapFieldTypeForGDB(sourceType C.OGRFieldType, targetForm
 		return sourceType
 
 	case C.OFTBinary:
-		// 二进制字段转换为字符串
+		// GeoPackage、FlatGeobuf、GeoParquet支持二进制，其余格式二进制字段转换为字符串
+		if targetFormat == "gpkg" || targetFormat == "fgb" || targetFormat == "parquet" {
+			return sourceType
+		}
 		return C.OFTString
 
 	case C.OFTIntegerList, C.OFTRealList, C.OFTStringList:
@@ -747,7 +804,7 @@ func (w *FileGeoWriter) copyFeatures(sourceLayer *GDALLayer, targetLayer C.OGRLa
 			}()
 
 			// 尝试复制要素
-			if err := w.copyFeatureSafely(sourceFeature, targetLayer, targetDefn); err != nil {
+			if err := w.copyFeatureSafely(sourceFeature, targetLayer, targetDefn, nil); err != nil {
 
 				errorCount++
 			} else {
@@ -760,7 +817,7 @@ func (w *FileGeoWriter) copyFeatures(sourceLayer *GDALLayer, targetLayer C.OGRLa
 }
 
 // copyFeatureSafely 安全地复制单个要素
-func (w *FileGeoWriter) copyFeatureSafely(sourceFeature C.OGRFeatureH, targetLayer C.OGRLayerH, targetDefn C.OGRFeatureDefnH) error {
+func (w *FileGeoWriter) copyFeatureSafely(sourceFeature C.OGRFeatureH, targetLayer C.OGRLayerH, targetDefn C.OGRFeatureDefnH, fieldNames map[string]string) error {
 	// 创建新要素
 	newFeature := C.OGR_F_Create(targetDefn)
 	if newFeature == nil {
@@ -776,7 +833,7 @@ func (w *FileGeoWriter) copyFeatureSafely(sourceFeature C.OGRFeatureH, targetLay
 	}
 
 	// 复制字段值
-	if err := w.copyFieldsSafely(sourceFeature, newFeature); err != nil {
+	if err := w.copyFieldsSafely(sourceFeature, newFeature, fieldNames); err != nil {
 		return fmt.Errorf("字段复制失败: %v", err)
 	}
 
@@ -805,6 +862,11 @@ func (w *FileGeoWriter) copyGeometrySafely(sourceFeature, newFeature C.OGRFeatur
 	targetDefn := C.OGR_F_GetDefnRef(newFeature)
 	targetGeomType := C.OGR_FD_GetGeomType(targetDefn)
 
+	// 属性表不写几何
+	if targetGeomType == C.wkbNone {
+		return nil
+	}
+
 	// 检查几何是否有效
 	if C.OGR_G_IsValid(geometry) == 0 {
 		validGeom := C.OGR_G_MakeValid(geometry)
@@ -820,6 +882,14 @@ func (w *FileGeoWriter) copyGeometrySafely(sourceFeature, newFeature C.OGRFeatur
 		}
 	}
 
+	// 未知几何类型的图层（如GeoPackage的GEOMETRY列）可容纳任意几何，直接写入
+	if targetGeomType == C.wkbUnknown {
+		if result := C.OGR_F_SetGeometry(newFeature, geometry); result != C.OGRERR_NONE {
+			return fmt.Errorf("设置几何失败，错误代码: %d", int(result))
+		}
+		return nil
+	}
+
 	// 使用normalizeGeometryType进行几何类型规范化
 	normalizedGeom := C.normalizeGeometryType(geometry, targetGeomType)
 	if normalizedGeom == nil {
@@ -889,7 +959,7 @@ func (w *FileGeoWriter) isGeometryTypeCompatible(sourceType, targetType C.OGRwkb
 // removeShapeFiles 删除Shapefile相关文件
 func (w *FileGeoWriter) removeShapeFiles() {
 	baseName := strings.TrimSuffix(w.FilePath, filepath.Ext(w.FilePath))
-	extensions := []string{".shp", ".shx", ".dbf", ".prj", ".cpg", ".qix", ".sbn", ".sbx"}
+	extensions := []string{".shp", ".shx", ".dbf", ".prj", ".cpg", ".qix", ".sbn", ".sbx", shpFieldMapSuffix}
 
 	for _, ext := range extensions {
 		filePath := baseName + ext
@@ -897,10 +967,21 @@ func (w *FileGeoWriter) removeShapeFiles() {
 			os.Remove(filePath)
 		}
 	}
+
+	// 删除之前分块写入的 _2、_3 ... 文件
+	for part := 2; ; part++ {
+		partBase := strings.TrimSuffix(shpPartPath(w.FilePath, part), filepath.Ext(w.FilePath))
+		if _, err := os.Stat(partBase + ".shp"); err != nil {
+			break
+		}
+		for _, ext := range extensions {
+			os.Remove(partBase + ext)
+		}
+	}
 }
 
 // copyFieldsSafely 安全地复制字段（修复版 - 按字段名匹配）
-func (w *FileGeoWriter) copyFieldsSafely(sourceFeature, newFeature C.OGRFeatureH) error {
+func (w *FileGeoWriter) copyFieldsSafely(sourceFeature, newFeature C.OGRFeatureH, fieldNames map[string]string) error {
 	// 获取源要素和目标要素的定义
 	sourceDefn := C.OGR_F_GetDefnRef(sourceFeature)
 	targetDefn := C.OGR_F_GetDefnRef(newFeature)
@@ -952,7 +1033,7 @@ func (w *FileGeoWriter) copyFieldsSafely(sourceFeature, newFeature C.OGRFeatureH
 		}
 
 		// 处理字段名（与copyFieldDefinitions中的处理保持一致）
-		sanitizedFieldName := w.sanitizeFieldName(originalFieldName)
+		sanitizedFieldName := w.sanitizeFieldName(originalFieldName, fieldNames)
 
 		// 查找目标字段索引
 		targetIndex, exists := targetFieldMap[sanitizedFieldName]
@@ -1054,6 +1135,9 @@ func (w *FileGeoWriter) copyFieldValueSafelyByName(sourceFeature, newFeature C.O
 				year, month, day, hour, minute, second, tzflag)
 		}
 
+	case C.OFTBinary:
+		setFeatureBinary(newFeature, C.int(targetIndex), featureBinary(sourceFeature, C.int(sourceIndex)))
+
 	default:
 		// 对于不支持的字段类型，尝试作为字符串处理
 		value := C.OGR_F_GetFieldAsString(sourceFeature, C.int(sourceIndex))
@@ -1309,7 +1393,7 @@ func (r *FileGeoReader) ReadGeoJSONFile(layerName ...string) (*GDALLayer, error)
 		driver:  driver,
 	}
 
-	runtime.SetFinalizer(gdalLayer, (*GDALLayer).cleanup)
+	manageLayer(gdalLayer)
 	return gdalLayer, nil
 }
 
@@ -1359,7 +1443,7 @@ func (r *FileGeoReader) ReadDXFFile(layerName ...string) (*GDALLayer, error) {
 		driver:  driver,
 	}
 
-	runtime.SetFinalizer(gdalLayer, (*GDALLayer).cleanup)
+	manageLayer(gdalLayer)
 	return gdalLayer, nil
 }
 
@@ -1412,7 +1496,7 @@ func (r *FileGeoReader) ReadKMLFile(layerName ...string) (*GDALLayer, error) {
 		driver:  driver,
 	}
 
-	runtime.SetFinalizer(gdalLayer, (*GDALLayer).cleanup)
+	manageLayer(gdalLayer)
 	return gdalLayer, nil
 }
 
@@ -1467,7 +1551,7 @@ func (r *FileGeoReader) ReadKMZFile(layerName ...string) (*GDALLayer, error) {
 		driver:  driver,
 	}
 
-	runtime.SetFinalizer(gdalLayer, (*GDALLayer).cleanup)
+	manageLayer(gdalLayer)
 	return gdalLayer, nil
 }
 
@@ -1524,7 +1608,7 @@ func (w *FileGeoWriter) WriteGeoJSONFile(sourceLayer *GDALLayer, layerName strin
 		return fmt.Errorf("无法创建图层: %s", layerName)
 	}
 
-	if err := w.copyFieldDefinitions(sourceDefn, newLayer); err != nil {
+	if err := w.copyFieldDefinitions(sourceDefn, newLayer, nil); err != nil {
 		return err
 	}
 
@@ -1576,7 +1660,7 @@ func (w *FileGeoWriter) WriteDXFFile(sourceLayer *GDALLayer, layerName string) e
 		return fmt.Errorf("无法创建图层: %s", layerName)
 	}
 
-	if err := w.copyFieldDefinitions(sourceDefn, newLayer); err != nil {
+	if err := w.copyFieldDefinitions(sourceDefn, newLayer, nil); err != nil {
 		return err
 	}
 
@@ -1635,7 +1719,7 @@ func (w *FileGeoWriter) WriteKMLFile(sourceLayer *GDALLayer, layerName string) e
 		return fmt.Errorf("无法创建图层: %s", layerName)
 	}
 
-	if err := w.copyFieldDefinitions(sourceDefn, newLayer); err != nil {
+	if err := w.copyFieldDefinitions(sourceDefn, newLayer, nil); err != nil {
 		return err
 	}
 
@@ -1692,7 +1776,7 @@ func (w *FileGeoWriter) WriteKMZFile(sourceLayer *GDALLayer, layerName string) e
 		return fmt.Errorf("无法创建图层: %s", layerName)
 	}
 
-	if err := w.copyFieldDefinitions(sourceDefn, newLayer); err != nil {
+	if err := w.copyFieldDefinitions(sourceDefn, newLayer, nil); err != nil {
 		return err
 	}
 
@@ -1771,7 +1855,7 @@ func (w *FileGeoWriter) copyFeaturesWithTransform(sourceLayer *GDALLayer, target
 			}
 
 			// 复制字段
-			if err := w.copyFieldsSafely(sourceFeature, newFeature); err != nil {
+			if err := w.copyFieldsSafely(sourceFeature, newFeature, nil); err != nil {
 				errorCount++
 				return
 			}
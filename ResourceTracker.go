@@ -0,0 +1,275 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+/*
+#include "osgeo_utils.h"
+*/
+import "C"
+import (
+	"fmt"
+	"io"
+	"log"
+	"runtime"
+	"sort"
+	"sync"
+	"sync/atomic"
+	"time"
+	"unsafe"
+)
+
+// ============================================================================
+// 资源生命周期跟踪
+// 开启后记录每个图层、栅格数据集、要素和vsimem缓冲区的创建堆栈，
+// 在 CleanupGDAL 或 ReportResourceLeaks 时输出未关闭的资源。
+// 使用 -tags gogeo_debug 编译时默认开启，也可调用 EnableResourceTracking 动态开关。
+// 无论是否开启跟踪，终结器都会回收未显式关闭的资源。
+// ============================================================================
+
+// ResourceKind 资源类型
+type ResourceKind string
+
+const (
+	ResourceLayer         ResourceKind = "GDALLayer"
+	ResourceRasterDataset ResourceKind = "RasterDataset"
+	ResourceFeature       ResourceKind = "GDALFeature"
+	ResourceVSIMem        ResourceKind = "vsimem"
+)
+
+// ResourceRecord 已打开资源的记录
+type ResourceRecord struct {
+	Kind    ResourceKind
+	Name    string    // 资源描述（图层名、文件路径、vsimem路径等）
+	Created time.Time // 创建时间
+	Stack   string    // 创建时的调用堆栈
+}
+
+// ResourceStats 资源统计
+type ResourceStats struct {
+	Open      map[ResourceKind]int // 当前未关闭的资源数
+	Finalized map[ResourceKind]int // 未显式关闭、由终结器回收的资源数
+}
+
+type resourceTracker struct {
+	enabled   atomic.Bool
+	mu        sync.Mutex
+	records   map[string]*ResourceRecord
+	finalized map[ResourceKind]int
+}
+
+var globalResourceTracker = newResourceTracker()
+
+func newResourceTracker() *resourceTracker {
+	t := &resourceTracker{
+		records:   make(map[string]*ResourceRecord),
+		finalized: make(map[ResourceKind]int),
+	}
+	t.enabled.Store(resourceTrackingDefault)
+	return t
+}
+
+// EnableResourceTracking 开启或关闭资源跟踪
+// 关闭时清空已有记录；跟踪会为每次创建采集调用堆栈，生产环境建议仅在排查泄漏时开启。
+func EnableResourceTracking(enabled bool) {
+	t := globalResourceTracker
+	t.enabled.Store(enabled)
+	if !enabled {
+		t.mu.Lock()
+		t.records = make(map[string]*ResourceRecord)
+		t.finalized = make(map[ResourceKind]int)
+		t.mu.Unlock()
+	}
+}
+
+// ResourceTrackingEnabled 是否已开启资源跟踪
+func ResourceTrackingEnabled() bool {
+	return globalResourceTracker.enabled.Load()
+}
+
+// OpenResources 返回当前未关闭的资源（按创建时间排序），未开启跟踪时返回nil
+func OpenResources() []ResourceRecord {
+	t := globalResourceTracker
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	records := make([]ResourceRecord, 0, len(t.records))
+	for _, record := range t.records {
+		records = append(records, *record)
+	}
+	sort.Slice(records, func(i, j int) bool {
+		return records[i].Created.Before(records[j].Created)
+	})
+	return records
+}
+
+// GetResourceStats 返回资源统计
+func GetResourceStats() ResourceStats {
+	t := globalResourceTracker
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	stats := ResourceStats{
+		Open:      make(map[ResourceKind]int),
+		Finalized: make(map[ResourceKind]int),
+	}
+	for _, record := range t.records {
+		stats.Open[record.Kind]++
+	}
+	for kind, count := range t.finalized {
+		stats.Finalized[kind] = count
+	}
+	return stats
+}
+
+// ReportResourceLeaks 将未关闭的资源及其创建堆栈写入w，返回泄漏数量
+func ReportResourceLeaks(w io.Writer) int {
+	records := OpenResources()
+	if len(records) == 0 {
+		return 0
+	}
+
+	fmt.Fprintf(w, "检测到 %d 个未关闭的资源:\n", len(records))
+	for i, record := range records {
+		fmt.Fprintf(w, "[%d] %s %s（创建于 %s，已存在 %s）\n%s\n",
+			i+1, record.Kind, record.Name,
+			record.Created.Format("2006-01-02 15:04:05.000"),
+			time.Since(record.Created).Round(time.Millisecond),
+			record.Stack)
+	}
+	return len(records)
+}
+
+// resourceKey 以Go包装对象地址作为资源键
+func resourceKey(kind ResourceKind, object unsafe.Pointer) string {
+	return fmt.Sprintf("%s@%p", kind, object)
+}
+
+// trackResource 登记资源（未开启跟踪时不做任何事）
+func trackResource(kind ResourceKind, key, name string) {
+	t := globalResourceTracker
+	if !t.enabled.Load() {
+		return
+	}
+
+	buf := make([]byte, 8192)
+	n := runtime.Stack(buf, false)
+
+	t.mu.Lock()
+	t.records[key] = &ResourceRecord{
+		Kind:    kind,
+		Name:    name,
+		Created: time.Now(),
+		Stack:   string(buf[:n]),
+	}
+	t.mu.Unlock()
+}
+
+// untrackResource 注销资源
+func untrackResource(key string) {
+	t := globalResourceTracker
+	if !t.enabled.Load() {
+		return
+	}
+	t.mu.Lock()
+	delete(t.records, key)
+	t.mu.Unlock()
+}
+
+// finalizedResource 记录由终结器回收的资源，跟踪模式下输出其创建堆栈
+func finalizedResource(kind ResourceKind, key string) {
+	t := globalResourceTracker
+	if !t.enabled.Load() {
+		return
+	}
+
+	t.mu.Lock()
+	record := t.records[key]
+	t.finalized[kind]++
+	t.mu.Unlock()
+
+	if record != nil {
+		log.Printf("警告: %s %s 未显式关闭，已由终结器回收，创建堆栈:\n%s", kind, record.Name, record.Stack)
+	}
+}
+
+// ============================================================================
+// 各类资源的登记与终结器
+// ============================================================================
+
+// manageLayer 为图层设置终结器并登记跟踪，返回原图层
+func manageLayer(gl *GDALLayer) *GDALLayer {
+	if gl == nil {
+		return nil
+	}
+	runtime.SetFinalizer(gl, (*GDALLayer).finalize)
+	if ResourceTrackingEnabled() {
+		name := ""
+		if gl.layer != nil {
+			name = C.GoString(C.OGR_L_GetName(gl.layer))
+		}
+		trackResource(ResourceLayer, resourceKey(ResourceLayer, unsafe.Pointer(gl)), name)
+	}
+	return gl
+}
+
+// finalize 图层终结器
+func (gl *GDALLayer) finalize() {
+	finalizedResource(ResourceLayer, resourceKey(ResourceLayer, unsafe.Pointer(gl)))
+	gl.cleanup()
+}
+
+// manageRasterDataset 为栅格数据集设置终结器并登记跟踪，返回原数据集
+func manageRasterDataset(rd *RasterDataset) *RasterDataset {
+	if rd == nil {
+		return nil
+	}
+	runtime.SetFinalizer(rd, (*RasterDataset).finalize)
+	trackResource(ResourceRasterDataset, resourceKey(ResourceRasterDataset, unsafe.Pointer(rd)), rd.filePath)
+	return rd
+}
+
+// finalize 栅格数据集终结器
+func (rd *RasterDataset) finalize() {
+	finalizedResource(ResourceRasterDataset, resourceKey(ResourceRasterDataset, unsafe.Pointer(rd)))
+	rd.Close()
+}
+
+// manageFeature 为调用方持有的要素设置终结器并登记跟踪，返回原要素
+func manageFeature(f *GDALFeature) *GDALFeature {
+	if f == nil {
+		return nil
+	}
+	runtime.SetFinalizer(f, (*GDALFeature).finalize)
+	trackResource(ResourceFeature, resourceKey(ResourceFeature, unsafe.Pointer(f)), "")
+	return f
+}
+
+// finalize 要素终结器
+func (f *GDALFeature) finalize() {
+	finalizedResource(ResourceFeature, resourceKey(ResourceFeature, unsafe.Pointer(f)))
+	f.Destroy()
+}
+
+// trackVSIMem 登记vsimem临时文件
+func trackVSIMem(path string) {
+	trackResource(ResourceVSIMem, string(ResourceVSIMem)+":"+path, path)
+}
+
+// untrackVSIMem 注销vsimem临时文件
+func untrackVSIMem(path string) {
+	untrackResource(string(ResourceVSIMem) + ":" + path)
+}
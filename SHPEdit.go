@@ -143,14 +143,29 @@ func InsertLayerToShapefile(sourceLayer *GDALLayer, shpPath string, options *Ins
 		return fmt.Errorf("源图层为空")
 	}
 
-	cFilePath := C.CString(shpPath)
-	defer C.free(unsafe.Pointer(cFilePath))
+	// 按已有数据的编码打开，新写入的字符串使用同一编码
+	encoding := ""
+	if options != nil {
+		encoding = normalizeSHPEncoding(options.Encoding)
+	}
+	if encoding == "" {
+		encoding = detectSHPEncoding(shpPath)
+	}
 
 	// 以可写模式打开Shapefile数据源
-	targetDataset := C.OGROpen(cFilePath, C.int(1), nil)
+	targetDataset := openShapefile(shpPath, true, encoding)
 	if targetDataset == nil {
 		return fmt.Errorf("无法以可写模式打开Shapefile: %s", shpPath)
 	}
+	// 关闭数据源后写入.cpg和LDID，使其他软件按同一编码读取
+	inserted := false
+	defer func() {
+		if inserted {
+			if err := writeSHPEncodingMarkers(shpPath, encoding); err != nil {
+				fmt.Printf("警告: 写入编码标识失败: %v\n", err)
+			}
+		}
+	}()
 	defer C.OGR_DS_Destroy(targetDataset)
 
 	// 获取目标图层（Shapefile只有一个图层）
@@ -307,6 +322,7 @@ func InsertLayerToShapefile(sourceLayer *GDALLayer, shpPath string, options *Ins
 
 	fmt.Printf("插入完成: 成功 %d 个，失败 %d 个\n", insertedCount, failedCount)
 
+	inserted = insertedCount > 0
 	if failedCount > 0 && options != nil && options.StrictMode {
 		return fmt.Errorf("部分要素插入失败: %d/%d", failedCount, insertedCount+failedCount)
 	}
@@ -356,26 +372,9 @@ func PackShapefile(shpPath string) error {
 // 返回: 是否创建了新字段, error
 func EnsureObjectIDField(shpPath string) (bool, error) {
 
-	// 检测并设置编码（添加这部分）
+	// 按检测到的编码以可写模式打开Shapefile
 	encoding := detectSHPEncoding(shpPath)
-
-	cEncodingKey := C.CString("SHAPE_ENCODING")
-	cEncodingValue := C.CString(encoding)
-	C.CPLSetConfigOption(cEncodingKey, cEncodingValue)
-	err := InitializeGDALSHPCoding(encoding)
-	if err != nil {
-		fmt.Println(err.Error())
-	}
-	defer func() {
-		C.CPLSetConfigOption(cEncodingKey, nil)
-		C.free(unsafe.Pointer(cEncodingKey))
-		C.free(unsafe.Pointer(cEncodingValue))
-	}()
-	cFilePath := C.CString(shpPath)
-	defer C.free(unsafe.Pointer(cFilePath))
-
-	// 以可写模式打开Shapefile
-	dataset := C.OGROpen(cFilePath, C.int(1), nil)
+	dataset := openShapefile(shpPath, true, encoding)
 	if dataset == nil {
 		// 获取详细的 GDAL 错误信息
 		errMsg := C.GoString(C.CPLGetLastErrorMsg())
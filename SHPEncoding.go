@@ -0,0 +1,366 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+/*
+#include "osgeo_utils.h"
+*/
+import "C"
+import (
+	"encoding/binary"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// ============================================================================
+// Shapefile 编码处理
+// GDAL读取时按编码将DBF字符串转换为UTF-8，写入时由UTF-8转换为指定编码。
+// 编码通过打开选项/图层创建选项ENCODING传递，只作用于对应的数据源，
+// 不修改进程级的SHAPE_ENCODING配置，多个goroutine可同时读写不同编码的文件。
+// 编码判断顺序：显式指定 > .cpg（与DBF内容不符时忽略）> DBF头LDID > 采样DBF字节 > GBK。
+// ============================================================================
+
+// Shapefile常用编码
+const (
+	SHPEncodingUTF8    = "UTF-8"
+	SHPEncodingGBK     = "GBK"
+	SHPEncodingGB18030 = "GB18030"
+)
+
+// dbfLDIDEncodings DBF头部语言驱动ID（第29字节）对应的编码，只列出明确的代码页
+var dbfLDIDEncodings = map[byte]string{
+	0x4D: SHPEncodingGBK, // 936 简体中文
+	0x4E: "CP949",        // 韩文
+	0x4F: "CP950",        // 繁体中文
+	0x13: "CP932",        // 日文
+	0x57: "CP1252",       // ANSI
+}
+
+// dbfSampleLimit 编码检测时最多读取的DBF记录字节数
+const dbfSampleLimit = 4 << 20
+
+// DetectSHPEncoding 检测Shapefile的DBF编码
+func DetectSHPEncoding(shpPath string) string {
+	return detectSHPEncoding(shpPath)
+}
+
+func detectSHPEncoding(shpPath string) string {
+	basePath := strings.TrimSuffix(shpPath, filepath.Ext(shpPath))
+	sampled := sampleDBFEncoding(basePath + ".dbf")
+
+	// .cpg 文件
+	if content, err := os.ReadFile(basePath + ".cpg"); err == nil {
+		if encoding := normalizeSHPEncoding(string(content)); encoding != "" {
+			// 声明为UTF-8但内容不是UTF-8时（常见于GBK数据配了错误的.cpg），以采样结果为准
+			if encoding == SHPEncodingUTF8 && sampled != "" && sampled != SHPEncodingUTF8 {
+				return sampled
+			}
+			return encoding
+		}
+	}
+
+	// DBF头部LDID
+	if ldid, err := readDBFLanguageDriver(basePath + ".dbf"); err == nil {
+		if encoding, ok := dbfLDIDEncodings[ldid]; ok && (sampled == "" || encoding == sampled) {
+			return encoding
+		}
+	}
+
+	if sampled != "" {
+		return sampled
+	}
+
+	// 默认返回GBK（中国常用编码）
+	return SHPEncodingGBK
+}
+
+// openShapefile 以指定编码打开Shapefile，update为true时以可写模式打开
+func openShapefile(shpPath string, update bool, encoding string) C.OGRDataSourceH {
+	cPath := C.CString(shpPath)
+	defer C.free(unsafe.Pointer(cPath))
+	cDriver := C.CString("ESRI Shapefile")
+	defer C.free(unsafe.Pointer(cDriver))
+	cEncoding := C.CString("ENCODING=" + encoding)
+	defer C.free(unsafe.Pointer(cEncoding))
+
+	drivers := C.CSLAddString(nil, cDriver)
+	defer C.CSLDestroy(drivers)
+	openOptions := C.CSLAddString(nil, cEncoding)
+	defer C.CSLDestroy(openOptions)
+
+	flags := C.uint(C.GDAL_OF_VECTOR)
+	if update {
+		flags |= C.GDAL_OF_UPDATE
+	}
+	return C.OGRDataSourceH(C.GDALOpenEx(cPath, flags, drivers, openOptions, nil))
+}
+
+// normalizeSHPEncoding 规范化编码名称（.cpg内容或用户输入）
+func normalizeSHPEncoding(name string) string {
+	name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+	upper := strings.ToUpper(strings.ReplaceAll(name, "_", "-"))
+	switch upper {
+	case "":
+		return ""
+	case "UTF-8", "UTF8", "65001":
+		return SHPEncodingUTF8
+	case "GBK", "GB2312", "CP936", "936", "ANSI 936", "MS936", "EUC-CN":
+		return SHPEncodingGBK
+	case "GB18030", "54936", "CP54936":
+		return SHPEncodingGB18030
+	}
+	if isDigits(upper) {
+		return "CP" + upper
+	}
+	return name
+}
+
+// shpCPGValue 写入.cpg的内容，使用ArcGIS识别的代码页写法
+func shpCPGValue(encoding string) string {
+	switch encoding {
+	case SHPEncodingUTF8:
+		return "UTF-8"
+	case SHPEncodingGBK:
+		return "936"
+	case SHPEncodingGB18030:
+		return "54936"
+	}
+	if strings.HasPrefix(encoding, "CP") && isDigits(encoding[2:]) {
+		return encoding[2:]
+	}
+	return encoding
+}
+
+// shpLDID 编码对应的LDID，没有对应值时为0（由.cpg决定编码）
+func shpLDID(encoding string) byte {
+	for ldid, name := range dbfLDIDEncodings {
+		if name == encoding && ldid != 0x57 {
+			return ldid
+		}
+	}
+	return 0
+}
+
+func isDigits(s string) bool {
+	if s == "" {
+		return false
+	}
+	for _, r := range s {
+		if r < '0' || r > '9' {
+			return false
+		}
+	}
+	return true
+}
+
+// readDBFLanguageDriver 读取DBF头部的LDID
+func readDBFLanguageDriver(dbfPath string) (byte, error) {
+	file, err := os.Open(dbfPath)
+	if err != nil {
+		return 0, err
+	}
+	defer file.Close()
+
+	header := make([]byte, 32)
+	if _, err := io.ReadFull(file, header); err != nil {
+		return 0, err
+	}
+	return header[29], nil
+}
+
+// writeSHPEncodingMarkers 写入.cpg并设置DBF头部的LDID，在数据源关闭后调用
+func writeSHPEncodingMarkers(shpPath, encoding string) error {
+	basePath := strings.TrimSuffix(shpPath, filepath.Ext(shpPath))
+	if err := createCpgFile(basePath+".cpg", encoding); err != nil {
+		return err
+	}
+
+	file, err := os.OpenFile(basePath+".dbf", os.O_RDWR, 0)
+	if err != nil {
+		return fmt.Errorf("无法打开DBF文件: %v", err)
+	}
+	defer file.Close()
+	if _, err := file.WriteAt([]byte{shpLDID(encoding)}, 29); err != nil {
+		return fmt.Errorf("写入DBF编码标识失败: %v", err)
+	}
+	return nil
+}
+
+// sampleDBFEncoding 采样DBF字段名和字符型字段的字节判断编码，全部为ASCII或无法判断时返回空
+func sampleDBFEncoding(dbfPath string) string {
+	file, err := os.Open(dbfPath)
+	if err != nil {
+		return ""
+	}
+	defer file.Close()
+
+	header := make([]byte, 32)
+	if _, err := io.ReadFull(file, header); err != nil {
+		return ""
+	}
+	recordCount := int(binary.LittleEndian.Uint32(header[4:8]))
+	headerLength := int(binary.LittleEndian.Uint16(header[8:10]))
+	recordLength := int(binary.LittleEndian.Uint16(header[10:12]))
+	if headerLength <= 32 || recordLength <= 1 {
+		return ""
+	}
+
+	descriptors := make([]byte, headerLength-32)
+	if _, err := io.ReadFull(file, descriptors); err != nil {
+		return ""
+	}
+
+	type charField struct{ offset, length int }
+	var samples [][]byte
+	var fields []charField
+	offset := 1 // 删除标记
+	for i := 0; i+32 <= len(descriptors) && descriptors[i] != 0x0D; i += 32 {
+		descriptor := descriptors[i : i+32]
+		samples = append(samples, trimDBFValue(descriptor[:11]))
+		length := int(descriptor[16])
+		if descriptor[11] == 'C' {
+			length += int(descriptor[17]) << 8 // 字符型字段长度可使用小数位字节扩展
+			fields = append(fields, charField{offset, length})
+		}
+		offset += length
+	}
+
+	if len(fields) > 0 {
+		sampleRecords := recordCount
+		if limit := dbfSampleLimit / recordLength; sampleRecords > limit {
+			sampleRecords = limit
+		}
+		record := make([]byte, recordLength)
+		for i := 0; i < sampleRecords; i++ {
+			if _, err := io.ReadFull(file, record); err != nil {
+				break
+			}
+			for _, field := range fields {
+				if field.offset+field.length <= len(record) {
+					samples = append(samples, trimDBFValue(record[field.offset:field.offset+field.length]))
+				}
+			}
+		}
+	}
+	return classifyEncoding(samples)
+}
+
+// trimDBFValue 去掉DBF值末尾的空格和空字符
+func trimDBFValue(value []byte) []byte {
+	end := len(value)
+	for end > 0 && (value[end-1] == ' ' || value[end-1] == 0) {
+		end--
+	}
+	start := 0
+	for start < end && value[start] == ' ' {
+		start++
+	}
+	return value[start:end]
+}
+
+// classifyEncoding 根据样本判断编码：全部为合法UTF-8时为UTF-8，否则按GBK/GB18030双字节、四字节规则判断
+// 字段宽度截断会使末尾的多字节字符不完整，判断时忽略末尾不完整的字符。
+func classifyEncoding(samples [][]byte) string {
+	nonASCII, utf8Valid, gbkValid, gb18030Valid, fourByte := 0, 0, 0, 0, false
+	for _, sample := range samples {
+		if isASCII(sample) {
+			continue
+		}
+		nonASCII++
+		if validUTF8Prefix(sample) {
+			utf8Valid++
+		}
+		valid, four := validGB18030(sample)
+		if valid {
+			gb18030Valid++
+			if four {
+				fourByte = true
+			} else {
+				gbkValid++
+			}
+		}
+	}
+
+	switch {
+	case nonASCII == 0:
+		return ""
+	case utf8Valid == nonASCII:
+		return SHPEncodingUTF8
+	case gbkValid == nonASCII:
+		return SHPEncodingGBK
+	case gb18030Valid == nonASCII && fourByte:
+		return SHPEncodingGB18030
+	}
+	return ""
+}
+
+func isASCII(value []byte) bool {
+	for _, b := range value {
+		if b >= 0x80 {
+			return false
+		}
+	}
+	return true
+}
+
+// validUTF8Prefix 是否为合法UTF-8（允许末尾有被截断的字符）
+func validUTF8Prefix(value []byte) bool {
+	if utf8.Valid(value) {
+		return true
+	}
+	for cut := 1; cut <= 3 && cut < len(value); cut++ {
+		tail := value[len(value)-cut:]
+		if utf8.RuneStart(tail[0]) && tail[0] >= 0xC0 && !utf8.FullRune(tail) {
+			return utf8.Valid(value[:len(value)-cut])
+		}
+	}
+	return false
+}
+
+// validGB18030 是否为合法GBK/GB18030字节序列，four表示包含四字节序列（GBK不支持）
+func validGB18030(value []byte) (valid bool, four bool) {
+	for i := 0; i < len(value); {
+		b := value[i]
+		switch {
+		case b < 0x80:
+			i++
+		case b == 0x80 || b == 0xFF:
+			return false, four
+		case i+1 >= len(value):
+			return true, four // 末尾被截断的首字节
+		case value[i+1] >= 0x40 && value[i+1] <= 0xFE && value[i+1] != 0x7F:
+			i += 2
+		case value[i+1] >= 0x30 && value[i+1] <= 0x39:
+			if i+3 >= len(value) {
+				return true, true
+			}
+			if value[i+2] < 0x81 || value[i+2] > 0xFE || value[i+3] < 0x30 || value[i+3] > 0x39 {
+				return false, four
+			}
+			four = true
+			i += 4
+		default:
+			return false, four
+		}
+	}
+	return true, four
+}
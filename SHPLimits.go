@@ -0,0 +1,371 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+/*
+#include "osgeo_utils.h"
+*/
+import "C"
+import (
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"unicode/utf8"
+	"unsafe"
+)
+
+// ============================================================================
+// Shapefile 限制处理
+// 1. 字段名限制为10字节：按字段顺序生成确定且唯一的短字段名，并写入
+//    <名称>.fieldmap.json 记录原字段名，ReadShapeFile 读取时恢复原字段名。
+// 2. .shp/.dbf 限制为2GB：写入时估算文件大小，超过限制时自动分为
+//    <名称>.shp、<名称>_2.shp、<名称>_3.shp ... 多个部分。
+// ============================================================================
+
+const (
+	shpFieldNameLimit  = 10               // DBF字段名最大字节数
+	shpDefaultPartSize = int64(2<<30 - 1) // 单个.shp/.dbf文件的默认大小上限
+	shpFieldMapSuffix  = ".fieldmap.json"
+)
+
+// SHPFieldMapping 短字段名与原字段名的对应关系
+type SHPFieldMapping struct {
+	Name     string `json:"name"`     // Shapefile中的字段名
+	Original string `json:"original"` // 原字段名
+}
+
+// shpFieldMapFile 字段名映射文件内容
+type shpFieldMapFile struct {
+	Version int               `json:"version"`
+	Fields  []SHPFieldMapping `json:"fields"`
+}
+
+// shapefileFieldNames 为字段生成Shapefile字段名，返回原字段名到短字段名的映射
+// 字段名按目标编码截断到10字节；截断后重名（不区分大小写）时依次使用 _1、_2 ... 后缀，
+// 相同的字段顺序总是得到相同的结果。
+func shapefileFieldNames(names []string, encoding string) map[string]string {
+	result := make(map[string]string, len(names))
+	used := make(map[string]bool, len(names))
+
+	for _, original := range names {
+		if _, exists := result[original]; exists {
+			continue
+		}
+		base := replaceFieldNameChars(original)
+		if base == "" {
+			base = "field"
+		}
+		name := truncateEncoded(base, shpFieldNameLimit, encoding)
+		for n := 1; used[strings.ToUpper(name)]; n++ {
+			suffix := "_" + strconv.Itoa(n)
+			name = truncateEncoded(base, shpFieldNameLimit-len(suffix), encoding) + suffix
+		}
+		used[strings.ToUpper(name)] = true
+		result[original] = name
+	}
+	return result
+}
+
+// truncateEncoded 按目标编码的字节数截断字符串，不截断半个字符
+// GBK/GB18030中汉字按2字节计算，UTF-8按实际字节数计算。
+func truncateEncoded(value string, limit int, encoding string) string {
+	size := 0
+	for i, r := range value {
+		runeSize := 1
+		if r >= utf8.RuneSelf {
+			if encoding == SHPEncodingUTF8 {
+				runeSize = utf8.RuneLen(r)
+			} else if r > 0xFFFF {
+				runeSize = 4
+			} else {
+				runeSize = 2
+			}
+		}
+		if size+runeSize > limit {
+			return value[:i]
+		}
+		size += runeSize
+	}
+	return value
+}
+
+// writeSHPFieldMap 写入字段名映射文件，所有字段名都未改变时删除映射文件
+func writeSHPFieldMap(shpPath string, names []string, mapping map[string]string) error {
+	mapPath := strings.TrimSuffix(shpPath, filepath.Ext(shpPath)) + shpFieldMapSuffix
+
+	content := shpFieldMapFile{Version: 1}
+	changed := false
+	for _, original := range names {
+		name, ok := mapping[original]
+		if !ok {
+			continue
+		}
+		content.Fields = append(content.Fields, SHPFieldMapping{Name: name, Original: original})
+		if name != original {
+			changed = true
+		}
+	}
+	if !changed {
+		os.Remove(mapPath)
+		return nil
+	}
+
+	data, err := json.MarshalIndent(content, "", "  ")
+	if err != nil {
+		return fmt.Errorf("生成字段名映射失败: %v", err)
+	}
+	if err := os.WriteFile(mapPath, data, 0644); err != nil {
+		return fmt.Errorf("写入字段名映射文件失败: %v", err)
+	}
+	return nil
+}
+
+// ReadSHPFieldMap 读取Shapefile的字段名映射，没有映射文件时返回nil
+func ReadSHPFieldMap(shpPath string) ([]SHPFieldMapping, error) {
+	mapPath := strings.TrimSuffix(shpPath, filepath.Ext(shpPath)) + shpFieldMapSuffix
+	data, err := os.ReadFile(mapPath)
+	if os.IsNotExist(err) {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("读取字段名映射文件失败: %v", err)
+	}
+
+	var content shpFieldMapFile
+	if err := json.Unmarshal(data, &content); err != nil {
+		return nil, fmt.Errorf("解析字段名映射文件失败: %v", err)
+	}
+	return content.Fields, nil
+}
+
+// restoreSHPFieldNames 按映射文件通过OGR SQL将短字段名还原为原字段名，返回结果图层
+// 没有映射文件或映射无效时返回nil。
+func restoreSHPFieldNames(dataset C.OGRDataSourceH, layer C.OGRLayerH, shpPath string) (C.OGRLayerH, error) {
+	mappings, err := ReadSHPFieldMap(shpPath)
+	if err != nil || len(mappings) == 0 {
+		return nil, err
+	}
+	originals := make(map[string]string, len(mappings))
+	for _, mapping := range mappings {
+		if mapping.Original != "" && !strings.Contains(mapping.Original, `"`) {
+			originals[strings.ToUpper(mapping.Name)] = mapping.Original
+		}
+	}
+
+	defn := C.OGR_L_GetLayerDefn(layer)
+	fieldCount := int(C.OGR_FD_GetFieldCount(defn))
+	columns := make([]string, 0, fieldCount)
+	renamed := false
+	for i := 0; i < fieldCount; i++ {
+		name := C.GoString(C.OGR_Fld_GetNameRef(C.OGR_FD_GetFieldDefn(defn, C.int(i))))
+		column := `"` + name + `"`
+		if original, ok := originals[strings.ToUpper(name)]; ok && original != name {
+			column += ` AS "` + original + `"`
+			renamed = true
+		}
+		columns = append(columns, column)
+	}
+	if !renamed {
+		return nil, nil
+	}
+
+	layerName := C.GoString(C.OGR_L_GetName(layer))
+	sql := fmt.Sprintf(`SELECT %s FROM "%s"`, strings.Join(columns, ", "), layerName)
+	cSQL := C.CString(sql)
+	defer C.free(unsafe.Pointer(cSQL))
+
+	result := C.OGR_DS_ExecuteSQL(dataset, cSQL, nil, nil)
+	if result == nil {
+		return nil, fmt.Errorf("还原字段名失败: %s", sql)
+	}
+	return result, nil
+}
+
+// shpPartPath 第n个分块的文件路径，第1块为原路径
+func shpPartPath(shpPath string, part int) string {
+	if part <= 1 {
+		return shpPath
+	}
+	ext := filepath.Ext(shpPath)
+	return fmt.Sprintf("%s_%d%s", strings.TrimSuffix(shpPath, ext), part, ext)
+}
+
+// shapefilePart 正在写入的Shapefile分块
+type shapefilePart struct {
+	path      string
+	dataset   C.OGRDataSourceH
+	layer     C.OGRLayerH
+	defn      C.OGRFeatureDefnH
+	recordLen int64 // DBF记录长度
+	shpSize   int64 // .shp估算大小
+	dbfSize   int64 // .dbf估算大小
+	count     int
+}
+
+// fits 加入大小为geomSize的要素后是否仍在限制内
+func (p *shapefilePart) fits(geomSize, limit int64) bool {
+	return p.shpSize+geomSize <= limit && p.dbfSize+p.recordLen <= limit
+}
+
+func (p *shapefilePart) add(geomSize int64) {
+	p.shpSize += geomSize
+	p.dbfSize += p.recordLen
+	p.count++
+}
+
+// writeShapefileParts 写入Shapefile，超过大小限制时分块，返回写入的分块数
+func (w *FileGeoWriter) writeShapefileParts(sourceLayer *GDALLayer, layerName string, encoding string) (int, error) {
+	limit := w.SHPMaxPartSize
+	if limit <= 0 {
+		limit = shpDefaultPartSize
+	}
+
+	// 生成字段名映射，建字段和复制字段值时使用同一映射
+	sourceDefn := sourceLayer.GetLayerDefn()
+	fieldCount := int(C.OGR_FD_GetFieldCount(sourceDefn))
+	names := make([]string, 0, fieldCount)
+	for i := 0; i < fieldCount; i++ {
+		names = append(names, C.GoString(C.OGR_Fld_GetNameRef(C.OGR_FD_GetFieldDefn(sourceDefn, C.int(i)))))
+	}
+	fieldNames := shapefileFieldNames(names, encoding)
+
+	closePart := func(part *shapefilePart) error {
+		C.OGR_DS_Destroy(part.dataset)
+		if err := writeSHPEncodingMarkers(part.path, encoding); err != nil {
+			return err
+		}
+		return writeSHPFieldMap(part.path, names, fieldNames)
+	}
+
+	partNumber := 1
+	part, err := w.createShapefilePart(sourceLayer, shpPartPath(w.FilePath, partNumber), layerName, encoding, fieldNames)
+	if err != nil {
+		return 0, err
+	}
+
+	sourceLayer.ResetReading()
+	for {
+		sourceFeature := sourceLayer.GetNextFeatureRow()
+		if sourceFeature == nil {
+			break
+		}
+
+		geomSize := estimateSHPRecordSize(C.OGR_F_GetGeometryRef(sourceFeature))
+		if part.count > 0 && !part.fits(geomSize, limit) {
+			if err := closePart(part); err != nil {
+				C.OGR_F_Destroy(sourceFeature)
+				return partNumber, err
+			}
+			partNumber++
+			fmt.Printf("Shapefile超过大小限制，写入分块: %s\n", shpPartPath(w.FilePath, partNumber))
+			if part, err = w.createShapefilePart(sourceLayer, shpPartPath(w.FilePath, partNumber), layerName, encoding, fieldNames); err != nil {
+				C.OGR_F_Destroy(sourceFeature)
+				return partNumber - 1, err
+			}
+		}
+
+		// 与copyFeatures一致，跳过无法复制的要素
+		func() {
+			defer func() {
+				recover()
+				C.OGR_F_Destroy(sourceFeature)
+			}()
+			if w.copyFeatureSafely(sourceFeature, part.layer, part.defn, fieldNames) == nil {
+				part.add(geomSize)
+			}
+		}()
+	}
+
+	return partNumber, closePart(part)
+}
+
+// createShapefilePart 创建一个Shapefile分块并创建字段
+func (w *FileGeoWriter) createShapefilePart(sourceLayer *GDALLayer, path, layerName, encoding string, fieldNames map[string]string) (*shapefilePart, error) {
+	cDriverName := C.CString("ESRI Shapefile")
+	defer C.free(unsafe.Pointer(cDriverName))
+	driver := C.OGRGetDriverByName(cDriverName)
+	if driver == nil {
+		return nil, fmt.Errorf("无法获取Shapefile驱动")
+	}
+
+	cFilePath := C.CString(path)
+	defer C.free(unsafe.Pointer(cFilePath))
+	dataset := C.OGR_Dr_CreateDataSource(driver, cFilePath, nil)
+	if dataset == nil {
+		return nil, fmt.Errorf("无法创建Shapefile: %s", path)
+	}
+
+	sourceDefn := sourceLayer.GetLayerDefn()
+	cLayerName := C.CString(layerName)
+	defer C.free(unsafe.Pointer(cLayerName))
+	cEncodingOption := C.CString("ENCODING=" + encoding)
+	defer C.free(unsafe.Pointer(cEncodingOption))
+	layerOptions := C.CSLAddString(nil, cEncodingOption)
+	defer C.CSLDestroy(layerOptions)
+
+	layer := C.OGR_DS_CreateLayer(dataset, cLayerName, sourceLayer.GetSpatialRef(), C.OGR_FD_GetGeomType(sourceDefn), layerOptions)
+	if layer == nil {
+		C.OGR_DS_Destroy(dataset)
+		return nil, fmt.Errorf("无法创建图层: %s", layerName)
+	}
+	if err := w.copyFieldDefinitions(sourceDefn, layer, fieldNames); err != nil {
+		C.OGR_DS_Destroy(dataset)
+		return nil, err
+	}
+
+	// DBF文件头32字节 + 每个字段32字节 + 结束符；记录为删除标记 + 各字段宽度
+	defn := C.OGR_L_GetLayerDefn(layer)
+	fieldCount := int(C.OGR_FD_GetFieldCount(defn))
+	recordLen := int64(1)
+	for i := 0; i < fieldCount; i++ {
+		fieldDefn := C.OGR_FD_GetFieldDefn(defn, C.int(i))
+		width := int64(C.OGR_Fld_GetWidth(fieldDefn))
+		if width <= 0 {
+			width = 254
+			if C.OGR_Fld_GetType(fieldDefn) == C.OFTDate {
+				width = 8
+			}
+		}
+		recordLen += width
+	}
+
+	return &shapefilePart{
+		path:      path,
+		dataset:   dataset,
+		layer:     layer,
+		defn:      defn,
+		recordLen: recordLen,
+		shpSize:   100,
+		dbfSize:   int64(32 + 32*fieldCount + 2),
+	}, nil
+}
+
+// estimateSHPRecordSize 估算要素在.shp中的字节数（记录头 + 形状内容）
+// 形状内容与WKB大小相近，线面另加边界框和部件数等44字节。
+func estimateSHPRecordSize(geometry C.OGRGeometryH) int64 {
+	if geometry == nil {
+		return 12
+	}
+	size := int64(C.OGR_G_WkbSize(geometry)) + 8
+	if C.OGR_GT_Flatten(C.OGR_G_GetGeometryType(geometry)) != C.wkbPoint {
+		size += 44
+	}
+	return size
+}
@@ -0,0 +1,562 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+/*
+#include "osgeo_utils.h"
+*/
+import "C"
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"log"
+	"strings"
+
+	"github.com/jackc/pgx/v5"
+	"gorm.io/gorm"
+)
+
+// ============================================================================
+// PostGIS 表结构演进
+// 将图层字段定义与已有表比较（类型映射见 field_mapping.go），按策略新增列、
+// 加宽 varchar/numeric/整数列，并报告不兼容的类型变化。
+// ============================================================================
+
+// SchemaPolicy 表结构演进策略
+type SchemaPolicy int
+
+const (
+	SchemaStrict   SchemaPolicy = iota // 结构必须一致，任何差异都报错
+	SchemaAdditive                     // 允许新增列，列需要加宽时报错
+	SchemaWiden                        // 允许新增列和加宽列
+)
+
+// String 策略名称
+func (p SchemaPolicy) String() string {
+	switch p {
+	case SchemaStrict:
+		return "strict"
+	case SchemaAdditive:
+		return "additive"
+	case SchemaWiden:
+		return "widen"
+	default:
+		return fmt.Sprintf("SchemaPolicy(%d)", int(p))
+	}
+}
+
+// SchemaChangeKind 结构变化类型
+type SchemaChangeKind string
+
+const (
+	SchemaChangeAdd          SchemaChangeKind = "add"          // 新增列
+	SchemaChangeWiden        SchemaChangeKind = "widen"        // 加宽列
+	SchemaChangeIncompatible SchemaChangeKind = "incompatible" // 不兼容，无法自动处理
+	SchemaChangeReproject    SchemaChangeKind = "reproject"    // 几何坐标系与目标列不同，写入时转换（不修改表结构）
+)
+
+// SchemaChange 单个列的结构变化
+type SchemaChange struct {
+	Kind        SchemaChangeKind
+	Column      string
+	CurrentType string // 目标表中的类型，新增列为空
+	NewType     string // 需要的类型，不兼容时为源字段对应的类型
+	Reason      string
+}
+
+// SchemaReconcileResult 表结构比较/调整结果
+type SchemaReconcileResult struct {
+	Policy  SchemaPolicy
+	Changes []SchemaChange
+	Applied bool // 变化是否已写入数据库
+}
+
+// HasChanges 是否存在结构差异
+func (r *SchemaReconcileResult) HasChanges() bool {
+	return len(r.Changes) > 0
+}
+
+// Incompatible 返回不兼容的变化
+func (r *SchemaReconcileResult) Incompatible() []SchemaChange {
+	var changes []SchemaChange
+	for _, change := range r.Changes {
+		if change.Kind == SchemaChangeIncompatible {
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// String 结构变化报告
+func (r *SchemaReconcileResult) String() string {
+	if len(r.Changes) == 0 {
+		return "表结构一致"
+	}
+	var sb strings.Builder
+	for i, change := range r.Changes {
+		if i > 0 {
+			sb.WriteString("\n")
+		}
+		switch change.Kind {
+		case SchemaChangeAdd:
+			sb.WriteString(fmt.Sprintf("新增列 %s %s", change.Column, change.NewType))
+		case SchemaChangeWiden:
+			sb.WriteString(fmt.Sprintf("加宽列 %s: %s -> %s", change.Column, change.CurrentType, change.NewType))
+		case SchemaChangeReproject:
+			sb.WriteString(fmt.Sprintf("转换坐标系 %s: %s", change.Column, change.Reason))
+		default:
+			sb.WriteString(fmt.Sprintf("不兼容 %s: %s", change.Column, change.Reason))
+		}
+	}
+	return sb.String()
+}
+
+// violations 返回当前策略不允许的变化
+func (r *SchemaReconcileResult) violations() []SchemaChange {
+	var changes []SchemaChange
+	for _, change := range r.Changes {
+		switch {
+		case change.Kind == SchemaChangeIncompatible,
+			change.Kind == SchemaChangeAdd && r.Policy == SchemaStrict,
+			change.Kind == SchemaChangeWiden && r.Policy != SchemaWiden:
+			changes = append(changes, change)
+		}
+	}
+	return changes
+}
+
+// pgColumnInfo 已有表的列信息
+type pgColumnInfo struct {
+	name       string
+	sqlType    string
+	notNull    bool
+	hasDefault bool
+}
+
+// ComparePGSchema 比较图层字段与已有表的结构，不修改数据库
+func ComparePGSchema(DB *gorm.DB, gdalLayer *GDALLayer, tableName string, schema string) (*SchemaReconcileResult, error) {
+	return reconcilePGSchema(DB, gdalLayer, tableName, schema, SchemaWiden, false)
+}
+
+// ReconcilePGSchema 按策略调整已有表的结构，所有ALTER在一个事务中执行
+// 存在策略不允许的变化或不兼容的类型变化时不做任何修改，返回结果和错误。
+func ReconcilePGSchema(DB *gorm.DB, gdalLayer *GDALLayer, tableName string, schema string, policy SchemaPolicy) (*SchemaReconcileResult, error) {
+	return reconcilePGSchema(DB, gdalLayer, tableName, schema, policy, true)
+}
+
+func reconcilePGSchema(DB *gorm.DB, gdalLayer *GDALLayer, tableName, schema string, policy SchemaPolicy, apply bool) (*SchemaReconcileResult, error) {
+	if gdalLayer == nil || gdalLayer.layer == nil {
+		return nil, fmt.Errorf("无效的GDALLayer")
+	}
+	if schema == "" {
+		schema = "public"
+	}
+
+	db, err := DB.DB()
+	if err != nil {
+		return nil, fmt.Errorf("获取数据库连接失败: %v", err)
+	}
+
+	qualified := quoteIdentifier(schema) + "." + quoteIdentifier(tableName)
+	columns, err := queryPGColumns(db, qualified)
+	if err != nil {
+		return nil, err
+	}
+
+	fields, err := analyzeFields(gdalLayer.GetLayerDefn())
+	if err != nil {
+		return nil, fmt.Errorf("分析字段失败: %v", err)
+	}
+
+	result := &SchemaReconcileResult{Policy: policy, Changes: planSchemaChanges(fields, gdalLayer.GetLayerDefn(), columns)}
+	result.Changes = append(result.Changes, planGeometryChanges(gdalLayer, columns)...)
+	if !apply {
+		return result, nil
+	}
+	if violations := result.violations(); len(violations) > 0 {
+		report := &SchemaReconcileResult{Changes: violations}
+		return result, fmt.Errorf("表 %s.%s 的结构与图层不一致（策略: %s）:\n%s", schema, tableName, policy, report)
+	}
+	var alterations []SchemaChange
+	for _, change := range result.Changes {
+		if change.Kind == SchemaChangeAdd || change.Kind == SchemaChangeWiden {
+			alterations = append(alterations, change)
+		}
+	}
+	if len(alterations) == 0 {
+		return result, nil
+	}
+
+	tx, err := db.Begin()
+	if err != nil {
+		return result, fmt.Errorf("开始事务失败: %v", err)
+	}
+	for _, change := range alterations {
+		var statement string
+		if change.Kind == SchemaChangeAdd {
+			statement = fmt.Sprintf("ALTER TABLE %s ADD COLUMN %s %s", qualified, quoteIdentifier(change.Column), change.NewType)
+		} else {
+			statement = fmt.Sprintf("ALTER TABLE %s ALTER COLUMN %s TYPE %s", qualified, quoteIdentifier(change.Column), change.NewType)
+		}
+		if _, err := tx.Exec(statement); err != nil {
+			tx.Rollback()
+			return result, fmt.Errorf("调整表结构失败 (%s): %v", statement, err)
+		}
+	}
+	if err := tx.Commit(); err != nil {
+		return result, fmt.Errorf("提交事务失败: %v", err)
+	}
+	result.Applied = true
+
+	log.Printf("已调整表 %s.%s 的结构:\n%s", schema, tableName, result)
+	return result, nil
+}
+
+// queryPGColumns 查询表的列定义
+func queryPGColumns(db *sql.DB, qualified string) (map[string]pgColumnInfo, error) {
+	rows, err := db.Query(`
+		SELECT attname, format_type(atttypid, atttypmod), attnotnull, atthasdef
+		FROM pg_attribute
+		WHERE attrelid = to_regclass($1) AND attnum > 0 AND NOT attisdropped`, qualified)
+	if err != nil {
+		return nil, fmt.Errorf("查询表结构失败: %v", err)
+	}
+	defer rows.Close()
+
+	columns := make(map[string]pgColumnInfo)
+	for rows.Next() {
+		var column pgColumnInfo
+		if err := rows.Scan(&column.name, &column.sqlType, &column.notNull, &column.hasDefault); err != nil {
+			return nil, fmt.Errorf("查询表结构失败: %v", err)
+		}
+		columns[column.name] = column
+	}
+	if err := rows.Err(); err != nil {
+		return nil, fmt.Errorf("查询表结构失败: %v", err)
+	}
+	if len(columns) == 0 {
+		return nil, fmt.Errorf("目标表不存在: %s", qualified)
+	}
+	return columns, nil
+}
+
+// planSchemaChanges 比较源字段和目标列，生成结构变化列表
+// 与 createTableFromLayerInfo 一致，字段名按小写匹配，跳过id和geom。
+func planSchemaChanges(fields []FieldAnalysisResult, defn C.OGRFeatureDefnH, columns map[string]pgColumnInfo) []SchemaChange {
+	reserved := map[string]bool{"id": true, "geom": true}
+	var changes []SchemaChange
+	seen := make(map[string]bool)
+
+	for _, field := range fields {
+		name := strings.ToLower(field.Name)
+		if reserved[name] || seen[name] {
+			continue
+		}
+		seen[name] = true
+
+		fieldType := FieldType(C.OGR_Fld_GetType(C.OGR_FD_GetFieldDefn(defn, C.int(field.Index))))
+		column, exists := columns[name]
+		if !exists {
+			changes = append(changes, SchemaChange{
+				Kind:    SchemaChangeAdd,
+				Column:  name,
+				NewType: mapGDBTypeToPostGIS(fieldType, field.Width, field.Precision),
+				Reason:  "目标表中没有该列",
+			})
+			continue
+		}
+		if change, ok := compareFieldWithColumn(name, fieldType, field.Width, field.Precision, column.sqlType); ok {
+			changes = append(changes, change)
+		}
+	}
+
+	// 源数据中没有、非空且无默认值的列会导致写入失败
+	for name, column := range columns {
+		if reserved[name] || seen[name] || !column.notNull || column.hasDefault {
+			continue
+		}
+		changes = append(changes, SchemaChange{
+			Kind:        SchemaChangeIncompatible,
+			Column:      name,
+			CurrentType: column.sqlType,
+			Reason:      fmt.Sprintf("列 %s 为非空且无默认值，但图层中没有该字段", name),
+		})
+	}
+	return changes
+}
+
+// planGeometryChanges 比较图层几何类型、坐标系与目标表的geom列
+// 几何类型不同或多部件写入单部件列时不兼容；坐标系不同时写入时转换为目标列的SRID。
+func planGeometryChanges(gdalLayer *GDALLayer, columns map[string]pgColumnInfo) []SchemaChange {
+	layerType := C.OGR_FD_GetGeomType(gdalLayer.GetLayerDefn())
+	if layerType == C.wkbNone {
+		return nil
+	}
+	column, exists := columns["geom"]
+	if !exists {
+		return []SchemaChange{{
+			Kind:    SchemaChangeIncompatible,
+			Column:  "geom",
+			NewType: convertOGRGeometryTypeForPG(C.OGR_GT_Flatten(layerType)),
+			Reason:  "目标表中没有几何列geom",
+		}}
+	}
+	typeName, _, srid, typed := parsePGGeometryType(column.sqlType)
+	if !typed {
+		return nil
+	}
+
+	var changes []SchemaChange
+	sourceType := convertOGRGeometryTypeForPG(C.OGR_GT_Flatten(layerType))
+	targetType := strings.ToUpper(typeName)
+	// 单部件可提升为对应的多部件；未知类型的图层只能在写入时检查
+	if targetType != "GEOMETRY" && sourceType != "GEOMETRY" && sourceType != targetType && "MULTI"+sourceType != targetType {
+		changes = append(changes, SchemaChange{
+			Kind:        SchemaChangeIncompatible,
+			Column:      "geom",
+			CurrentType: column.sqlType,
+			NewType:     sourceType,
+			Reason:      fmt.Sprintf("图层几何类型 %s 无法写入 %s 列", sourceType, column.sqlType),
+		})
+	}
+	if layerEPSG := gdalLayer.GetEPSGCode(); srid > 0 && layerEPSG > 0 && layerEPSG != srid {
+		changes = append(changes, SchemaChange{
+			Kind:        SchemaChangeReproject,
+			Column:      "geom",
+			CurrentType: column.sqlType,
+			Reason:      fmt.Sprintf("EPSG:%d -> EPSG:%d", layerEPSG, srid),
+		})
+	}
+	return changes
+}
+
+// compareFieldWithColumn 比较单个字段与已有列，ok为false表示无需变化
+func compareFieldWithColumn(name string, fieldType FieldType, width, precision int, pgType string) (SchemaChange, bool) {
+	pgType = strings.ToLower(strings.TrimSpace(pgType))
+	baseType, params := parsePostGISType(pgType)
+	targetType, targetWidth, targetPrecision := mapPostGISTypeToGDB(pgType)
+	sourceType := mapGDBTypeToPostGIS(fieldType, width, precision)
+
+	widen := func(newType string) (SchemaChange, bool) {
+		return SchemaChange{Kind: SchemaChangeWiden, Column: name, CurrentType: pgType, NewType: newType}, true
+	}
+	incompatible := func(reason string) (SchemaChange, bool) {
+		return SchemaChange{
+			Kind:        SchemaChangeIncompatible,
+			Column:      name,
+			CurrentType: pgType,
+			NewType:     sourceType,
+			Reason:      fmt.Sprintf("%s（列类型 %s，字段类型 %s）", reason, pgType, sourceType),
+		}, true
+	}
+
+	if baseType == "boolean" || baseType == "bool" {
+		return incompatible("布尔列不能写入该类型的值")
+	}
+
+	switch targetType {
+	case FieldTypeString:
+		unlimited := baseType == "text" || (len(params) == 0 && (baseType == "character varying" || baseType == "varchar"))
+		switch baseType {
+		case "text", "character varying", "varchar", "character", "char":
+		default:
+			// uuid、json等以字符串读写的类型，只接受字符串字段，不调整宽度
+			if fieldType == FieldTypeString {
+				return SchemaChange{}, false
+			}
+			return incompatible("类型不同")
+		}
+		if fieldType == FieldTypeBinary {
+			return incompatible("二进制字段不能写入字符串列")
+		}
+		if unlimited {
+			return SchemaChange{}, false
+		}
+		needed := width
+		if fieldType != FieldTypeString {
+			needed = fieldTextWidth(fieldType)
+		}
+		if needed == 0 {
+			return widen("TEXT")
+		}
+		if needed > targetWidth {
+			return widen(mapGDBTypeToPostGIS(FieldTypeString, needed, 0))
+		}
+
+	case FieldTypeInteger:
+		switch fieldType {
+		case FieldTypeInteger:
+			if baseType == "smallint" || baseType == "int2" {
+				return widen("INTEGER")
+			}
+		case FieldTypeInteger64:
+			return widen("BIGINT")
+		case FieldTypeReal:
+			return incompatible("实数不能写入整数列")
+		default:
+			return incompatible("类型不同")
+		}
+
+	case FieldTypeInteger64:
+		if fieldType == FieldTypeReal {
+			return incompatible("实数不能写入整数列")
+		}
+		if fieldType != FieldTypeInteger && fieldType != FieldTypeInteger64 {
+			return incompatible("类型不同")
+		}
+
+	case FieldTypeReal:
+		if fieldType != FieldTypeInteger && fieldType != FieldTypeInteger64 && fieldType != FieldTypeReal {
+			return incompatible("类型不同")
+		}
+		switch baseType {
+		case "real", "float4":
+			return widen("DOUBLE PRECISION")
+		case "numeric", "decimal":
+			if len(params) == 0 {
+				return SchemaChange{}, false // 不限精度
+			}
+			if fieldType == FieldTypeReal && precision == 0 {
+				return widen("NUMERIC")
+			}
+			sourceDigits, sourceScale := width-precision, precision
+			switch fieldType {
+			case FieldTypeInteger:
+				sourceDigits, sourceScale = 10, 0
+			case FieldTypeInteger64:
+				sourceDigits, sourceScale = 19, 0
+			}
+			targetDigits := targetWidth - targetPrecision
+			if sourceDigits > targetDigits || sourceScale > targetPrecision {
+				digits, scale := maxInt(sourceDigits, targetDigits), maxInt(sourceScale, targetPrecision)
+				return widen(fmt.Sprintf("NUMERIC(%d,%d)", digits+scale, scale))
+			}
+		}
+
+	case FieldTypeDate:
+		if fieldType != FieldTypeDate {
+			return incompatible("日期列只能写入日期")
+		}
+
+	case FieldTypeTime:
+		if fieldType != FieldTypeTime {
+			return incompatible("时间列只能写入时间")
+		}
+
+	case FieldTypeDateTime:
+		if fieldType != FieldTypeDate && fieldType != FieldTypeDateTime {
+			return incompatible("时间戳列只能写入日期或日期时间")
+		}
+
+	case FieldTypeBinary:
+		if fieldType != FieldTypeBinary {
+			return incompatible("类型不同")
+		}
+	}
+	return SchemaChange{}, false
+}
+
+// fieldTextWidth 非字符串字段以文本形式写入时需要的长度
+func fieldTextWidth(fieldType FieldType) int {
+	switch fieldType {
+	case FieldTypeInteger:
+		return 11
+	case FieldTypeInteger64:
+		return 20
+	case FieldTypeReal:
+		return 32
+	case FieldTypeDate:
+		return 10
+	case FieldTypeTime:
+		return 15
+	case FieldTypeDateTime:
+		return 26
+	default:
+		return 0
+	}
+}
+
+func maxInt(a, b int) int {
+	if a > b {
+		return a
+	}
+	return b
+}
+
+// SaveGDALLayerToPGWithPolicy 将图层写入PostgreSQL，表已存在时按策略调整结构后追加数据
+// 表不存在时与 SaveGDALLayerToPGBatch 相同（新建表，srid和batchSize仅用于此情况）。
+// 追加时使用COPY写入（见 pgCopyAppendExisting）：几何按目标列的SRID转换，全部数据在一个事务中写入，
+// 任一行无法转换为目标列类型时整体失败；因几何处理失败被跳过的要素数以错误返回。
+func SaveGDALLayerToPGWithPolicy(DB *gorm.DB, gdalLayer *GDALLayer, tableName string, schema string, srid int, batchSize int, policy SchemaPolicy) (*SchemaReconcileResult, error) {
+	if gdalLayer == nil || gdalLayer.layer == nil {
+		return nil, fmt.Errorf("无效的GDALLayer")
+	}
+
+	db, err := DB.DB()
+	if err != nil {
+		return nil, fmt.Errorf("获取数据库连接失败: %v", err)
+	}
+	if schema == "" {
+		schema = "public"
+	}
+	if srid == 0 {
+		srid = 4490
+	}
+	if batchSize <= 0 {
+		batchSize = 1000
+	}
+
+	var exists bool
+	err = db.QueryRow(`SELECT EXISTS (SELECT 1 FROM information_schema.tables WHERE table_schema = $1 AND table_name = $2)`,
+		schema, tableName).Scan(&exists)
+	if err != nil {
+		return nil, fmt.Errorf("检查表存在性失败: %v", err)
+	}
+	if !exists {
+		return &SchemaReconcileResult{Policy: policy}, SaveGDALLayerToPGBatch(DB, gdalLayer, tableName, schema, srid, batchSize)
+	}
+
+	result, err := ReconcilePGSchema(DB, gdalLayer, tableName, schema, policy)
+	if err != nil {
+		return result, err
+	}
+
+	opts := &PGCopyOptions{
+		Schema:           schema,
+		SRID:             srid,
+		GeometryColumn:   "geom",
+		IDColumn:         "id",
+		Mode:             PGCopyAppend,
+		ProgressInterval: 100000,
+	}
+	stats := &PGCopyStats{}
+	err = withPgxConn(DB, func(ctx context.Context, conn *pgx.Conn) error {
+		columns := pgCopyColumns(gdalLayer.GetLayerDefn(), opts)
+		return pgCopyAppendExisting(ctx, conn, gdalLayer, tableName, columns, opts, stats)
+	})
+	if err != nil {
+		return result, fmt.Errorf("追加数据失败: %v", err)
+	}
+	if stats.Skipped > 0 {
+		return result, fmt.Errorf("已向表 %s.%s 追加 %d 个要素，%d 个要素因几何处理失败未写入",
+			schema, tableName, stats.Features, stats.Skipped)
+	}
+
+	log.Printf("成功将图层数据追加到表 %s.%s: %s", schema, tableName, stats)
+	return result, nil
+}
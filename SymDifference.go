@@ -218,6 +218,7 @@ func executeConcurrentSymDifferenceAnalysis(tileGroups []GroupTileFiles, resultL
 				if err != nil {
 					processingError = fmt.Errorf("合并分块 %d 结果失败: %v", result.index, err)
 					log.Printf("错误: %v", processingError)
+					result.layer.Close()
 					return
 				}
 
@@ -257,6 +258,7 @@ func executeConcurrentSymDifferenceAnalysis(tileGroups []GroupTileFiles, resultL
 
 	// 等待结果收集完成
 	resultWg.Wait()
+	releaseTaskResults(results)
 
 	if processingError != nil {
 		return processingError
@@ -308,6 +310,7 @@ func processTileGroupforSymDifference(tileGroup GroupTileFiles, config *Parallel
 	// 加载layer2的bin文件
 	methodTileLayer, err := DeserializeLayerFromFile(tileGroup.GPBin.Layer2)
 	if err != nil {
+		inputTileLayer.Close()
 		return nil, fmt.Errorf("加载擦除分块文件失败: %v", err)
 	}
 	defer func() {
@@ -346,7 +349,7 @@ func createSymDifferenceTileResultLayer(inputLayer, methodLayer *GDALLayer, laye
 	}
 
 	resultLayer := &GDALLayer{layer: resultLayerPtr}
-	runtime.SetFinalizer(resultLayer, (*GDALLayer).cleanup)
+	manageLayer(resultLayer)
 
 	// 添加字段定义 - 使用默认策略（合并字段，带前缀区分来源）
 	err := addSymDifferenceFields(resultLayer, inputLayer, methodLayer)
@@ -376,7 +379,7 @@ func createSymDifferenceResultLayer(layer1, layer2 *GDALLayer) (*GDALLayer, erro
 	}
 
 	resultLayer := &GDALLayer{layer: resultLayerPtr}
-	runtime.SetFinalizer(resultLayer, (*GDALLayer).cleanup)
+	manageLayer(resultLayer)
 
 	// 添加字段定义 - 使用默认策略（合并字段，带前缀区分来源）
 	err := addSymDifferenceFields(resultLayer, layer1, layer2)
@@ -696,6 +699,7 @@ func ExecuteConcurrentSymDifferenceAnalysisPG(tileGroups []GroupTileFiles, resul
 				if err != nil {
 					processingError = fmt.Errorf("合并分块 %d 结果失败: %v", result.index, err)
 					log.Printf("错误: %v", processingError)
+					result.layer.Close()
 					return
 				}
 				// 释放临时图层资源
@@ -726,6 +730,7 @@ func ExecuteConcurrentSymDifferenceAnalysisPG(tileGroups []GroupTileFiles, resul
 	close(results)
 	// 等待结果收集完成
 	resultWg.Wait()
+	releaseTaskResults(results)
 	if processingError != nil {
 		return processingError
 	}
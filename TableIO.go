@@ -0,0 +1,852 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+/*
+#include "osgeo_utils.h"
+*/
+import "C"
+import (
+	"bytes"
+	"encoding/csv"
+	"fmt"
+	"io"
+	"math"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"unsafe"
+
+	"golang.org/x/text/encoding/simplifiedchinese"
+	"golang.org/x/text/transform"
+)
+
+// ============================================================================
+// CSV / Excel 表格导入导出
+// 导入：按经纬度、高斯X/Y或WKT列生成点（或任意几何）图层，其余列自动推断类型；
+// 导出：将图层属性表写为CSV/XLSX，表头优先使用字段别名（GDB中文别名）。
+// ============================================================================
+
+// TableImportOptions 表格导入选项
+type TableImportOptions struct {
+	Sheet     string // XLSX工作表名，默认第一个工作表
+	Encoding  string // CSV编码："UTF-8"、"GBK"、"GB18030"，为空时自动检测
+	Delimiter rune   // CSV分隔符，默认逗号
+	XColumn   string // 经度/东坐标列名，为空时按常见表头自动识别
+	YColumn   string // 纬度/北坐标列名，为空时按常见表头自动识别
+	WKTColumn string // WKT几何列名，指定后忽略X/Y列
+	EPSG      int    // 坐标系，为0时自动判断：经纬度使用CGCS2000(4490)，高斯坐标按带号前缀推断
+	SwapXY    bool   // 交换X/Y列（测量习惯中X为北坐标）
+	LayerName string // 图层名，默认使用文件名
+}
+
+// TableRowError 无法解析的行
+type TableRowError struct {
+	Row    int    // 表格中的行号（表头为第1行）
+	Column string // 出错的列
+	Value  string // 原始值
+	Reason string // 错误原因
+}
+
+// TableImportResult 表格导入结果
+type TableImportResult struct {
+	Layer    *GDALLayer
+	EPSG     int             // 实际使用的坐标系
+	Total    int             // 数据行总数（不含表头和空行）
+	Imported int             // 成功导入的行数
+	Errors   []TableRowError // 无法解析的行
+}
+
+// ErrorReport 生成无法解析行的文字报告
+func (r *TableImportResult) ErrorReport() string {
+	var sb strings.Builder
+	sb.WriteString(fmt.Sprintf("共 %d 行，成功导入 %d 行，失败 %d 行\n", r.Total, r.Imported, len(r.Errors)))
+	for _, e := range r.Errors {
+		sb.WriteString(fmt.Sprintf("第%d行 [%s] \"%s\": %s\n", e.Row, e.Column, e.Value, e.Reason))
+	}
+	return sb.String()
+}
+
+// 常见坐标列表头（统一小写比较）
+var (
+	tableXHeaders   = []string{"经度", "lon", "lng", "long", "longitude", "x", "x坐标", "东坐标", "横坐标", "easting", "east"}
+	tableYHeaders   = []string{"纬度", "lat", "latitude", "y", "y坐标", "北坐标", "纵坐标", "northing", "north"}
+	tableWKTHeaders = []string{"wkt", "geometry", "geom", "the_geom", "shape", "几何", "几何图形"}
+)
+
+// ReadTableLayer 读取CSV/XLSX表格并生成图层
+// 坐标无法解析的行不会导入，记录在结果的Errors中；表格中找不到坐标列时返回错误。
+func ReadTableLayer(filePath string, options *TableImportOptions) (*TableImportResult, error) {
+	if options == nil {
+		options = &TableImportOptions{}
+	}
+
+	var header []string
+	var rows [][]string
+	var err error
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".csv", ".txt":
+		header, rows, err = readCSVTable(filePath, options)
+	case ".xlsx":
+		header, rows, err = readXLSXTable(filePath, options.Sheet)
+	default:
+		return nil, fmt.Errorf("不支持的表格类型: %s", filepath.Ext(filePath))
+	}
+	if err != nil {
+		return nil, err
+	}
+	if len(header) == 0 {
+		return nil, fmt.Errorf("表格没有表头: %s", filePath)
+	}
+
+	header = normalizeTableHeader(header)
+	wktCol, xCol, yCol, err := resolveCoordinateColumns(header, options)
+	if err != nil {
+		return nil, err
+	}
+
+	// 解析几何
+	result := &TableImportResult{}
+	geometries := make([]*Geometry, len(rows))
+	var firstX, firstY float64
+	hasPoint := false
+	for i, row := range rows {
+		if isEmptyTableRow(row) {
+			continue
+		}
+		result.Total++
+		rowNumber := i + 2
+
+		if wktCol >= 0 {
+			value := tableCell(row, wktCol)
+			geom, err := NewGeometryFromWKT(value)
+			if err != nil {
+				result.Errors = append(result.Errors, TableRowError{Row: rowNumber, Column: header[wktCol], Value: value, Reason: "无法解析WKT"})
+				continue
+			}
+			geometries[i] = geom
+			continue
+		}
+
+		xValue, yValue := tableCell(row, xCol), tableCell(row, yCol)
+		x, err := parseCoordinateValue(xValue)
+		if err != nil {
+			result.Errors = append(result.Errors, TableRowError{Row: rowNumber, Column: header[xCol], Value: xValue, Reason: err.Error()})
+			continue
+		}
+		y, err := parseCoordinateValue(yValue)
+		if err != nil {
+			result.Errors = append(result.Errors, TableRowError{Row: rowNumber, Column: header[yCol], Value: yValue, Reason: err.Error()})
+			continue
+		}
+		if options.SwapXY {
+			x, y = y, x
+		}
+		if !hasPoint {
+			firstX, firstY, hasPoint = x, y, true
+		}
+		geometries[i] = NewPointGeometry(x, y)
+	}
+
+	// 确定坐标系
+	epsg := options.EPSG
+	if epsg == 0 && wktCol < 0 && hasPoint {
+		if firstX >= -180 && firstX <= 180 && firstY >= -90 && firstY <= 90 {
+			epsg = 4490
+		} else {
+			guess, err := GuessGKZoneFromCoordinate(firstX, firstY)
+			if err != nil {
+				closeGeometries(geometries)
+				return nil, fmt.Errorf("无法确定坐标系，请指定EPSG: %v", err)
+			}
+			epsg = guess.SpatialReference.EPSG
+			if guess.AxisSwapped {
+				for _, geom := range geometries {
+					if geom != nil {
+						x, y := C.OGR_G_GetX(geom.handle(), 0), C.OGR_G_GetY(geom.handle(), 0)
+						C.OGR_G_SetPoint_2D(geom.handle(), 0, y, x)
+					}
+				}
+			}
+		}
+	}
+	result.EPSG = epsg
+
+	layerName := options.LayerName
+	if layerName == "" {
+		layerName = strings.TrimSuffix(filepath.Base(filePath), filepath.Ext(filePath))
+	}
+
+	layer, err := buildTableLayer(layerName, header, rows, geometries, wktCol, epsg)
+	closeGeometries(geometries)
+	if err != nil {
+		return nil, err
+	}
+	result.Layer = layer
+	result.Imported = int(layer.GetFeatureCount())
+	return result, nil
+}
+
+// readCSVTable 读取CSV，非UTF-8内容按GB18030（兼容GBK）解码
+func readCSVTable(filePath string, options *TableImportOptions) ([]string, [][]string, error) {
+	data, err := os.ReadFile(filePath)
+	if err != nil {
+		return nil, nil, fmt.Errorf("无法读取文件: %v", err)
+	}
+	data = bytes.TrimPrefix(data, []byte("\xef\xbb\xbf"))
+
+	encoding := strings.ToUpper(options.Encoding)
+	if encoding == "" && !utf8.Valid(data) {
+		encoding = "GB18030"
+	}
+	switch encoding {
+	case "", "UTF-8", "UTF8":
+	case "GBK", "GB2312", "GB18030", "CP936":
+		data, err = simplifiedchinese.GB18030.NewDecoder().Bytes(data)
+		if err != nil {
+			return nil, nil, fmt.Errorf("按%s解码失败: %v", encoding, err)
+		}
+	default:
+		return nil, nil, fmt.Errorf("不支持的编码: %s", options.Encoding)
+	}
+
+	reader := csv.NewReader(bytes.NewReader(data))
+	if options.Delimiter != 0 {
+		reader.Comma = options.Delimiter
+	}
+	reader.FieldsPerRecord = -1
+	reader.LazyQuotes = true
+
+	records, err := reader.ReadAll()
+	if err != nil {
+		return nil, nil, fmt.Errorf("解析CSV失败: %v", err)
+	}
+	if len(records) == 0 {
+		return nil, nil, nil
+	}
+	return records[0], records[1:], nil
+}
+
+// readXLSXTable 通过GDAL的XLSX驱动读取工作表，所有单元格按字符串读取
+func readXLSXTable(filePath string, sheet string) ([]string, [][]string, error) {
+	cFilePath := C.CString(filePath)
+	defer C.free(unsafe.Pointer(cFilePath))
+	cDriver := C.CString("XLSX")
+	defer C.free(unsafe.Pointer(cDriver))
+	cHeaders := C.CString("HEADERS=FORCE")
+	defer C.free(unsafe.Pointer(cHeaders))
+	cFieldTypes := C.CString("FIELD_TYPES=STRING")
+	defer C.free(unsafe.Pointer(cFieldTypes))
+
+	// 通过打开选项指定首行为表头、全部按字符串读取，不修改进程级配置
+	drivers := C.CSLAddString(nil, cDriver)
+	defer C.CSLDestroy(drivers)
+	openOptions := C.CSLAddString(nil, cHeaders)
+	openOptions = C.CSLAddString(openOptions, cFieldTypes)
+	defer C.CSLDestroy(openOptions)
+
+	dataset := C.OGRDataSourceH(C.GDALOpenEx(cFilePath, C.GDAL_OF_VECTOR, drivers, openOptions, nil))
+	if dataset == nil {
+		return nil, nil, fmt.Errorf("无法打开XLSX文件: %s", filePath)
+	}
+	defer C.OGR_DS_Destroy(dataset)
+
+	var layer C.OGRLayerH
+	if sheet != "" {
+		cSheet := C.CString(sheet)
+		defer C.free(unsafe.Pointer(cSheet))
+		layer = C.OGR_DS_GetLayerByName(dataset, cSheet)
+	} else if C.OGR_DS_GetLayerCount(dataset) > 0 {
+		layer = C.OGR_DS_GetLayer(dataset, C.int(0))
+	}
+	if layer == nil {
+		return nil, nil, fmt.Errorf("无法获取工作表: %s", sheet)
+	}
+
+	defn := C.OGR_L_GetLayerDefn(layer)
+	fieldCount := int(C.OGR_FD_GetFieldCount(defn))
+	header := make([]string, fieldCount)
+	for i := 0; i < fieldCount; i++ {
+		header[i] = C.GoString(C.OGR_Fld_GetNameRef(C.OGR_FD_GetFieldDefn(defn, C.int(i))))
+	}
+
+	var rows [][]string
+	C.OGR_L_ResetReading(layer)
+	for {
+		feature := C.OGR_L_GetNextFeature(layer)
+		if feature == nil {
+			break
+		}
+		row := make([]string, fieldCount)
+		for i := 0; i < fieldCount; i++ {
+			if C.OGR_F_IsFieldSetAndNotNull(feature, C.int(i)) != 0 {
+				row[i] = C.GoString(C.OGR_F_GetFieldAsString(feature, C.int(i)))
+			}
+		}
+		rows = append(rows, row)
+		C.OGR_F_Destroy(feature)
+	}
+	return header, rows, nil
+}
+
+// normalizeTableHeader 去除表头空白，为空表头和重复表头生成唯一列名
+func normalizeTableHeader(header []string) []string {
+	result := make([]string, len(header))
+	seen := make(map[string]int)
+	for i, name := range header {
+		name = strings.TrimSpace(strings.TrimPrefix(name, "\ufeff"))
+		if name == "" {
+			name = fmt.Sprintf("column_%d", i+1)
+		}
+		if count := seen[name]; count > 0 {
+			seen[name]++
+			name = fmt.Sprintf("%s_%d", name, count+1)
+		} else {
+			seen[name] = 1
+		}
+		result[i] = name
+	}
+	return result
+}
+
+// resolveCoordinateColumns 确定WKT列或X/Y列的位置
+func resolveCoordinateColumns(header []string, options *TableImportOptions) (wktCol, xCol, yCol int, err error) {
+	find := func(name string) int {
+		for i, h := range header {
+			if h == name {
+				return i
+			}
+		}
+		return -1
+	}
+	detect := func(candidates []string) int {
+		for _, candidate := range candidates {
+			for i, h := range header {
+				if strings.ToLower(h) == candidate {
+					return i
+				}
+			}
+		}
+		return -1
+	}
+
+	if options.WKTColumn != "" {
+		if wktCol = find(options.WKTColumn); wktCol < 0 {
+			return -1, -1, -1, fmt.Errorf("未找到WKT列: %s", options.WKTColumn)
+		}
+		return wktCol, -1, -1, nil
+	}
+
+	xCol, yCol = detect(tableXHeaders), detect(tableYHeaders)
+	if options.XColumn != "" {
+		if xCol = find(options.XColumn); xCol < 0 {
+			return -1, -1, -1, fmt.Errorf("未找到X列: %s", options.XColumn)
+		}
+	}
+	if options.YColumn != "" {
+		if yCol = find(options.YColumn); yCol < 0 {
+			return -1, -1, -1, fmt.Errorf("未找到Y列: %s", options.YColumn)
+		}
+	}
+	if xCol >= 0 && yCol >= 0 && xCol != yCol {
+		return -1, xCol, yCol, nil
+	}
+
+	if options.XColumn == "" && options.YColumn == "" {
+		if wktCol = detect(tableWKTHeaders); wktCol >= 0 {
+			return wktCol, -1, -1, nil
+		}
+	}
+	return -1, -1, -1, fmt.Errorf("未找到坐标列，请指定XColumn/YColumn或WKTColumn")
+}
+
+func tableCell(row []string, index int) string {
+	if index < 0 || index >= len(row) {
+		return ""
+	}
+	return strings.TrimSpace(row[index])
+}
+
+func isEmptyTableRow(row []string) bool {
+	for _, value := range row {
+		if strings.TrimSpace(value) != "" {
+			return false
+		}
+	}
+	return true
+}
+
+var dmsNumberPattern = regexp.MustCompile(`\d+(?:\.\d+)?`)
+
+// parseCoordinateValue 解析坐标值，支持十进制和度分秒（如 116°23'45.6"E）
+func parseCoordinateValue(value string) (float64, error) {
+	if value == "" {
+		return 0, fmt.Errorf("坐标为空")
+	}
+	if v, err := strconv.ParseFloat(value, 64); err == nil && !math.IsInf(v, 0) && !math.IsNaN(v) {
+		return v, nil
+	}
+
+	parts := dmsNumberPattern.FindAllString(value, -1)
+	if len(parts) == 0 || len(parts) > 3 || !strings.ContainsAny(value, "°度") {
+		return 0, fmt.Errorf("无法解析坐标")
+	}
+	result := 0.0
+	for i, part := range parts {
+		v, _ := strconv.ParseFloat(part, 64)
+		result += v / math.Pow(60, float64(i))
+	}
+	upper := strings.ToUpper(value)
+	if strings.HasPrefix(value, "-") || strings.ContainsAny(upper, "SW") ||
+		strings.Contains(value, "南") || strings.Contains(value, "西") {
+		result = -result
+	}
+	return result, nil
+}
+
+func closeGeometries(geometries []*Geometry) {
+	for _, geom := range geometries {
+		geom.Close()
+	}
+}
+
+// tableColumnType 推断出的列类型
+type tableColumnType struct {
+	fieldType C.OGRFieldType
+	width     int
+}
+
+var (
+	tableIntegerPattern = regexp.MustCompile(`^[-+]?(0|[1-9]\d*)$`)
+	tableRealPattern    = regexp.MustCompile(`^[-+]?(\d+\.?\d*|\.\d+)([eE][-+]?\d+)?$`)
+	tableDateLayouts    = []string{"2006-01-02", "2006/01/02", "2006.01.02", "2006/1/2", "2006-1-2"}
+	tableTimeLayouts    = []string{"2006-01-02 15:04:05", "2006/01/02 15:04:05", "2006-01-02T15:04:05", "2006/1/2 15:04:05", "2006/1/2 15:04", "2006-01-02 15:04"}
+)
+
+// inferTableColumnType 根据列中所有非空值推断字段类型
+// 以0开头的数字串（如行政区划代码、编号）保持为字符串
+func inferTableColumnType(rows [][]string, col int) tableColumnType {
+	isInteger, isReal, isDate, isDateTime := true, true, true, true
+	hasValue := false
+	width := 0
+	for _, row := range rows {
+		value := tableCell(row, col)
+		if value == "" {
+			continue
+		}
+		hasValue = true
+		if n := utf8.RuneCountInString(value); n > width {
+			width = n
+		}
+		if isInteger && !tableIntegerPattern.MatchString(value) {
+			isInteger = false
+		}
+		if isInteger {
+			if _, err := strconv.ParseInt(value, 10, 64); err != nil {
+				isInteger = false
+			}
+		}
+		if isReal && (!tableRealPattern.MatchString(value) || (len(value) > 1 && value[0] == '0' && value[1] != '.')) {
+			isReal = false
+		}
+		if isDate {
+			if _, ok := parseTableTime(value, tableDateLayouts); !ok {
+				isDate = false
+			}
+		}
+		if isDateTime {
+			if _, ok := parseTableTime(value, tableTimeLayouts); !ok {
+				isDateTime = false
+			}
+		}
+	}
+
+	switch {
+	case !hasValue:
+		return tableColumnType{fieldType: C.OFTString}
+	case isInteger:
+		for _, row := range rows {
+			if v, err := strconv.ParseInt(tableCell(row, col), 10, 64); err == nil && (v > math.MaxInt32 || v < math.MinInt32) {
+				return tableColumnType{fieldType: C.OFTInteger64}
+			}
+		}
+		return tableColumnType{fieldType: C.OFTInteger}
+	case isReal:
+		return tableColumnType{fieldType: C.OFTReal}
+	case isDate:
+		return tableColumnType{fieldType: C.OFTDate}
+	case isDateTime:
+		return tableColumnType{fieldType: C.OFTDateTime}
+	default:
+		if width > 254 {
+			width = 0
+		}
+		return tableColumnType{fieldType: C.OFTString, width: width}
+	}
+}
+
+func parseTableTime(value string, layouts []string) (time.Time, bool) {
+	for _, layout := range layouts {
+		if t, err := time.ParseInLocation(layout, value, time.Local); err == nil {
+			return t, true
+		}
+	}
+	return time.Time{}, false
+}
+
+// buildTableLayer 创建内存图层并写入表格数据，geometries中为nil的行跳过
+func buildTableLayer(layerName string, header []string, rows [][]string, geometries []*Geometry, wktCol int, epsg int) (*GDALLayer, error) {
+	geomType := C.OGRwkbGeometryType(C.wkbPoint)
+	if wktCol >= 0 {
+		geomType = C.wkbUnknown
+		for _, geom := range geometries {
+			if geom == nil {
+				continue
+			}
+			t := C.OGR_G_GetGeometryType(geom.handle())
+			if geomType == C.wkbUnknown {
+				geomType = t
+			} else if geomType != t {
+				geomType = C.wkbUnknown
+				break
+			}
+		}
+	}
+
+	var srs C.OGRSpatialReferenceH
+	if epsg > 0 {
+		srs = CreateSpatialReferenceFromEPSG(epsg)
+		if srs == nil {
+			return nil, fmt.Errorf("无效的EPSG代码: %d", epsg)
+		}
+		C.OSRSetAxisMappingStrategy(srs, C.OAMS_TRADITIONAL_GIS_ORDER)
+		defer C.OSRRelease(srs)
+	}
+
+	layer, err := newMemoryResultLayer("table_import", layerName, srs, geomType)
+	if err != nil {
+		return nil, err
+	}
+
+	// 字段定义：列序号 -> 字段序号
+	columnTypes := make([]tableColumnType, len(header))
+	fieldIndexes := make([]C.int, len(header))
+	fieldIndex := C.int(0)
+	for col, name := range header {
+		fieldIndexes[col] = -1
+		if col == wktCol {
+			continue
+		}
+		columnTypes[col] = inferTableColumnType(rows, col)
+
+		cName := C.CString(name)
+		fieldDefn := C.OGR_Fld_Create(cName, columnTypes[col].fieldType)
+		C.free(unsafe.Pointer(cName))
+		if columnTypes[col].width > 0 {
+			C.OGR_Fld_SetWidth(fieldDefn, C.int(columnTypes[col].width))
+		}
+		result := C.OGR_L_CreateField(layer.layer, fieldDefn, C.int(1))
+		C.OGR_Fld_Destroy(fieldDefn)
+		if result != C.OGRERR_NONE {
+			layer.Close()
+			return nil, fmt.Errorf("无法创建字段: %s", name)
+		}
+		fieldIndexes[col] = fieldIndex
+		fieldIndex++
+	}
+
+	layerDefn := C.OGR_L_GetLayerDefn(layer.layer)
+	for i, row := range rows {
+		if geometries[i] == nil {
+			continue
+		}
+
+		feature := C.OGR_F_Create(layerDefn)
+		C.OGR_F_SetGeometry(feature, geometries[i].handle())
+		for col := range header {
+			value := tableCell(row, col)
+			if fieldIndexes[col] < 0 || value == "" {
+				continue
+			}
+			setTableFieldValue(feature, fieldIndexes[col], columnTypes[col].fieldType, value)
+		}
+		result := C.OGR_L_CreateFeature(layer.layer, feature)
+		C.OGR_F_Destroy(feature)
+		if result != C.OGRERR_NONE {
+			layer.Close()
+			return nil, fmt.Errorf("写入第%d行失败", i+2)
+		}
+	}
+	return layer, nil
+}
+
+// setTableFieldValue 按推断类型设置字段值
+func setTableFieldValue(feature C.OGRFeatureH, index C.int, fieldType C.OGRFieldType, value string) {
+	switch fieldType {
+	case C.OFTInteger, C.OFTInteger64:
+		v, _ := strconv.ParseInt(value, 10, 64)
+		C.OGR_F_SetFieldInteger64(feature, index, C.GIntBig(v))
+	case C.OFTReal:
+		v, _ := strconv.ParseFloat(value, 64)
+		C.OGR_F_SetFieldDouble(feature, index, C.double(v))
+	case C.OFTDate:
+		t, _ := parseTableTime(value, tableDateLayouts)
+		C.OGR_F_SetFieldDateTime(feature, index, C.int(t.Year()), C.int(t.Month()), C.int(t.Day()), 0, 0, 0, 0)
+	case C.OFTDateTime:
+		t, _ := parseTableTime(value, tableTimeLayouts)
+		setFeatureDateTime(feature, index, t)
+	default:
+		cValue := C.CString(value)
+		C.OGR_F_SetFieldString(feature, index, cValue)
+		C.free(unsafe.Pointer(cValue))
+	}
+}
+
+// ============================================================================
+// 属性表导出
+// ============================================================================
+
+// TableExportOptions 属性表导出选项
+type TableExportOptions struct {
+	Sheet         string            // XLSX工作表名，默认图层名
+	Encoding      string            // CSV编码："UTF-8"（默认，带BOM便于Excel识别）、"UTF-8-NOBOM"、"GBK"、"GB18030"
+	Delimiter     rune              // CSV分隔符，默认逗号
+	FieldAliases  map[string]string // 字段名 -> 表头，优先于字段自带的别名（可传入GDBLayerMetaData.GetFieldAliasMap()）
+	UseFieldNames bool              // 表头使用字段名而不是别名
+	WKTColumn     string            // 非空时追加该名称的WKT几何列
+}
+
+// ExportLayerTable 将图层属性表导出为CSV或XLSX（按扩展名判断）
+func ExportLayerTable(layer *GDALLayer, filePath string, options *TableExportOptions) error {
+	if layer == nil || layer.layer == nil {
+		return fmt.Errorf("图层为空")
+	}
+	if options == nil {
+		options = &TableExportOptions{}
+	}
+
+	switch strings.ToLower(filepath.Ext(filePath)) {
+	case ".csv", ".txt":
+		return exportCSVTable(layer, filePath, options)
+	case ".xlsx":
+		return exportXLSXTable(layer, filePath, options)
+	default:
+		return fmt.Errorf("不支持的表格类型: %s", filepath.Ext(filePath))
+	}
+}
+
+// tableExportHeader 生成导出表头：自定义别名 > 字段别名 > 字段名
+func tableExportHeader(defn C.OGRFeatureDefnH, options *TableExportOptions) []string {
+	fieldCount := int(C.OGR_FD_GetFieldCount(defn))
+	header := make([]string, 0, fieldCount+1)
+	for i := 0; i < fieldCount; i++ {
+		fieldDefn := C.OGR_FD_GetFieldDefn(defn, C.int(i))
+		name := C.GoString(C.OGR_Fld_GetNameRef(fieldDefn))
+		title := name
+		if !options.UseFieldNames {
+			if alias, ok := options.FieldAliases[name]; ok && alias != "" {
+				title = alias
+			} else if alias := C.OGR_Fld_GetAlternativeNameRef(fieldDefn); alias != nil && C.GoString(alias) != "" {
+				title = C.GoString(alias)
+			}
+		}
+		header = append(header, title)
+	}
+	if options.WKTColumn != "" {
+		header = append(header, options.WKTColumn)
+	}
+	return normalizeTableHeader(header)
+}
+
+// exportCSVTable 导出CSV
+func exportCSVTable(layer *GDALLayer, filePath string, options *TableExportOptions) error {
+	file, err := os.Create(filePath)
+	if err != nil {
+		return fmt.Errorf("无法创建文件: %v", err)
+	}
+	defer file.Close()
+
+	var out io.Writer = file
+	var encoder io.WriteCloser // 转码写入器，需在关闭文件前关闭以写出缓冲的字节
+	switch strings.ToUpper(options.Encoding) {
+	case "", "UTF-8", "UTF8":
+		if _, err := file.WriteString("\xef\xbb\xbf"); err != nil {
+			return fmt.Errorf("写入文件失败: %v", err)
+		}
+	case "UTF-8-NOBOM":
+	case "GBK", "GB2312", "GB18030", "CP936":
+		encoder = transform.NewWriter(file, simplifiedchinese.GB18030.NewEncoder())
+		out = encoder
+	default:
+		return fmt.Errorf("不支持的编码: %s", options.Encoding)
+	}
+
+	writer := csv.NewWriter(out)
+	if options.Delimiter != 0 {
+		writer.Comma = options.Delimiter
+	}
+
+	defn := layer.GetLayerDefn()
+	if err := writer.Write(tableExportHeader(defn, options)); err != nil {
+		return fmt.Errorf("写入表头失败: %v", err)
+	}
+
+	fieldCount := int(C.OGR_FD_GetFieldCount(defn))
+	layer.ResetReading()
+	for {
+		feature := layer.GetNextFeatureRow()
+		if feature == nil {
+			break
+		}
+		record := make([]string, 0, fieldCount+1)
+		for i := 0; i < fieldCount; i++ {
+			value := ""
+			if C.OGR_F_IsFieldSetAndNotNull(feature, C.int(i)) != 0 {
+				value = C.GoString(C.OGR_F_GetFieldAsString(feature, C.int(i)))
+			}
+			record = append(record, value)
+		}
+		if options.WKTColumn != "" {
+			record = append(record, GeometryToWKT(C.OGR_F_GetGeometryRef(feature)))
+		}
+		C.OGR_F_Destroy(feature)
+
+		if err := writer.Write(record); err != nil {
+			return fmt.Errorf("写入数据失败: %v", err)
+		}
+	}
+
+	writer.Flush()
+	if err := writer.Error(); err != nil {
+		return fmt.Errorf("写入数据失败: %v", err)
+	}
+	if encoder != nil {
+		if err := encoder.Close(); err != nil {
+			return fmt.Errorf("写入数据失败: %v", err)
+		}
+	}
+	if err := file.Close(); err != nil {
+		return fmt.Errorf("关闭文件失败: %v", err)
+	}
+	return nil
+}
+
+// exportXLSXTable 通过GDAL的XLSX驱动导出工作表，保留数值和日期类型
+func exportXLSXTable(layer *GDALLayer, filePath string, options *TableExportOptions) error {
+	if _, err := os.Stat(filePath); err == nil {
+		if err := os.Remove(filePath); err != nil {
+			return fmt.Errorf("无法删除已存在的文件: %v", err)
+		}
+	}
+
+	cDriverName := C.CString("XLSX")
+	defer C.free(unsafe.Pointer(cDriverName))
+	driver := C.OGRGetDriverByName(cDriverName)
+	if driver == nil {
+		return fmt.Errorf("无法获取XLSX驱动")
+	}
+
+	cFilePath := C.CString(filePath)
+	defer C.free(unsafe.Pointer(cFilePath))
+
+	dataset := C.OGR_Dr_CreateDataSource(driver, cFilePath, nil)
+	if dataset == nil {
+		return fmt.Errorf("无法创建XLSX文件: %s", filePath)
+	}
+	defer C.OGR_DS_Destroy(dataset)
+
+	sheet := options.Sheet
+	if sheet == "" {
+		sheet = layer.GetLayerName()
+	}
+	cSheet := C.CString(sheet)
+	defer C.free(unsafe.Pointer(cSheet))
+
+	sheetLayer := C.OGR_DS_CreateLayer(dataset, cSheet, nil, C.wkbNone, nil)
+	if sheetLayer == nil {
+		return fmt.Errorf("无法创建工作表: %s", sheet)
+	}
+
+	defn := layer.GetLayerDefn()
+	header := tableExportHeader(defn, options)
+	fieldCount := int(C.OGR_FD_GetFieldCount(defn))
+	fieldTypes := make([]C.OGRFieldType, len(header))
+	for i, title := range header {
+		fieldType := C.OGRFieldType(C.OFTString)
+		if i < fieldCount {
+			switch t := C.OGR_Fld_GetType(C.OGR_FD_GetFieldDefn(defn, C.int(i))); t {
+			case C.OFTInteger, C.OFTInteger64, C.OFTReal, C.OFTDate, C.OFTDateTime:
+				fieldType = t
+			}
+		}
+		fieldTypes[i] = fieldType
+
+		cTitle := C.CString(title)
+		fieldDefn := C.OGR_Fld_Create(cTitle, fieldType)
+		C.free(unsafe.Pointer(cTitle))
+		result := C.OGR_L_CreateField(sheetLayer, fieldDefn, C.int(1))
+		C.OGR_Fld_Destroy(fieldDefn)
+		if result != C.OGRERR_NONE {
+			return fmt.Errorf("无法创建列: %s", title)
+		}
+	}
+
+	sheetDefn := C.OGR_L_GetLayerDefn(sheetLayer)
+	layer.ResetReading()
+	for {
+		feature := layer.GetNextFeatureRow()
+		if feature == nil {
+			break
+		}
+
+		row := C.OGR_F_Create(sheetDefn)
+		for i := 0; i < fieldCount; i++ {
+			if C.OGR_F_IsFieldSetAndNotNull(feature, C.int(i)) == 0 {
+				continue
+			}
+			switch fieldTypes[i] {
+			case C.OFTInteger, C.OFTInteger64:
+				C.OGR_F_SetFieldInteger64(row, C.int(i), C.OGR_F_GetFieldAsInteger64(feature, C.int(i)))
+			case C.OFTReal:
+				C.OGR_F_SetFieldDouble(row, C.int(i), C.OGR_F_GetFieldAsDouble(feature, C.int(i)))
+			case C.OFTDate, C.OFTDateTime:
+				if t, ok := featureDateTime(feature, C.int(i)); ok {
+					setFeatureDateTime(row, C.int(i), t)
+				}
+			default:
+				C.OGR_F_SetFieldString(row, C.int(i), C.OGR_F_GetFieldAsString(feature, C.int(i)))
+			}
+		}
+		if options.WKTColumn != "" {
+			cWKT := C.CString(GeometryToWKT(C.OGR_F_GetGeometryRef(feature)))
+			C.OGR_F_SetFieldString(row, C.int(fieldCount), cWKT)
+			C.free(unsafe.Pointer(cWKT))
+		}
+		C.OGR_F_Destroy(feature)
+
+		result := C.OGR_L_CreateFeature(sheetLayer, row)
+		C.OGR_F_Destroy(row)
+		if result != C.OGRERR_NONE {
+			return fmt.Errorf("写入数据失败")
+		}
+	}
+	return nil
+}
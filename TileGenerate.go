@@ -542,8 +542,12 @@ int groupAndSerializeByTiles(OGRLayerH hClippedLayer, double* minXs, double* min
 // 在C代码部分添加新函数
 
 // 创建瓦片图层，包含clip_index字段
+// 瓦片图层所属的数据源通过phTileDS返回，由调用者销毁
 OGRLayerH createTileLayer(double* minXs, double* minYs, double* maxXs, double* maxYs,
-                         int* tileIndices, int tileCount, OGRSpatialReferenceH hSRS) {
+                         int* tileIndices, int tileCount, OGRSpatialReferenceH hSRS,
+                         OGRDataSourceH* phTileDS) {
+    *phTileDS = NULL;
+
     // 创建内存数据源
     OGRSFDriverH hDriver = OGRGetDriverByName("MEM");
     if (!hDriver) return NULL;
@@ -591,6 +595,7 @@ OGRLayerH createTileLayer(double* minXs, double* minYs, double* maxXs, double* m
         OGR_G_DestroyGeometry(hTileGeom);
     }
 
+    *phTileDS = hTileDS;
     return hTileLayer;
 }
 
@@ -605,20 +610,28 @@ int clipAndGroupByTilesOptimized(OGRLayerH hInputLayer, double* minXs, double* m
     OGRwkbGeometryType geomType = OGR_L_GetGeomType(hInputLayer);
 
     // 创建瓦片图层
-    OGRLayerH hTileLayer = createTileLayer(minXs, minYs, maxXs, maxYs, tileIndices, tileCount, hSRS);
+    OGRDataSourceH hTileDS = NULL;
+    OGRLayerH hTileLayer = createTileLayer(minXs, minYs, maxXs, maxYs, tileIndices, tileCount, hSRS, &hTileDS);
     if (!hTileLayer) return 0;
 
     // 创建输出数据源用于存储裁剪结果
     OGRSFDriverH hDriver = OGRGetDriverByName("MEM");
-    if (!hDriver) return 0;
+    if (!hDriver) {
+        OGR_DS_Destroy(hTileDS);
+        return 0;
+    }
 
     OGRDataSourceH hOutputDS = OGR_Dr_CreateDataSource(hDriver, "output", NULL);
-    if (!hOutputDS) return 0;
+    if (!hOutputDS) {
+        OGR_DS_Destroy(hTileDS);
+        return 0;
+    }
 
     // 创建裁剪结果图层
     OGRLayerH hClippedLayer = OGR_DS_CreateLayer(hOutputDS, "clipped", hSRS, geomType, NULL);
     if (!hClippedLayer) {
         OGR_DS_Destroy(hOutputDS);
+        OGR_DS_Destroy(hTileDS);
         return 0;
     }
 
@@ -638,6 +651,7 @@ int clipAndGroupByTilesOptimized(OGRLayerH hInputLayer, double* minXs, double* m
 
     // 执行交集操作 - 这是关键的一次性裁剪
     OGRErr eErr = OGR_L_Intersection(hInputLayer, hTileLayer, hClippedLayer, NULL, NULL, NULL);
+    OGR_DS_Destroy(hTileDS);
     if (eErr != OGRERR_NONE) {
         OGR_DS_Destroy(hOutputDS);
         return 0;
@@ -659,6 +673,12 @@ int clipAndGroupByTilesOptimized(OGRLayerH hInputLayer, double* minXs, double* m
 
     // 创建索引映射
     int* indexMap = (int*)malloc(sizeof(int) * tileCount);
+    if (!indexMap) {
+        free(tileLayers);
+        free(tileDatasources);
+        OGR_DS_Destroy(hOutputDS);
+        return 0;
+    }
     for (int i = 0; i < tileCount; i++) {
         indexMap[i] = tileIndices[i];
 
@@ -977,8 +997,8 @@ func (p *OptimizedTileProcessor) Cleanup() {
 	defer p.mutex.Unlock()
 
 	if p.clippedLayer != nil && p.clippedLayer.layer != nil {
-		// 这里需要清理C层面的资源
-		// 注意：由于是MEM驱动创建的，需要销毁整个数据源
+		// 由于是MEM驱动创建的，需要销毁整个数据源
+		p.clippedLayer.Close()
 		p.clippedLayer = nil
 	}
 }
@@ -1542,11 +1562,15 @@ func mapGeometryColumnTypeToOGR(geomType string) C.OGRwkbGeometryType {
 
 func createLayerFromPGQuery(db *gorm.DB, query string, sourceTable string, srid int) (*GDALLayer, error) {
 	// 创建内存数据源
-	driver := C.OGRGetDriverByName(C.CString("Memory"))
+	driverName := C.CString("Memory")
+	defer C.free(unsafe.Pointer(driverName))
+	driver := C.OGRGetDriverByName(driverName)
 	if driver == nil {
 		return nil, fmt.Errorf("无法获取Memory驱动")
 	}
-	ds := C.OGR_Dr_CreateDataSource(driver, C.CString(""), nil)
+	dsName := C.CString("")
+	defer C.free(unsafe.Pointer(dsName))
+	ds := C.OGR_Dr_CreateDataSource(driver, dsName, nil)
 	if ds == nil {
 		return nil, fmt.Errorf("创建内存数据源失败")
 	}
@@ -1702,11 +1726,8 @@ func createLayerFromPGQuery(db *gorm.DB, query string, sourceTable string, srid
 						if isMultiGeometryType(geomType) {
 							finalGeom = geom
 						} else {
-							// 转换为Multi类型
+							// 转换为Multi类型（原始几何在下方统一销毁）
 							finalGeom = convertToMultiGeometry(geom, multiGeomType)
-							if finalGeom != geom {
-								C.OGR_G_DestroyGeometry(geom) // 销毁原始几何
-							}
 						}
 
 						if finalGeom != nil {
@@ -1749,12 +1770,16 @@ func createLayerFromPGQuery(db *gorm.DB, query string, sourceTable string, srid
 		}
 		C.OGR_F_Destroy(feature)
 	}
+	if err := rows.Err(); err != nil {
+		C.OGR_DS_Destroy(ds)
+		return nil, fmt.Errorf("读取查询结果失败: %v", err)
+	}
 
-	return &GDALLayer{
+	return manageLayer(&GDALLayer{
 		layer:   layer,
 		dataset: ds,
 		driver:  driver,
-	}, nil
+	}), nil
 }
 
 // 辅助函数：检查是否为Multi几何类型
@@ -318,11 +318,11 @@ func (t *RasterTiler) CreateTileLayer() (*GDALLayer, error) {
 	}
 
 	// 创建 GDALLayer 对象（修正字段名）
-	gdalLayer := &GDALLayer{
+	gdalLayer := manageLayer(&GDALLayer{
 		layer:   layer,
 		dataset: dataset, // 修正：使用 dataset 而不是 dataSource
 		driver:  driver,  // 添加：driver 字段
-	}
+	})
 
 	t.geoLayer = gdalLayer
 	return gdalLayer, nil
@@ -657,7 +657,7 @@ func (up *UnionProcessor) createOutputLayer(inputLayer *GDALLayer) (*GDALLayer,
 		driver:  nil,
 	}
 
-	runtime.SetFinalizer(outputLayer, (*GDALLayer).cleanup)
+	manageLayer(outputLayer)
 	return outputLayer, nil
 }
 
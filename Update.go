@@ -221,6 +221,7 @@ func executeConcurrentUpdateAnalysis(tileGroups []GroupTileFiles, resultLayer *G
 				if err != nil {
 					processingError = fmt.Errorf("合并分块 %d 结果失败: %v", result.index, err)
 					log.Printf("错误: %v", processingError)
+					result.layer.Close()
 					return
 				}
 
@@ -260,6 +261,7 @@ func executeConcurrentUpdateAnalysis(tileGroups []GroupTileFiles, resultLayer *G
 
 	// 等待结果收集完成
 	resultWg.Wait()
+	releaseTaskResults(results)
 
 	if processingError != nil {
 		return processingError
@@ -309,6 +311,7 @@ func processTileGroupforUpdate(tileGroup GroupTileFiles, config *ParallelGeosCon
 	// 加载layer2的bin文件
 	methodTileLayer, err := DeserializeLayerFromFile(tileGroup.GPBin.Layer2)
 	if err != nil {
+		inputTileLayer.Close()
 		return nil, fmt.Errorf("加载擦除分块文件失败: %v", err)
 	}
 	defer func() {
@@ -349,7 +352,7 @@ func createUpdateTileResultLayer(layer1, layer2 *GDALLayer, layerName string) (*
 	}
 
 	resultLayer := &GDALLayer{layer: resultLayerPtr}
-	runtime.SetFinalizer(resultLayer, (*GDALLayer).cleanup)
+	manageLayer(resultLayer)
 
 	// 添加字段定义 - 使用默认策略（合并字段，带前缀区分来源）
 	err := addUpdateFields(resultLayer, layer1, layer2)
@@ -378,7 +381,7 @@ func createUpdateAnalysisResultLayer(inputLayer, updateLayer *GDALLayer) (*GDALL
 	}
 
 	resultLayer := &GDALLayer{layer: resultLayerPtr}
-	runtime.SetFinalizer(resultLayer, (*GDALLayer).cleanup)
+	manageLayer(resultLayer)
 
 	// 添加字段定义
 	err := addUpdateFields(resultLayer, inputLayer, updateLayer)
@@ -700,6 +703,7 @@ func ExecuteConcurrentUpdateAnalysisPG(tileGroups []GroupTileFiles, resultLayer
 				if err != nil {
 					processingError = fmt.Errorf("合并分块 %d 结果失败: %v", result.index, err)
 					log.Printf("错误: %v", processingError)
+					result.layer.Close()
 					return
 				}
 				// 释放临时图层资源
@@ -730,6 +734,7 @@ func ExecuteConcurrentUpdateAnalysisPG(tileGroups []GroupTileFiles, resultLayer
 	close(results)
 	// 等待结果收集完成
 	resultWg.Wait()
+	releaseTaskResults(results)
 	if processingError != nil {
 		return processingError
 	}
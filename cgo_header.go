@@ -217,7 +217,9 @@ type SpatialReference struct {
 
 // Geometry 表示几何对象的Go包装器
 type Geometry struct {
-	cPtr C.OGRGeometryH // C语言几何对象指针
+	cPtr     C.OGRGeometryH // C语言几何对象指针
+	borrowed bool           // 是否为借用引用（由要素持有，不负责释放）
+	owner    interface{}    // 借用引用的持有者，防止其先于几何对象被回收
 }
 
 // InitializeGDAL 初始化GDAL库，支持自定义配置
@@ -347,9 +349,8 @@ func CreateGeometryFromWKBHex(wkbHex string) (*Geometry, error) {
 	if cPtr == nil {
 		return nil, errors.New("failed to create geometry from WKB hex string") // 创建失败
 	}
-	// 创建Go包装器对象
-	geom := &Geometry{cPtr: cPtr}
-	return geom, nil // 返回成功创建的对象
+	// 创建Go包装器对象（设置终结器）
+	return wrapGeometry(cPtr), nil
 }
 
 // GetGeometryTypeFromWKBData 从WKB数据中提取几何类型
@@ -366,7 +367,11 @@ func GetGeometryTypeFromWKBData(wkbData []byte) uint32 {
 }
 
 // CleanupGDAL 清理GDAL资源，程序退出前调用
+// 开启资源跟踪时，会先输出仍未关闭的资源及其创建堆栈
 func CleanupGDAL() {
+	if ResourceTrackingEnabled() {
+		ReportResourceLeaks(log.Writer())
+	}
 	C.cleanupGDAL() // 调用C函数清理资源
 }
 
@@ -380,6 +385,11 @@ func (srs *SpatialReference) destroy() {
 
 // destroy 销毁几何对象的C资源（终结器函数）
 func (geom *Geometry) destroy() {
+	if geom.borrowed {
+		geom.cPtr = nil
+		geom.owner = nil
+		return
+	}
 	if geom.cPtr != nil {
 		// 添加错误恢复机制
 		defer func() {
@@ -110,7 +110,7 @@ func mapPostGISTypeToGDB(pgType string) (FieldType, int, int) {
 // parsePostGISType 解析PostgreSQL类型字符串
 func parsePostGISType(pgType string) (baseType string, params []int) {
 	// 使用正则表达式提取类型和参数
-	re := regexp.MustCompile(`^([a-z\s]+)(?:$([^)]+)$)?$`)
+	re := regexp.MustCompile(`^([a-z\s]+)(?:\(([^)]+)\))?$`)
 	matches := re.FindStringSubmatch(pgType)
 
 	if len(matches) < 2 {
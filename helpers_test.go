@@ -0,0 +1,44 @@
+/*
+Copyright (C) 2025 [GrainArc]
+
+This program is free software: you can redistribute it and/or modify
+it under the terms of the GNU Affero General Public License as published
+by the Free Software Foundation, either version 3 of the License, or
+(at your option) any later version.
+
+This program is distributed in the hope that it will be useful,
+but WITHOUT ANY WARRANTY; without even the implied warranty of
+MERCHANTABILITY or FITNESS FOR A PARTICULAR PURPOSE.  See the
+GNU Affero General Public License for more details.
+
+You should have received a copy of the GNU Affero General Public License
+along with this program.  If not, see <https://www.gnu.org/licenses/>.
+*/
+package Gogeo
+
+import (
+	"os"
+	"path/filepath"
+	"sync"
+	"testing"
+)
+
+var initGDALOnce sync.Once
+
+// testGeoJSONLayer 将GeoJSON写入临时文件并读取为图层
+// 测试文件中不能使用cgo，字段类型由GeoJSON驱动根据属性值推断（如整数数组为IntegerList）。
+func testGeoJSONLayer(t *testing.T, geojson string) *GDALLayer {
+	t.Helper()
+	initGDALOnce.Do(func() { InitializeGDAL() })
+
+	path := filepath.Join(t.TempDir(), "layer.geojson")
+	if err := os.WriteFile(path, []byte(geojson), 0644); err != nil {
+		t.Fatalf("写入GeoJSON失败: %v", err)
+	}
+	layer, err := ReadGeoJSONLayer(path)
+	if err != nil {
+		t.Fatalf("读取GeoJSON失败: %v", err)
+	}
+	t.Cleanup(layer.Close)
+	return layer
+}
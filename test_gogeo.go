@@ -304,7 +304,7 @@ func writeLayerToDataset(sourceLayer *GDALLayer, dataset C.OGRDataSourceH, layer
 	}
 
 	// 复制字段定义
-	err := tempWriter.copyFieldDefinitions(sourceDefn, newLayer)
+	err := tempWriter.copyFieldDefinitions(sourceDefn, newLayer, nil)
 	if err != nil {
 		return fmt.Errorf("复制字段定义失败: %v", err)
 	}
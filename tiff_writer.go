@@ -292,6 +292,7 @@ import "C"
 import (
 	"errors"
 	"fmt"
+	"runtime"
 	"sync"
 	"sync/atomic"
 	"unsafe"
@@ -333,7 +334,7 @@ func NewGeoTiffWriterWithSRS(width, height, bands, tileSize int, geoTransform [6
 		cGeoTransform[i] = C.double(geoTransform[i])
 	}
 	C.GDALSetGeoTransform(dataset, &cGeoTransform[0])
-	return &GeoTiffWriter{
+	w := &GeoTiffWriter{
 		dataset:      dataset,
 		width:        width,
 		height:       height,
@@ -344,7 +345,10 @@ func NewGeoTiffWriterWithSRS(width, height, bands, tileSize int, geoTransform [6
 		tileImages:   make([]C.GDALDatasetH, 0),
 		vsimemPaths:  make([]string, 0),
 		memBuffers:   make([]unsafe.Pointer, 0),
-	}, nil
+	}
+	// 未显式Close时由终结器释放数据集与vsimem缓冲区
+	runtime.SetFinalizer(w, (*GeoTiffWriter).Close)
+	return w, nil
 }
 
 // WriteTile 写入瓦片到指定位置
@@ -379,6 +383,7 @@ func (w *GeoTiffWriter) WriteTile(tileData []byte, format string, dstX, dstY int
 	}
 	C.VSIFCloseL(fp)
 	w.vsimemPaths = append(w.vsimemPaths, vsimemPath)
+	trackVSIMem(vsimemPath)
 	// 打开瓦片数据集
 	hTileDS := C.GDALOpen(cVsimemPath, C.GA_ReadOnly)
 	if hTileDS == nil {
@@ -477,6 +482,7 @@ func (w *GeoTiffWriter) Close() {
 		cPath := C.CString(path)
 		C.VSIUnlink(cPath)
 		C.free(unsafe.Pointer(cPath))
+		untrackVSIMem(path)
 	}
 	w.vsimemPaths = nil
 
@@ -493,6 +499,7 @@ func (w *GeoTiffWriter) Close() {
 		C.closeDataset(w.dataset)
 		w.dataset = nil
 	}
+	runtime.SetFinalizer(w, nil)
 }
 
 // GetDimensions 获取尺寸